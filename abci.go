@@ -13,20 +13,24 @@ import (
 
 // DeliverOrError returns an abci response for DeliverTx,
 // converting the error message if present, or using the successful
-// DeliverResult
+// DeliverResult. The gas used is reported in both cases.
 func DeliverOrError(result DeliverResult, err error) abci.ResponseDeliverTx {
 	if err != nil {
-		return DeliverTxError(err)
+		res := DeliverTxError(err)
+		res.GasUsed = result.GasUsed
+		return res
 	}
 	return result.ToABCI()
 }
 
 // CheckOrError returns an abci response for CheckTx,
 // converting the error message if present, or using the successful
-// CheckResult
+// CheckResult. The gas allocated is reported in both cases.
 func CheckOrError(result CheckResult, err error) abci.ResponseCheckTx {
 	if err != nil {
-		return CheckTxError(err)
+		res := CheckTxError(err)
+		res.GasWanted = result.GasAllocated
+		return res
 	}
 	return result.ToABCI()
 }
@@ -40,15 +44,16 @@ type DeliverResult struct {
 	Log     string
 	Diff    []abci.Validator
 	Tags    []common.KVPair
-	GasUsed int64 // unused
+	GasUsed int64 // units of work this tx actually performed
 }
 
 // ToABCI converts our internal type into an abci response
 func (d DeliverResult) ToABCI() abci.ResponseDeliverTx {
 	return abci.ResponseDeliverTx{
-		Data: d.Data,
-		Log:  d.Log,
-		Tags: d.Tags,
+		Data:    d.Data,
+		Log:     d.Log,
+		Tags:    d.Tags,
+		GasUsed: d.GasUsed,
 	}
 }
 
@@ -87,6 +92,13 @@ type TickResult struct {
 	Diff []abci.Validator
 }
 
+// EndBlockResult allows the EndBlocker to modify the validator set
+// and tag the block
+type EndBlockResult struct {
+	Diff []abci.Validator
+	Tags []common.KVPair
+}
+
 //---------- type safe error converters --------
 
 // DeliverTxError converts any error into a abci.ResponseDeliverTx,
@@ -49,11 +49,17 @@ func TestCreateErrorResult(t *testing.T) {
 
 func TestCreateResults(t *testing.T) {
 	d, msg := []byte{1, 3, 4}, "got it"
-	dres := weave.DeliverResult{Data: d, Log: msg}
+	dres := weave.DeliverResult{Data: d, Log: msg, GasUsed: 321}
 	ad := dres.ToABCI()
 	assert.EqualValues(t, d, ad.Data)
 	assert.Equal(t, msg, ad.Log)
 	assert.Empty(t, ad.Tags)
+	assert.Equal(t, int64(321), ad.GasUsed)
+
+	// gas is still reported on errors
+	ed := weave.DeliverOrError(dres, errors.ErrOutOfGas("write"))
+	assert.Equal(t, uint32(errors.CodeOutOfGas), ed.Code)
+	assert.Equal(t, int64(321), ed.GasUsed)
 
 	c, gas := "aok", int64(12345)
 	cres := weave.NewCheck(gas, c)
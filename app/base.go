@@ -2,18 +2,20 @@ package app
 
 import (
 	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/common"
 
 	"github.com/confio/weave"
 	"github.com/confio/weave/errors"
 )
 
-// BaseApp adds DeliverTx, CheckTx, and BeginBlock
+// BaseApp adds DeliverTx, CheckTx, BeginBlock and EndBlock
 // handlers to the storage and query functionality of StoreApp
 type BaseApp struct {
 	*StoreApp
-	decoder weave.TxDecoder
-	handler weave.Handler
-	ticker  weave.Ticker
+	decoder    weave.TxDecoder
+	handler    weave.Handler
+	ticker     weave.Ticker
+	endBlocker weave.EndBlocker
 }
 
 var _ abci.Application = BaseApp{}
@@ -30,6 +32,13 @@ func NewBaseApp(store *StoreApp, decoder weave.TxDecoder,
 	}
 }
 
+// WithEndBlocker sets an EndBlocker to call at the end of
+// every block
+func (b BaseApp) WithEndBlocker(e weave.EndBlocker) BaseApp {
+	b.endBlocker = e
+	return b
+}
+
 // DeliverTx - ABCI - dispatches to the handler
 func (b BaseApp) DeliverTx(txBytes []byte) abci.ResponseDeliverTx {
 	tx, err := b.loadTx(txBytes)
@@ -86,6 +95,26 @@ func (b BaseApp) BeginBlock(req abci.RequestBeginBlock) (
 	return
 }
 
+// EndBlock - ABCI
+// Calls the EndBlocker, if set, and returns its tags along
+// with all validator changes made in this block
+func (b BaseApp) EndBlock(req abci.RequestEndBlock) abci.ResponseEndBlock {
+	var tags []common.KVPair
+	if b.endBlocker != nil {
+		ctx := weave.WithLogInfo(b.BlockContext(), "call", "end_block")
+		res, err := b.endBlocker.EndBlock(ctx, b.DeliverStore())
+		if err != nil {
+			panic(err)
+		}
+		b.StoreApp.AddValChange(res.Diff)
+		tags = res.Tags
+	}
+
+	res := b.StoreApp.EndBlock(req)
+	res.Tags = append(res.Tags, tags...)
+	return res
+}
+
 // loadTx calls the decoder, and capture any panics
 func (b BaseApp) loadTx(txBytes []byte) (tx weave.Tx, err error) {
 	defer errors.Recover(&err)
@@ -0,0 +1,126 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store/iavl"
+)
+
+// heightEndBlocker stores the height at the end of each block,
+// and adds a validator with that power
+type heightEndBlocker struct{}
+
+func (heightEndBlocker) EndBlock(ctx weave.Context,
+	db weave.KVStore) (weave.EndBlockResult, error) {
+
+	var res weave.EndBlockResult
+	height, _ := weave.GetHeight(ctx)
+	db.Set([]byte("height"), []byte{byte(height)})
+	res.Diff = []abci.Validator{{PubKey: abci.PubKey{Data: []byte("val")}, Power: height}}
+	res.Tags = []common.KVPair{{Key: []byte("height"), Value: []byte{byte(height)}}}
+	return res, nil
+}
+
+func TestEndBlocker(t *testing.T) {
+	store := NewStoreApp("test", iavl.MockCommitStore(),
+		weave.NewQueryRouter(), context.Background())
+	base := NewBaseApp(store, nil, nil, nil)
+
+	// nothing happens without an EndBlocker
+	base.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+	res := base.EndBlock(abci.RequestEndBlock{Height: 1})
+	assert.Empty(t, res.ValidatorUpdates)
+	assert.Empty(t, res.Tags)
+	base.Commit()
+
+	// the diff is merged with the changes of the block
+	base = base.WithEndBlocker(heightEndBlocker{})
+	base.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	base.AddValChange([]abci.Validator{
+		{PubKey: abci.PubKey{Data: []byte("other")}, Power: 10},
+		{PubKey: abci.PubKey{Data: []byte("val")}, Power: 10},
+	})
+	res = base.EndBlock(abci.RequestEndBlock{Height: 2})
+	require.Equal(t, 2, len(res.ValidatorUpdates))
+	assert.Equal(t, int64(10), res.ValidatorUpdates[0].Power)
+	assert.Equal(t, int64(2), res.ValidatorUpdates[1].Power)
+	require.Equal(t, 1, len(res.Tags))
+	assert.Equal(t, []byte{2}, res.Tags[0].Value)
+	base.Commit()
+
+	// and it writes to the deliver store
+	assert.Equal(t, []byte{2}, store.DeliverStore().Get([]byte("height")))
+}
+
+// diffTicker adds one validator, or fails if err is set
+type diffTicker struct {
+	name string
+	err  error
+}
+
+func (d diffTicker) Tick(ctx weave.Context,
+	db weave.KVStore) (weave.TickResult, error) {
+
+	var res weave.TickResult
+	res.Diff = []abci.Validator{{PubKey: abci.PubKey{Data: []byte(d.name)}, Power: 1}}
+	return res, d.err
+}
+
+func TestChainTickers(t *testing.T) {
+	ctx := context.Background()
+	db := iavl.MockCommitStore().CacheWrap()
+
+	// all diffs are combined in order
+	res, err := ChainTickers(diffTicker{name: "a"}, diffTicker{name: "b"}).Tick(ctx, db)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res.Diff))
+	assert.Equal(t, []byte("a"), res.Diff[0].PubKey.Data)
+	assert.Equal(t, []byte("b"), res.Diff[1].PubKey.Data)
+
+	// and we abort on the first error
+	fail := fmt.Errorf("boom")
+	_, err = ChainTickers(diffTicker{name: "a", err: fail}, diffTicker{name: "b"}).Tick(ctx, db)
+	assert.Equal(t, fail, err)
+}
+
+// keyInvariant requires the key to be in the store
+type keyInvariant []byte
+
+func (k keyInvariant) CheckInvariants(db weave.ReadOnlyKVStore) error {
+	if db.Get(k) == nil {
+		return fmt.Errorf("Missing %X", []byte(k))
+	}
+	return nil
+}
+
+func TestCheckState(t *testing.T) {
+	store := NewStoreApp("test", iavl.MockCommitStore(),
+		weave.NewQueryRouter(), context.Background())
+
+	// nothing to check
+	_, err := store.CheckState(0)
+	assert.Error(t, err)
+
+	store = store.WithInvariants(keyInvariant("foo"))
+	store.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+	store.Commit()
+	store.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	store.DeliverStore().Set([]byte("foo"), []byte("bar"))
+	store.Commit()
+
+	// we can check the latest or any older state
+	height, err := store.CheckState(0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), height)
+	height, err = store.CheckState(1)
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), height)
+}
@@ -34,6 +34,35 @@ func (cs *commitStore) CommitInfo() (version int64, hash []byte) {
 	return id.Version, id.Hash
 }
 
+// ReadVersion returns a read-only view of the committed state
+// at the given height, or the most recent state if height is 0.
+// Returns the actual height we read from.
+func (cs *commitStore) ReadVersion(height int64) (weave.ReadOnlyKVStore, int64, error) {
+	latest, _ := cs.CommitInfo()
+	if height == 0 || height == latest {
+		return cs.committed.CacheWrap(), latest, nil
+	}
+	if height > latest {
+		return nil, 0, errors.ErrUnknownHeight(height)
+	}
+	db, err := cs.committed.GetVersion(height)
+	return db, height, err
+}
+
+// ProveVersion is like ReadVersion, but returns a view that
+// records proofs of all reads. This only works on committed state.
+func (cs *commitStore) ProveVersion(height int64) (weave.ProvableKVStore, int64, error) {
+	latest, _ := cs.CommitInfo()
+	if height == 0 {
+		height = latest
+	}
+	if height == 0 || height > latest {
+		return nil, 0, errors.ErrUnknownHeight(height)
+	}
+	db, err := cs.committed.GetVersionWithProof(height)
+	return db, height, err
+}
+
 // Commit will flush deliver to the underlying store and commit it
 // to disk. It then regenerates new deliver/check caches
 //
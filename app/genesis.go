@@ -1,6 +1,8 @@
 package app
 
 import (
+	"fmt"
+
 	"github.com/confio/weave"
 )
 
@@ -15,6 +17,8 @@ type chainInitializer struct {
 	inits []weave.Initializer
 }
 
+var _ weave.Exporter = chainInitializer{}
+
 // FromGenesis will pass opts to all Initializers in the list,
 // aborting at the first error.
 func (c chainInitializer) FromGenesis(opts weave.Options, kv weave.KVStore) error {
@@ -26,3 +30,20 @@ func (c chainInitializer) FromGenesis(opts weave.Options, kv weave.KVStore) erro
 	}
 	return nil
 }
+
+// ToGenesis will call all Initializers in the list, aborting
+// at the first error. They must all be Exporters, as skipping
+// one would lose its state.
+func (c chainInitializer) ToGenesis(opts weave.Options, kv weave.ReadOnlyKVStore) error {
+	for _, i := range c.inits {
+		exp, ok := i.(weave.Exporter)
+		if !ok {
+			return fmt.Errorf("Initializer cannot export state: %T", i)
+		}
+		err := exp.ToGenesis(opts, kv)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -2,13 +2,13 @@
 // source: app/results.proto
 
 /*
-Package app is a generated protocol buffer package.
+	Package app is a generated protocol buffer package.
 
-It is generated from these files:
-	app/results.proto
+	It is generated from these files:
+		app/results.proto
 
-It has these top-level messages:
-	ResultSet
+	It has these top-level messages:
+		ResultSet
 */
 package app
 
@@ -32,6 +32,9 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 // ResultSet contains a list of keys or values
 type ResultSet struct {
 	Results [][]byte `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	// Next is the cursor to get the next page of
+	// a paged query, empty if there are no more results
+	Next []byte `protobuf:"bytes,2,opt,name=next,proto3" json:"next,omitempty"`
 }
 
 func (m *ResultSet) Reset()                    { *m = ResultSet{} }
@@ -46,6 +49,13 @@ func (m *ResultSet) GetResults() [][]byte {
 	return nil
 }
 
+func (m *ResultSet) GetNext() []byte {
+	if m != nil {
+		return m.Next
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ResultSet)(nil), "app.ResultSet")
 }
@@ -72,6 +82,12 @@ func (m *ResultSet) MarshalTo(dAtA []byte) (int, error) {
 			i += copy(dAtA[i:], b)
 		}
 	}
+	if len(m.Next) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintResults(dAtA, i, uint64(len(m.Next)))
+		i += copy(dAtA[i:], m.Next)
+	}
 	return i, nil
 }
 
@@ -93,6 +109,10 @@ func (m *ResultSet) Size() (n int) {
 			n += 1 + l + sovResults(uint64(l))
 		}
 	}
+	l = len(m.Next)
+	if l > 0 {
+		n += 1 + l + sovResults(uint64(l))
+	}
 	return n
 }
 
@@ -167,6 +187,37 @@ func (m *ResultSet) Unmarshal(dAtA []byte) error {
 			m.Results = append(m.Results, make([]byte, postIndex-iNdEx))
 			copy(m.Results[len(m.Results)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Next", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowResults
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthResults
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Next = append(m.Next[:0], dAtA[iNdEx:postIndex]...)
+			if m.Next == nil {
+				m.Next = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipResults(dAtA[iNdEx:])
@@ -296,12 +347,12 @@ var (
 func init() { proto.RegisterFile("app/results.proto", fileDescriptorResults) }
 
 var fileDescriptorResults = []byte{
-	// 97 bytes of a gzipped FileDescriptorProto
+	// 110 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4c, 0x2c, 0x28, 0xd0,
 	0x2f, 0x4a, 0x2d, 0x2e, 0xcd, 0x29, 0x29, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x4e,
-	0x2c, 0x28, 0x50, 0x52, 0xe5, 0xe2, 0x0c, 0x02, 0x8b, 0x06, 0xa7, 0x96, 0x08, 0x49, 0x70, 0xb1,
-	0x43, 0x95, 0x48, 0x30, 0x2a, 0x30, 0x6b, 0xf0, 0x04, 0xc1, 0xb8, 0x4e, 0x02, 0x27, 0x1e, 0xc9,
-	0x31, 0x5e, 0x78, 0x24, 0xc7, 0xf8, 0xe0, 0x91, 0x1c, 0xe3, 0x84, 0xc7, 0x72, 0x0c, 0x49, 0x6c,
-	0x60, 0x43, 0x8c, 0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0x87, 0x7d, 0x95, 0x87, 0x59, 0x00, 0x00,
-	0x00,
+	0x2c, 0x28, 0x50, 0xb2, 0xe4, 0xe2, 0x0c, 0x02, 0x8b, 0x06, 0xa7, 0x96, 0x08, 0x49, 0x70, 0xb1,
+	0x43, 0x95, 0x48, 0x30, 0x2a, 0x30, 0x6b, 0xf0, 0x04, 0xc1, 0xb8, 0x42, 0x42, 0x5c, 0x2c, 0x79,
+	0xa9, 0x15, 0x25, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0x3c, 0x41, 0x60, 0xb6, 0x93, 0xc0, 0x89, 0x47,
+	0x72, 0x8c, 0x17, 0x1e, 0xc9, 0x31, 0x3e, 0x78, 0x24, 0xc7, 0x38, 0xe1, 0xb1, 0x1c, 0x43, 0x12,
+	0x1b, 0xd8, 0x60, 0x63, 0xc0, 0x00, 0x38, 0x24, 0x87, 0x9d, 0x6d, 0x00, 0x00, 0x00,
 }
@@ -47,6 +47,12 @@ type StoreApp struct {
 	// cached validator changes from DeliverTx
 	pending []abci.Validator
 
+	// keeps track of the current validator set, if set
+	validators weave.ValidatorUpdater
+
+	// verifies the state on demand, if set
+	invariants weave.InvariantChecker
+
 	// baseContext contains context info that is valid for
 	// lifetime of this app (eg. chainID)
 	baseContext weave.Context
@@ -98,6 +104,19 @@ func (s *StoreApp) WithInit(init weave.Initializer) *StoreApp {
 	return s
 }
 
+// WithValidators is used to persist the genesis validators
+// and all changes to them
+func (s *StoreApp) WithValidators(v weave.ValidatorUpdater) *StoreApp {
+	s.validators = v
+	return s
+}
+
+// WithInvariants is used to check the state on demand
+func (s *StoreApp) WithInvariants(c weave.InvariantChecker) *StoreApp {
+	s.invariants = c
+	return s
+}
+
 // parseAppState is called from InitChain, the first time the chain
 // starts, and not on restarts.
 func (s *StoreApp) parseAppState(data []byte, chainID string, init weave.Initializer) error {
@@ -119,6 +138,69 @@ func (s *StoreApp) parseAppState(data []byte, chainID string, init weave.Initial
 	return init.FromGenesis(appState, s.DeliverStore())
 }
 
+// ExportState dumps the state at the given height, or the latest
+// one if height is 0, in the format read by InitChain.
+// Returns the actual height we read from, which is also stored
+// under weave.ExportHeightKey, so the modules can rebase their
+// heights on the new chain.
+//
+// This requires the Initializer to also be a weave.Exporter.
+func (s *StoreApp) ExportState(height int64) (weave.Options, int64, error) {
+	exp, ok := s.initializer.(weave.Exporter)
+	if !ok {
+		return nil, 0, fmt.Errorf("Initializer cannot export state: %T", s.initializer)
+	}
+	db, height, err := s.store.ReadVersion(height)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := make(weave.Options)
+	err = exp.ToGenesis(opts, db)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = opts.WriteOptions(weave.ExportHeightKey, height)
+	if err != nil {
+		return nil, 0, err
+	}
+	return opts, height, nil
+}
+
+// ExportValidators returns the validator set at the given height,
+// or the latest one if height is 0, so a new chain can start with
+// the current power table instead of the original one.
+// Returns the actual height we read from.
+//
+// This requires a ValidatorUpdater, see WithValidators.
+func (s *StoreApp) ExportValidators(height int64) ([]abci.Validator, int64, error) {
+	if s.validators == nil {
+		return nil, 0, fmt.Errorf("No validators to export")
+	}
+	db, height, err := s.store.ReadVersion(height)
+	if err != nil {
+		return nil, 0, err
+	}
+	vals, err := s.validators.GetValidators(db)
+	if err != nil {
+		return nil, 0, err
+	}
+	return vals, height, nil
+}
+
+// CheckState verifies the invariants hold at the given height,
+// or the latest one if height is 0.
+// Returns the actual height we read from.
+func (s *StoreApp) CheckState(height int64) (int64, error) {
+	if s.invariants == nil {
+		return 0, fmt.Errorf("No invariants to check")
+	}
+	db, height, err := s.store.ReadVersion(height)
+	if err != nil {
+		return 0, err
+	}
+	return height, s.invariants.CheckInvariants(db)
+}
+
 // store chainID and update context
 func (s *StoreApp) storeChainID(chainId string) error {
 	// set the chainID
@@ -197,9 +279,26 @@ A query request has the following elements:
 * Height - the block height to query (if 0 most recent)
 * Prove - if true, also return a proof
 
+Proofs cover every read the query handler made on the store,
+including secondary index lookups. Their format is defined by the
+CommitKVStore (for iavl, see iavl.VerifyResults).
+
+Historical queries are only possible as long as the requested
+height is still kept in the history of the CommitKVStore.
+
 Path may be "/", "/<bucket>", or "/<bucket>/<index>"
-It may be followed by "?prefix" to make a prefix query.
-Soon we will support "?range" for powerful range queries
+It may be followed by "?prefix" to make a prefix query,
+or "?range" for powerful range queries.
+
+Prefix queries are split in pages of weave.DefaultPageLimit,
+or the size given by appending eg. "&limit=50" to the modifier
+(at most weave.MaxPageLimit). If there are more results,
+the ResultSets contain a Next cursor, which can be passed
+as "&cursor=<hex>" to get the next page.
+
+Appending "&json" to the modifier (or "?json" for key queries)
+returns the values as JSON instead of protobuf, if the path
+registered a JSONEncoder, like all orm buckets do.
 
 Key and Value in Results are always serialized ResultSet
 objects, able to support 0 to N values. They must be the
@@ -216,51 +315,104 @@ func (s *StoreApp) Query(reqQuery abci.RequestQuery) (resQuery abci.ResponseQuer
 		resQuery.Log = fmt.Sprintf("Unexpected Query path: %v", reqQuery.Path)
 		return
 	}
+	mod, asJSON := weave.ParseJSON(mod)
+	var enc weave.JSONEncoder
+	if asJSON {
+		enc = s.queryRouter.JSONEncoder(path)
+		if enc == nil {
+			resQuery.Code = errors.CodeUnknownRequest
+			resQuery.Log = fmt.Sprintf("No json for query path: %v", path)
+			return
+		}
+	}
 
-	// TODO: support historical queries by getting old read-only
-	// height := reqQuery.Height
-	// if height == 0 {
-	// 	withProof := s.CommittedHeight() - 1
-	// 	if tree.Tree.VersionExists(uint64(withProof)) {
-	// 		height = withProof
-	// 	} else {
-	// 		height = s.CommittedHeight()
-	// 	}
-	// }
-	height, _ := s.store.CommitInfo()
+	var db weave.ReadOnlyKVStore
+	var prover weave.ProvableKVStore
+	var height int64
+	var err error
+	if reqQuery.Prove {
+		prover, height, err = s.store.ProveVersion(reqQuery.Height)
+		db = prover
+	} else {
+		db, height, err = s.store.ReadVersion(reqQuery.Height)
+	}
+	if err != nil {
+		return queryError(err)
+	}
 	resQuery.Height = height
-	// TODO: better version handling!
-	db := s.store.committed.CacheWrap()
 
 	// make the query
-	models, err := qh.Query(db, mod, reqQuery.Data)
+	models, next, err := runQuery(qh, db, mod, reqQuery.Data)
 	if err != nil {
 		return queryError(err)
 	}
+	if enc != nil {
+		models, err = encodeJSON(enc, models)
+		if err != nil {
+			return queryError(err)
+		}
+	}
 
 	// set the info as ResultSets....
-	resQuery.Key, err = ResultsFromKeys(models).Marshal()
+	keys, values := ResultsFromKeys(models), ResultsFromValues(models)
+	keys.Next, values.Next = next, next
+	resQuery.Key, err = keys.Marshal()
 	if err != nil {
 		return queryError(err)
 	}
-	resQuery.Value, err = ResultsFromValues(models).Marshal()
+	resQuery.Value, err = values.Marshal()
 	if err != nil {
 		return queryError(err)
 	}
 
-	// TODO: support proofs given this info....
-	// if reqQuery.Prove {
-	//  value, proof, err := tree.GetVersionedWithProof(key, height)
-	//  if err != nil {
-	//      resQuery.Log = err.Error()
-	//      break
-	//  }
-	//  resQuery.Value = value
-	//  resQuery.Proof = proof.Bytes()
+	// proves all reads the query made, so the client can
+	// check all results and the absence of any others
+	if prover != nil {
+		resQuery.Proof, err = prover.Proof()
+		if err != nil {
+			return queryError(err)
+		}
+	}
 
 	return resQuery
 }
 
+// runQuery calls the query handler, using pagination if
+// requested in the modifier. next is the cursor for the
+// next page, if any
+func runQuery(qh weave.QueryHandler, db weave.ReadOnlyKVStore,
+	mod string, data []byte) (models []weave.Model, next []byte, err error) {
+
+	mod, page, err := weave.ParsePage(mod)
+	if err != nil {
+		return nil, nil, err
+	}
+	if page == nil {
+		models, err = qh.Query(db, mod, data)
+		return models, nil, err
+	}
+	pqh, ok := qh.(weave.PagedQueryHandler)
+	if !ok {
+		return nil, nil, errors.ErrInvalidPage("not supported")
+	}
+	return pqh.QueryPage(db, mod, data, *page)
+}
+
+// encodeJSON returns the models with all values as JSON.
+// Proofs still cover the raw values, which the client must
+// query without json to verify
+func encodeJSON(enc weave.JSONEncoder, models []weave.Model) ([]weave.Model, error) {
+	res := make([]weave.Model, len(models))
+	for i, m := range models {
+		value, err := enc(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = weave.Pair(m.Key, value)
+	}
+	return res, nil
+}
+
 // splitPath splits out the real path along with the query
 // modifier (everything after the ?)
 func splitPath(path string) (string, string) {
@@ -274,9 +426,10 @@ func splitPath(path string) (string, string) {
 }
 
 func queryError(err error) abci.ResponseQuery {
+	tm := errors.Wrap(err)
 	return abci.ResponseQuery{
-		Log:  err.Error(),
-		Code: errors.CodeInternalErr,
+		Log:  tm.ABCILog(),
+		Code: tm.ABCICode(),
 	}
 }
 
@@ -293,7 +446,7 @@ func (s *StoreApp) Commit() (res abci.ResponseCommit) {
 }
 
 // InitChain implements ABCI
-// TODO: store the original validators somewhere
+// The original validators are stored with the ValidatorUpdater, if any.
 // Note: in tendermint 0.17, the genesis file is passed
 // in here, we should use this to trigger reading the genesis now
 // TODO: investigate validators and consensusParams in response
@@ -303,6 +456,7 @@ func (s *StoreApp) InitChain(req abci.RequestInitChain) (res abci.ResponseInitCh
 		// Read comment on type header
 		panic(err)
 	}
+	s.updateValidators(req.Validators)
 
 	return abci.ResponseInitChain{}
 }
@@ -314,6 +468,7 @@ func (s *StoreApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBegi
 	// set the begin block context
 	ctx := weave.WithHeader(s.baseContext, req.Header)
 	ctx = weave.WithHeight(ctx, req.Header.GetHeight())
+	ctx = weave.WithLastCommit(ctx, req.Validators)
 	s.blockContext = ctx
 
 	return
@@ -330,7 +485,9 @@ func (s *StoreApp) EndBlock(_ abci.RequestEndBlock) (res abci.ResponseEndBlock)
 
 // AddValChange is meant to be called by apps on DeliverTx
 // results, this is added to the cache for the endblock changeset
+// and stored with the ValidatorUpdater, if any
 func (s *StoreApp) AddValChange(diffs []abci.Validator) {
+	s.updateValidators(diffs)
 	// ensures multiple updates for one validator are combined into one slot
 	for _, d := range diffs {
 		idx := pubKeyIndex(d, s.pending)
@@ -351,3 +508,16 @@ func pubKeyIndex(val abci.Validator, list []abci.Validator) int {
 	}
 	return -1
 }
+
+// updateValidators writes the diff to the deliver store,
+// if we keep track of the validators
+func (s *StoreApp) updateValidators(diff []abci.Validator) {
+	if s.validators == nil || len(diff) == 0 {
+		return
+	}
+	err := s.validators.UpdateValidators(s.DeliverStore(), diff)
+	if err != nil {
+		// Read comment on type header
+		panic(err)
+	}
+}
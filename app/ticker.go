@@ -0,0 +1,29 @@
+package app
+
+import (
+	"github.com/confio/weave"
+)
+
+// ChainTickers lets you run many tickers at the beginning of
+// each block, in the given order
+func ChainTickers(tickers ...weave.Ticker) weave.Ticker {
+	return chainTicker{tickers}
+}
+
+type chainTicker struct {
+	tickers []weave.Ticker
+}
+
+// Tick calls all Tickers in the list, aborting at the first
+// error, and returns the validator changes of all of them
+func (c chainTicker) Tick(ctx weave.Context, store weave.KVStore) (weave.TickResult, error) {
+	var res weave.TickResult
+	for _, t := range c.tickers {
+		r, err := t.Tick(ctx, store)
+		if err != nil {
+			return res, err
+		}
+		res.Diff = append(res.Diff, r.Diff...)
+	}
+	return res, nil
+}
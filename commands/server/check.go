@@ -0,0 +1,48 @@
+package server
+
+import (
+	"flag"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/tmlibs/log"
+)
+
+// StateChecker is implemented by apps that can verify their
+// invariants, such as app.BaseApp
+type StateChecker interface {
+	CheckState(height int64) (int64, error)
+}
+
+func parseCheck(args []string) (int64, error) {
+	var height int64
+	checkFlags := flag.NewFlagSet("check", flag.ExitOnError)
+	checkFlags.Int64Var(&height, flagHeight, 0, "height to check, 0 for the latest")
+	err := checkFlags.Parse(args)
+	return height, err
+}
+
+// CheckCmd verifies all invariants of the app hold at a given
+// height, and returns an error with every violation otherwise.
+//
+// The app must not be running, as we open the same database.
+func CheckCmd(gen AppGenerator, logger log.Logger, home string, args []string) error {
+	height, err := parseCheck(args)
+	if err != nil {
+		return err
+	}
+
+	app, err := gen(home, logger)
+	if err != nil {
+		return err
+	}
+	checker, ok := app.(StateChecker)
+	if !ok {
+		return errors.Errorf("App cannot check state: %T", app)
+	}
+	height, err = checker.CheckState(height)
+	if err != nil {
+		return err
+	}
+	logger.Info("All invariants hold", "height", height)
+	return nil
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/confio/weave"
+)
+
+const (
+	flagHeight  = "height"
+	flagOut     = "out"
+	flagChainID = "chain-id"
+
+	// ChainIDKey and ValidatorsKey are the fields of the
+	// genesis file we replace on export
+	ChainIDKey    = "chain_id"
+	ValidatorsKey = "validators"
+
+	// ed25519Prefix is the go-wire prefix tendermint expects
+	// for ed25519 pubkeys in the genesis file
+	ed25519Prefix = "B3EF72328EAF59"
+)
+
+// StateExporter is implemented by apps that can dump their state,
+// and validators, such as app.BaseApp
+type StateExporter interface {
+	ExportState(height int64) (weave.Options, int64, error)
+	ExportValidators(height int64) ([]abci.Validator, int64, error)
+}
+
+// genesisValidator is how tendermint reads a validator
+// from the genesis file
+type genesisValidator struct {
+	PubKey genesisPubKey `json:"pub_key"`
+	Power  int64         `json:"power"`
+	Name   string        `json:"name"`
+}
+
+type genesisPubKey struct {
+	Prefix string `json:"_df"`
+	Data   []byte `json:"_v"`
+}
+
+func toGenesisValidators(vals []abci.Validator) ([]genesisValidator, error) {
+	res := make([]genesisValidator, len(vals))
+	for i, v := range vals {
+		if v.PubKey.Type != "ed25519" {
+			return nil, errors.Errorf("Cannot export %s validator", v.PubKey.Type)
+		}
+		res[i] = genesisValidator{
+			PubKey: genesisPubKey{Prefix: ed25519Prefix, Data: v.PubKey.Data},
+			Power:  v.Power,
+		}
+	}
+	return res, nil
+}
+
+func parseExport(args []string) (int64, string, string, error) {
+	// parse flagHeight, flagOut, flagChainID and return the result
+	var height int64
+	var out, chainID string
+	exportFlags := flag.NewFlagSet("export", flag.ExitOnError)
+	exportFlags.Int64Var(&height, flagHeight, 0, "height to export, 0 for the latest")
+	exportFlags.StringVar(&out, flagOut, "", "file to write to, stdout if empty")
+	exportFlags.StringVar(&chainID, flagChainID, "", "chain id of the new chain (required)")
+	err := exportFlags.Parse(args)
+	return height, out, chainID, err
+}
+
+// ExportCmd dumps the state of the app at a given height as
+// app_state in a genesis file, so a new chain can start from it.
+// The validators are the ones at that height, and the chain id
+// must be a new one, so transactions cannot be replayed.
+//
+// Everything else is copied from the current genesis file in home,
+// if there is one. The app must not be running, as we open the
+// same database.
+func ExportCmd(gen AppGenerator, logger log.Logger, home string, args []string) error {
+	height, out, chainID, err := parseExport(args)
+	if err != nil {
+		return err
+	}
+	if chainID == "" {
+		return errors.Errorf("Missing -%s for the new chain", flagChainID)
+	}
+
+	app, err := gen(home, logger)
+	if err != nil {
+		return err
+	}
+	exp, ok := app.(StateExporter)
+	if !ok {
+		return errors.Errorf("App cannot export state: %T", app)
+	}
+	opts, height, err := exp.ExportState(height)
+	if err != nil {
+		return err
+	}
+	state, err := json.MarshalIndent(opts, "", "  ")
+	if err != nil {
+		return err
+	}
+	vals, _, err := exp.ExportValidators(height)
+	if err != nil {
+		return err
+	}
+	genVals, err := toGenesisValidators(vals)
+	if err != nil {
+		return err
+	}
+	valsJSON, err := json.Marshal(genVals)
+	if err != nil {
+		return err
+	}
+
+	doc, err := loadGenesis(filepath.Join(home, DirConfig, "genesis.json"))
+	if err != nil {
+		return err
+	}
+	var oldChainID string
+	if raw, ok := doc[ChainIDKey]; ok {
+		err = json.Unmarshal(raw, &oldChainID)
+		if err != nil {
+			return err
+		}
+	}
+	if chainID == oldChainID {
+		return errors.Errorf("The new chain needs another id than %s", oldChainID)
+	}
+	newChainID, err := json.Marshal(chainID)
+	if err != nil {
+		return err
+	}
+	doc[ChainIDKey] = newChainID
+	doc[ValidatorsKey] = valsJSON
+	doc[AppStateKey] = state
+	bz, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Exported state", "height", height)
+	if out == "" {
+		_, err = fmt.Println(string(bz))
+		return err
+	}
+	return ioutil.WriteFile(out, bz, 0600)
+}
+
+// loadGenesis reads the genesis file, or returns an
+// empty doc if there is none
+func loadGenesis(filename string) (GenesisDoc, error) {
+	bz, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return GenesisDoc{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var doc GenesisDoc
+	err = json.Unmarshal(bz, &doc)
+	return doc, err
+}
@@ -18,7 +18,8 @@ var (
 	AddressLength = 20
 
 	// it must have (?s) flags, otherwise it errors when last section contains 0x20 (newline)
-	perm = regexp.MustCompile(`(?s)^([a-zA-Z0-9_\-]{3,8})/([a-zA-Z0-9_\-]{3,8})/(.+)$`)
+	// the type may be a bit longer, to fit names like secp256k1
+	perm = regexp.MustCompile(`(?s)^([a-zA-Z0-9_\-]{3,8})/([a-zA-Z0-9_\-]{3,10})/(.+)$`)
 )
 
 // Condition is a specially formatted array, containing
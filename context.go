@@ -38,6 +38,8 @@ const (
 	contextKeyHeight
 	contextKeyChainID
 	contextKeyLogger
+	contextKeyGasMeter
+	contextKeyLastCommit
 )
 
 var (
@@ -69,6 +71,24 @@ func GetHeader(ctx Context) (abci.Header, bool) {
 	return val, ok
 }
 
+// WithLastCommit sets the validators of the last block,
+// and whether they signed it, for the Context.
+// panics if called with last commit already set
+func WithLastCommit(ctx Context, vals []abci.SigningValidator) Context {
+	if _, ok := GetLastCommit(ctx); ok {
+		panic("Last commit already set")
+	}
+	return context.WithValue(ctx, contextKeyLastCommit, vals)
+}
+
+// GetLastCommit returns the validators of the last block,
+// and whether they signed it.
+// ok is false if no last commit set in this Context
+func GetLastCommit(ctx Context) ([]abci.SigningValidator, bool) {
+	val, ok := ctx.Value(contextKeyLastCommit).([]abci.SigningValidator)
+	return val, ok
+}
+
 // WithHeight sets the block height for the Context.
 // panics if called with height already set
 func WithHeight(ctx Context, height int64) Context {
@@ -103,6 +123,22 @@ func GetChainID(ctx Context) string {
 	return ctx.Value(contextKeyChainID).(string)
 }
 
+// WithGasMeter sets the gas meter for the Context.
+// panics if called with a gas meter already set
+func WithGasMeter(ctx Context, meter GasMeter) Context {
+	if _, ok := GetGasMeter(ctx); ok {
+		panic("Gas meter already set")
+	}
+	return context.WithValue(ctx, contextKeyGasMeter, meter)
+}
+
+// GetGasMeter returns the gas meter of the current tx
+// ok is false if no gas meter set in this Context
+func GetGasMeter(ctx Context) (GasMeter, bool) {
+	val, ok := ctx.Value(contextKeyGasMeter).(GasMeter)
+	return val, ok
+}
+
 // WithLogger sets the logger for this Context
 func WithLogger(ctx Context, logger log.Logger) Context {
 	// Logger can be overridden below... no problem
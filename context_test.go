@@ -44,6 +44,15 @@ func TestContext(t *testing.T) {
 	// don't try a second time
 	assert.Panics(t, func() { WithChainID(ctx2, "my-chain") })
 
+	// last commit is set once, even if empty
+	_, ok = GetLastCommit(ctx)
+	assert.False(t, ok)
+	ctx2 = WithLastCommit(ctx, nil)
+	vals, ok := GetLastCommit(ctx2)
+	assert.True(t, ok)
+	assert.Empty(t, vals)
+	assert.Panics(t, func() { WithLastCommit(ctx2, nil) })
+
 	// TODO: test header context!
 }
 
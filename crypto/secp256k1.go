@@ -0,0 +1,107 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+
+	"github.com/confio/weave"
+)
+
+const (
+	// secp256k1 public keys are always in the 33 byte compressed form,
+	// so every key has exactly one Condition (and Address)
+	secp256k1PubKeySize = 33
+	// signatures are r | s, each padded to 32 bytes
+	secp256k1SigSize = 64
+)
+
+// we only accept signatures with s in the lower half of the
+// curve order, as (r, -s) is just as valid and would allow
+// anyone to change the signature bytes
+var secp256k1HalfOrder = new(big.Int).Rsh(btcec.S256().N, 1)
+
+var _ PubKey = (*PublicKey_Secp256K1)(nil)
+
+// Verify verifies the signature was created with this message and public key.
+//
+// The message is hashed with sha256 first, as secp256k1 only signs hashes.
+func (p *PublicKey_Secp256K1) Verify(message []byte, sig *Signature) bool {
+	secsig, ok := sig.GetSig().(*Signature_Secp256K1)
+	if !ok || len(secsig.Secp256K1) != secp256k1SigSize {
+		return false
+	}
+	if len(p.Secp256K1) != secp256k1PubKeySize {
+		return false
+	}
+	publicKey, err := btcec.ParsePubKey(p.Secp256K1, btcec.S256())
+	if err != nil {
+		return false
+	}
+
+	parsed := &btcec.Signature{
+		R: new(big.Int).SetBytes(secsig.Secp256K1[:32]),
+		S: new(big.Int).SetBytes(secsig.Secp256K1[32:]),
+	}
+	if parsed.S.Cmp(secp256k1HalfOrder) > 0 {
+		return false
+	}
+	hash := sha256.Sum256(message)
+	return parsed.Verify(hash[:], publicKey)
+}
+
+// Condition encodes the public key into a weave permission
+func (p *PublicKey_Secp256K1) Condition() weave.Condition {
+	return weave.NewCondition(ExtensionName, "secp256k1", p.Secp256K1)
+}
+
+var _ Signer = (*PrivateKey_Secp256K1)(nil)
+
+// Sign returns a matching signature for this private key.
+//
+// Signatures are deterministic (RFC 6979), so signing the same
+// message twice returns the same bytes.
+func (p *PrivateKey_Secp256K1) Sign(message []byte) (*Signature, error) {
+	privateKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), p.Secp256K1)
+	hash := sha256.Sum256(message)
+	parsed, err := privateKey.Sign(hash[:])
+	if err != nil {
+		return nil, err
+	}
+
+	bz := make([]byte, secp256k1SigSize)
+	r, s := parsed.R.Bytes(), parsed.S.Bytes()
+	copy(bz[32-len(r):32], r)
+	copy(bz[64-len(s):], s)
+	sig := &Signature{
+		Sig: &Signature_Secp256K1{
+			Secp256K1: bz,
+		},
+	}
+	return sig, nil
+}
+
+// PublicKey returns the corresponding PublicKey
+func (p *PrivateKey_Secp256K1) PublicKey() *PublicKey {
+	_, pub := btcec.PrivKeyFromBytes(btcec.S256(), p.Secp256K1)
+	return &PublicKey{
+		Pub: &PublicKey_Secp256K1{
+			Secp256K1: pub.SerializeCompressed(),
+		},
+	}
+}
+
+// GenPrivKeySecp256k1 returns a random new private key
+// (TODO: look at sources of randomness, other than default crypto/rand)
+func GenPrivKeySecp256k1() *PrivateKey {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		panic(err)
+	}
+	return &PrivateKey{
+		Priv: &PrivateKey_Secp256K1{
+			Secp256K1: priv.Serialize(),
+		},
+	}
+}
@@ -0,0 +1,97 @@
+package crypto
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecp256k1Signing(t *testing.T) {
+	private := GenPrivKeySecp256k1()
+	public := private.PublicKey()
+
+	msg := []byte("foobar")
+	msg2 := []byte("dingbooms")
+
+	sig, err := private.Sign(msg)
+	require.NoError(t, err)
+	sig2, err := private.Sign(msg2)
+	require.NoError(t, err)
+
+	bz, err := sig.Marshal()
+	assert.NoError(t, err)
+	bz2, err := sig2.Marshal()
+	assert.NoError(t, err)
+	assert.NotEqual(t, bz, bz2)
+
+	// signatures are deterministic
+	again, err := private.Sign(msg)
+	require.NoError(t, err)
+	assert.Equal(t, sig, again)
+
+	assert.True(t, public.Verify(msg, sig))
+	assert.False(t, public.Verify(msg, sig2))
+	assert.False(t, public.Verify(msg2, sig))
+	assert.True(t, public.Verify(msg2, sig2))
+	assert.False(t, public.Verify(msg, new(Signature)))
+	assert.False(t, public.Verify(msg, nil))
+
+	// and don't mix with ed25519
+	edsig, err := GenPrivKeyEd25519().Sign(msg)
+	require.NoError(t, err)
+	assert.False(t, public.Verify(msg, edsig))
+	assert.False(t, GenPrivKeyEd25519().PublicKey().Verify(msg, sig))
+}
+
+func TestSecp256k1KnownKey(t *testing.T) {
+	// the private key 1 has the generator as public key
+	priv := make([]byte, 32)
+	priv[31] = 1
+	private := &PrivateKey{Priv: &PrivateKey_Secp256K1{Secp256K1: priv}}
+	public := private.PublicKey()
+
+	gen := "0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798"
+	assert.Equal(t, gen, fmt.Sprintf("%X", public.GetSecp256K1()))
+	assert.Equal(t, "sigs/secp256k1/"+gen, public.Condition().String())
+
+	// signatures must be low-s, the flipped one is rejected
+	msg := []byte("foobar")
+	sig, err := private.Sign(msg)
+	require.NoError(t, err)
+	assert.True(t, public.Verify(msg, sig))
+	bz := sig.GetSecp256K1()
+	r, s := bz[:32], bz[32:]
+	flipped := new(big.Int).Sub(btcec.S256().N, new(big.Int).SetBytes(s))
+	high := make([]byte, 64)
+	copy(high, r)
+	copy(high[64-len(flipped.Bytes()):], flipped.Bytes())
+	highSig := &Signature{Sig: &Signature_Secp256K1{Secp256K1: high}}
+	assert.False(t, public.Verify(msg, highSig))
+
+	// only the compressed form is accepted
+	uncompressed := &PublicKey{Pub: &PublicKey_Secp256K1{
+		Secp256K1: append([]byte{4}, make([]byte, 64)...)}}
+	assert.False(t, uncompressed.Verify(msg, sig))
+}
+
+func TestSecp256k1Address(t *testing.T) {
+	pub := GenPrivKeySecp256k1().PublicKey()
+	pub2 := GenPrivKeySecp256k1().PublicKey()
+
+	assert.NoError(t, pub.Condition().Validate())
+	assert.NoError(t, pub2.Condition().Validate())
+	assert.NotEqual(t, pub.Condition(), pub2.Condition())
+	assert.NoError(t, pub.Address().Validate())
+
+	bz, err := pub.Marshal()
+	require.Nil(t, err)
+	var read PublicKey
+	err = read.Unmarshal(bz)
+	require.Nil(t, err)
+	assert.Equal(t, read.Condition(), pub.Condition())
+	assert.Equal(t, read.Address(), pub.Address())
+}
@@ -16,6 +16,7 @@ const (
 	CodeUnknownRequest             = 4
 	CodeUnrecognizedAddress        = 5
 	CodeInvalidChainID             = 6
+	CodeOutOfGas                   = 7
 )
 
 var (
@@ -29,6 +30,9 @@ var (
 	errUnrecognizedCondition = stderrors.New("Unrecognized Condition")
 	errInvalidChainID        = stderrors.New("Invalid ChainID")
 	errModifyChainID         = stderrors.New("Cannot modify ChainID")
+	errUnknownHeight         = stderrors.New("Unknown height")
+	errInvalidPage           = stderrors.New("Invalid page")
+	errOutOfGas              = stderrors.New("Out of gas")
 )
 
 // IsSameError returns true if these errors have the same root cause.
@@ -196,3 +200,40 @@ func ErrModifyChainID() error {
 func IsModifyChainIDErr(err error) bool {
 	return IsSameError(errModifyChainID, err)
 }
+
+// ErrUnknownHeight is when we request a version of the state
+// that was never committed or was already pruned
+func ErrUnknownHeight(height int64) error {
+	msg := fmt.Sprintf("%d", height)
+	return WithLog(msg, errUnknownHeight, CodeUnknownRequest)
+}
+
+// IsUnknownHeightErr returns true iff an error was created
+// with ErrUnknownHeight
+func IsUnknownHeightErr(err error) bool {
+	return IsSameError(errUnknownHeight, err)
+}
+
+// ErrInvalidPage is when the pagination options of a query
+// cannot be parsed or are not supported
+func ErrInvalidPage(reason string) error {
+	return WithLog(reason, errInvalidPage, CodeUnknownRequest)
+}
+
+// IsInvalidPageErr returns true iff an error was created
+// with ErrInvalidPage
+func IsInvalidPageErr(err error) bool {
+	return IsSameError(errInvalidPage, err)
+}
+
+// ErrOutOfGas is when a tx performed more work than its gas limit
+// allows. descriptor names the operation that used up the gas
+func ErrOutOfGas(descriptor string) error {
+	return WithLog(descriptor, errOutOfGas, CodeOutOfGas)
+}
+
+// IsOutOfGasErr returns true iff an error was created
+// with ErrOutOfGas
+func IsOutOfGasErr(err error) bool {
+	return IsSameError(errOutOfGas, err)
+}
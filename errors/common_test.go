@@ -43,6 +43,13 @@ func TestChecks(t *testing.T) {
 		{ErrInvalidSignature(), IsUnauthorizedErr, true},
 		{ErrInvalidSignature(), IsInvalidSignatureErr, true},
 
+		{ErrUnknownHeight(17), IsUnknownHeightErr, true},
+		{ErrUnknownHeight(17), IsInternalErr, false},
+		{ErrInvalidPage("limit"), IsInvalidPageErr, true},
+		{ErrInvalidPage("limit"), IsUnknownHeightErr, false},
+		{ErrOutOfGas("write"), IsOutOfGasErr, true},
+		{ErrOutOfGas("write"), IsInternalErr, false},
+
 		{nil, NoErr, true},
 		{Wrap(nil), NoErr, true},
 	}
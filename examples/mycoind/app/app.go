@@ -19,22 +19,51 @@ import (
 	"github.com/confio/weave/app"
 	"github.com/confio/weave/orm"
 	"github.com/confio/weave/store/iavl"
+	"github.com/confio/weave/store/leveldb"
 	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/batch"
 	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/currency"
+	"github.com/confio/weave/x/distribution"
+	"github.com/confio/weave/x/escrow"
+	"github.com/confio/weave/x/htlc"
+	"github.com/confio/weave/x/invariant"
+	"github.com/confio/weave/x/migration"
+	"github.com/confio/weave/x/multisig"
+	"github.com/confio/weave/x/scheduler"
 	"github.com/confio/weave/x/sigs"
+	"github.com/confio/weave/x/staking"
 	"github.com/confio/weave/x/utils"
 	"github.com/confio/weave/x/validators"
 )
 
+// TxGasLimit is the maximum gas a single tx may use
+// before it is aborted
+const TxGasLimit int64 = 100000
+
+// ProposerBonus is the percent of the fees of a block that
+// go to its proposer, before the rest is split among all
+// validators
+const ProposerBonus int64 = 10
+
+// InvariantBlocks is how often we verify the state is
+// consistent, logging every violation
+const InvariantBlocks int64 = 100
+
+// MigrationHeight is the first block in which pending migrations
+// run. With 0, they run as soon as a node with new ones starts.
+const MigrationHeight int64 = 0
+
 // Authenticator returns the typical authentication,
-// just using public key signatures
+// using public key signatures and multisig contracts
 func Authenticator() x.Authenticator {
-	return sigs.Authenticate{}
+	return x.ChainAuth(sigs.Authenticate{}, multisig.Authenticate{})
 }
 
-// CashControl returns a controller for cash functions
-func CashControl() cash.Controller {
-	return cash.NewController(cash.NewBucket())
+// CashControl returns a controller for cash functions,
+// that only accepts registered currencies
+func CashControl() currency.Controller {
+	return currency.NewController(cash.NewBucket())
 }
 
 // ValidatorControl returns a controller for validator functions
@@ -43,7 +72,8 @@ func ValidatorControl() validators.Controller {
 }
 
 // Chain returns a chain of decorators, to handle authentication,
-// fees, logging, and recovery
+// fees, gas metering, logging, and recovery. Batch messages
+// are unpacked last, so they are signed and paid for once.
 func Chain(minFee x.Coin, authFn x.Authenticator) app.Decorators {
 	return app.ChainDecorators(
 		utils.NewLogging(),
@@ -51,11 +81,15 @@ func Chain(minFee x.Coin, authFn x.Authenticator) app.Decorators {
 		utils.NewKeyTagger(),
 		// on CheckTx, bad tx don't affect state
 		utils.NewSavepoint().OnCheck(),
+		utils.NewGasLimit(TxGasLimit),
 		sigs.NewDecorator(),
-		cash.NewFeeDecorator(authFn, CashControl(), minFee),
+		multisig.NewDecorator(authFn),
+		cash.NewFeeDecorator(authFn, CashControl(), minFee).
+			WithCollector(distribution.CollectorAddress),
 		// on DeliverTx, bad tx will increment nonce and take fee
 		// even if the message fails
 		utils.NewSavepoint().OnDeliver(),
+		batch.NewDecorator(),
 	)
 }
 
@@ -65,9 +99,73 @@ func Router(authFn x.Authenticator) app.Router {
 	r := app.NewRouter()
 	cash.RegisterRoutes(r, authFn, CashControl())
 	validators.RegisterRoutes(r, authFn, ValidatorControl())
+	multisig.RegisterRoutes(r)
+	escrow.RegisterRoutes(r, authFn, CashControl())
+	htlc.RegisterRoutes(r, authFn, CashControl())
+	staking.RegisterRoutes(r, authFn, CashControl())
+	currency.RegisterRoutes(r, authFn, CashControl())
 	return r
 }
 
+// Ticker first applies pending migrations, then runs the
+// scheduler, which runs all tasks the modules scheduled for
+// the beginning of a block, followed by the invariant checks
+// every InvariantBlocks
+func Ticker() weave.Ticker {
+	t := scheduler.NewTicker()
+	staking.RegisterTasks(t, CashControl())
+	return app.ChainTickers(
+		migration.NewTicker(Migrations(), MigrationHeight),
+		t,
+		invariant.NewTicker(Invariants(), InvariantBlocks),
+	)
+}
+
+// Invariants returns the checks of all modules, to verify
+// the state is consistent
+func Invariants() *invariant.Checker {
+	return invariant.NewChecker().RegisterAll(
+		cash.RegisterInvariants,
+		currency.RegisterInvariants,
+		escrow.RegisterInvariants,
+		htlc.RegisterInvariants,
+		staking.RegisterInvariants,
+		scheduler.RegisterInvariants,
+	)
+}
+
+// Migrations returns the upgrades of the state of all
+// modules, applied once each when the code changes
+func Migrations() *migration.Registry {
+	return migration.NewRegistry()
+}
+
+// EndBlocker pays out the fees collected in each block
+// to the validators
+func EndBlocker() weave.EndBlocker {
+	return distribution.NewDistributor(cash.NewBucket(), ProposerBonus)
+}
+
+// Initializers returns the genesis loaders of all modules
+// that have state to set up
+func Initializers() weave.Initializer {
+	return app.ChainInitializers(
+		cash.Initializer{},
+		// after cash, to count the supply
+		currency.Initializer{},
+		validators.Initializer{},
+		sigs.Initializer{},
+		multisig.Initializer{},
+		escrow.Initializer{},
+		htlc.Initializer{},
+		staking.Initializer{},
+		scheduler.Initializer{},
+		distribution.Initializer{},
+		// all modules start at their latest version
+		Migrations(),
+	)
+}
+
 // QueryRouter returns a default query router,
 // allowing access to "/wallets", "/auth", and "/"
 func QueryRouter() weave.QueryRouter {
@@ -75,7 +173,15 @@ func QueryRouter() weave.QueryRouter {
 	r.RegisterAll(
 		validators.RegisterQuery,
 		cash.RegisterQuery,
+		currency.RegisterQuery,
 		sigs.RegisterQuery,
+		multisig.RegisterQuery,
+		escrow.RegisterQuery,
+		htlc.RegisterQuery,
+		staking.RegisterQuery,
+		distribution.RegisterQuery,
+		scheduler.RegisterQuery,
+		migration.RegisterQuery,
 		orm.RegisterQuery,
 	)
 	return r
@@ -91,27 +197,40 @@ func Stack(minFee x.Coin) weave.Handler {
 
 // Application constructs a basic ABCI application with
 // the given arguments. If you are not sure what to use
-// for the Handler, just use Stack().
+// for the Handler, just use Stack(), and DefaultConfig()
+// for the config.
 func Application(name string, h weave.Handler,
-	tx weave.TxDecoder, dbPath string) (app.BaseApp, error) {
+	tx weave.TxDecoder, dbPath string, conf Config) (app.BaseApp, error) {
 
 	ctx := context.Background()
 	// ctx = context.WithValue(ctx, "app", name)
-	kv, err := CommitKVStore(dbPath)
+	kv, err := CommitKVStore(dbPath, conf)
 	if err != nil {
 		return app.BaseApp{}, err
 	}
-	store := app.NewStoreApp(name, kv, QueryRouter(), ctx)
-	base := app.NewBaseApp(store, tx, h, nil)
+	store := app.NewStoreApp(name, kv, QueryRouter(), ctx).
+		WithValidators(validators.NewValidatorSet()).
+		WithInvariants(Invariants())
+	base := app.NewBaseApp(store, tx, h, Ticker()).
+		WithEndBlocker(EndBlocker())
 	return base, nil
 }
 
 // CommitKVStore returns an initialized KVStore that persists
-// the data to the named path.
-func CommitKVStore(dbPath string) (weave.CommitKVStore, error) {
+// the data to the named path, with the backend and pruning
+// strategy of the config.
+func CommitKVStore(dbPath string, conf Config) (weave.CommitKVStore, error) {
+	err := conf.Validate()
+	if err != nil {
+		return nil, err
+	}
+
 	// memory backed case, just for testing
 	if dbPath == "" {
-		return iavl.MockCommitStore(), nil
+		if conf.Backend == BackendLevelDB {
+			return leveldb.MockCommitStore(), nil
+		}
+		return iavl.MockCommitStore().WithPruning(conf.Pruning), nil
 	}
 
 	// Expand the path fully
@@ -126,5 +245,8 @@ func CommitKVStore(dbPath string) (weave.CommitKVStore, error) {
 	// Split the database name into it's components (dir, name)
 	dir := filepath.Dir(path)
 	name := filepath.Base(path)
-	return iavl.NewCommitStore(dir, name), nil
+	if conf.Backend == BackendLevelDB {
+		return leveldb.NewCommitStore(dir, name), nil
+	}
+	return iavl.NewCommitStore(dir, name).WithPruning(conf.Pruning), nil
 }
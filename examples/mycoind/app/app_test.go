@@ -2,6 +2,7 @@ package app
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -14,9 +15,16 @@ import (
 	"github.com/confio/weave"
 	"github.com/confio/weave/app"
 	"github.com/confio/weave/crypto"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store/iavl"
 	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/batch"
 	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/escrow"
+	"github.com/confio/weave/x/multisig"
 	"github.com/confio/weave/x/sigs"
+	"github.com/confio/weave/x/staking"
+	"github.com/confio/weave/x/validators"
 )
 
 func testInitChain(t *testing.T, myApp app.BaseApp, addr string) {
@@ -32,6 +40,15 @@ func testInitChain(t *testing.T, myApp app.BaseApp, addr string) {
                     "whole": 1234,
                     "ticker": "FRNK"
                 }]
+            }],
+            "currencies": [{
+                "ticker": "ETH",
+                "name": "Ether",
+                "decimals": 9
+                }, {
+                "ticker": "FRNK",
+                "name": "Frank",
+                "decimals": 9
             }]}`, addr)
 	assert.Equal(t, "", myApp.GetChainID())
 	myApp.InitChain(abci.RequestInitChain{AppStateBytes: []byte(appState), ChainId: chainID})
@@ -52,13 +69,24 @@ func testCommit(t *testing.T, myApp app.BaseApp, h int64) []byte {
 }
 
 func testQuery(t *testing.T, myApp app.BaseApp, path string, key []byte, obj weave.Persistent) {
+	testQueryAt(t, myApp, path, key, 0, obj)
+}
+
+// testQueryAt queries the state committed at the given height
+func testQueryAt(t *testing.T, myApp app.BaseApp, path string, key []byte,
+	height int64, obj weave.Persistent) {
+
 	// Query for my balance
 	query := abci.RequestQuery{
-		Path: path,
-		Data: key,
+		Path:   path,
+		Data:   key,
+		Height: height,
 	}
 	qres := myApp.Query(query)
 	require.Equal(t, uint32(0), qres.Code, "%#v", qres)
+	if height != 0 {
+		assert.Equal(t, height, qres.Height)
+	}
 	assert.NotEmpty(t, qres.Value)
 	if path == "/" {
 		// the original key will be embedded in a result set
@@ -100,11 +128,41 @@ func testSendTx(t *testing.T, myApp app.BaseApp, h int64,
 	// check and deliver must pass
 	chres := myApp.CheckTx(txBytes)
 	require.Equal(t, uint32(0), chres.Code, chres.Log)
+	assert.Equal(t, TxGasLimit, chres.GasWanted)
 	dres := myApp.DeliverTx(txBytes)
 	require.Equal(t, uint32(0), dres.Code, dres.Log)
+	assert.True(t, dres.GasUsed > 0)
+	assert.True(t, dres.GasUsed <= TxGasLimit)
 	return dres
 }
 
+// TestLevelDBBackend runs the app on a plain leveldb,
+// selected in the config
+func TestLevelDBBackend(t *testing.T) {
+	conf := DefaultConfig()
+	conf.Backend = BackendLevelDB
+	myApp, err := Application("mycoin", Stack(x.Coin{}), TxDecoder, "", conf)
+	require.NoError(t, err)
+	myApp.WithInit(Initializers())
+
+	pk := crypto.GenPrivKeyEd25519()
+	addr := pk.PublicKey().Address()
+	testInitChain(t, myApp, addr.String())
+	testCommit(t, myApp, 1)
+
+	var acct cash.Set
+	testQuery(t, myApp, "/", cash.NewBucket().DBKey(addr), &acct)
+	require.Equal(t, 2, len(acct.Coins))
+
+	// there are no proofs
+	qres := myApp.Query(abci.RequestQuery{
+		Path:  "/",
+		Data:  cash.NewBucket().DBKey(addr),
+		Prove: true,
+	})
+	assert.NotEqual(t, uint32(0), qres.Code)
+}
+
 func TestApp(t *testing.T) {
 	// no minimum fee, in-memory data-store
 	abciApp, err := GenerateApp("", log.NewNopLogger())
@@ -197,4 +255,437 @@ func TestApp(t *testing.T) {
 	assert.Equal(t, "ETH", second.Coins[0].Ticker)
 	assert.Equal(t, int64(100), second.Coins[1].Whole)
 	assert.Equal(t, "FRNK", second.Coins[1].Ticker)
+
+	// we can still query the state before the second send
+	var old cash.Set
+	testQueryAt(t, myApp, "/wallets", addr2, 2, &old)
+	require.Equal(t, 1, len(old.Coins))
+	assert.Equal(t, int64(2000), old.Coins[0].Whole)
+
+	// but not the future
+	qres := myApp.Query(abci.RequestQuery{Path: "/wallets", Data: addr2, Height: 7})
+	assert.Equal(t, uint32(errors.CodeUnknownRequest), qres.Code)
+
+	// all results can be proven against the app hash
+	wallet := cash.NewBucket().DBKey
+	testProvenQuery(t, myApp, "/wallets", addr2, 3, hash3,
+		iavl.KeyDomain(wallet(addr2)))
+	testProvenQuery(t, myApp, "/wallets?prefix", addr[:4], 0, hash3,
+		iavl.RangeDomain(wallet(addr[:4]), prefixEnd(wallet(addr[:4]))))
+	testProvenQuery(t, myApp, "/wallets", addr, 2, hash2,
+		iavl.KeyDomain(wallet(addr)))
+
+	// page through all wallets one by one
+	var wallets []weave.Model
+	path := "/wallets?prefix&limit=1"
+	for len(wallets) < 5 {
+		qres := myApp.Query(abci.RequestQuery{Path: path})
+		require.Equal(t, uint32(0), qres.Code, "%#v", qres)
+		var keys, values app.ResultSet
+		require.NoError(t, keys.Unmarshal(qres.Key))
+		require.NoError(t, values.Unmarshal(qres.Value))
+		models, err := app.JoinResults(&keys, &values)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(models))
+		wallets = append(wallets, models...)
+		if len(keys.Next) == 0 {
+			break
+		}
+		path = fmt.Sprintf("/wallets?prefix&limit=1&cursor=%X", keys.Next)
+	}
+	assert.Equal(t, 2, len(wallets))
+
+	// scripts can get readable json, with addresses in hex
+	qres = myApp.Query(abci.RequestQuery{Path: "/wallets?json", Data: addr2})
+	require.Equal(t, uint32(0), qres.Code, "%#v", qres)
+	var values app.ResultSet
+	require.NoError(t, values.Unmarshal(qres.Value))
+	require.Equal(t, 1, len(values.Results))
+	assert.JSONEq(t, `{"coins":[{"whole":2000,"ticker":"ETH"},{"whole":100,"ticker":"FRNK"}]}`,
+		string(values.Results[0]))
+	qres = myApp.Query(abci.RequestQuery{Path: "/currencies?prefix&json"})
+	require.Equal(t, uint32(0), qres.Code, "%#v", qres)
+	var currencies app.ResultSet
+	require.NoError(t, currencies.Unmarshal(qres.Value))
+	require.Equal(t, 2, len(currencies.Results))
+	assert.Contains(t, string(currencies.Results[1]), `"name":"Frank"`)
+	// raw keys have no type
+	qres = myApp.Query(abci.RequestQuery{Path: "/?json", Data: addr})
+	assert.Equal(t, uint32(errors.CodeUnknownRequest), qres.Code)
+}
+
+// testProvenQuery makes a query with a proof and verifies the
+// results against the app hash of that height, and that they
+// hold everything in domain
+func testProvenQuery(t *testing.T, myApp app.BaseApp, path string, data []byte,
+	height int64, hash []byte, domain iavl.Domain) {
+
+	query := abci.RequestQuery{
+		Path:   path,
+		Data:   data,
+		Height: height,
+		Prove:  true,
+	}
+	qres := myApp.Query(query)
+	require.Equal(t, uint32(0), qres.Code, "%#v", qres)
+	require.NotEmpty(t, qres.Proof)
+
+	var keys, values app.ResultSet
+	require.NoError(t, keys.Unmarshal(qres.Key))
+	require.NoError(t, values.Unmarshal(qres.Value))
+	models, err := app.JoinResults(&keys, &values)
+	require.NoError(t, err)
+	require.NotEmpty(t, models)
+
+	err = iavl.VerifyResults(qres.Proof, hash, domain, models)
+	assert.NoError(t, err)
+}
+
+// prefixEnd returns the first key after all keys with this prefix
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+func TestSecp256k1(t *testing.T) {
+	abciApp, err := GenerateApp("", log.NewNopLogger())
+	require.NoError(t, err)
+	myApp := abciApp.(app.BaseApp)
+
+	// secp256k1 keys can hold and send coins like any other
+	pk := crypto.GenPrivKeySecp256k1()
+	addr := pk.PublicKey().Address()
+	testInitChain(t, myApp, addr.String())
+	testCommit(t, myApp, 1)
+
+	addr2 := crypto.GenPrivKeySecp256k1().PublicKey().Address()
+	testSendTx(t, myApp, 2, 2000, "ETH", pk, addr2, 0)
+	testSendTx(t, myApp, 2, 100, "FRNK", pk, addr2, 1)
+	testCommit(t, myApp, 2)
+
+	var acct cash.Set
+	testQuery(t, myApp, "/wallets", addr2, &acct)
+	require.Equal(t, 2, len(acct.Coins))
+	assert.Equal(t, int64(2000), acct.Coins[0].Whole)
+	assert.Equal(t, int64(100), acct.Coins[1].Whole)
+
+	// and the pubkey is stored with the sequence
+	var user sigs.UserData
+	testQuery(t, myApp, "/auth", addr, &user)
+	assert.Equal(t, int64(2), user.Sequence)
+	assert.Equal(t, pk.PublicKey(), user.PubKey)
+}
+
+func TestValidatorSet(t *testing.T) {
+	abciApp, err := GenerateApp("", log.NewNopLogger())
+	require.NoError(t, err)
+	myApp := abciApp.(app.BaseApp)
+
+	val := func(key string, power int64) abci.Validator {
+		return abci.Validator{
+			PubKey: abci.PubKey{Type: "ed25519", Data: []byte(key)},
+			Power:  power,
+		}
+	}
+
+	// the genesis validators are stored
+	myApp.InitChain(abci.RequestInitChain{
+		AppStateBytes: []byte(`{}`),
+		ChainId:       "test-valset",
+		Validators:    []abci.Validator{val("foo", 10), val("bar", 20)},
+	})
+	testCommit(t, myApp, 1)
+
+	var vals []validators.Validator
+	load := func() {
+		vals = nil
+		qres := myApp.Query(abci.RequestQuery{Path: "/validators/set?prefix"})
+		require.Equal(t, uint32(0), qres.Code, "%#v", qres)
+		var keys, values app.ResultSet
+		require.NoError(t, keys.Unmarshal(qres.Key))
+		require.NoError(t, values.Unmarshal(qres.Value))
+		models, err := app.JoinResults(&keys, &values)
+		require.NoError(t, err)
+		for _, m := range models {
+			var v validators.Validator
+			require.NoError(t, v.Unmarshal(m.Value))
+			vals = append(vals, v)
+		}
+	}
+	load()
+	require.Equal(t, 2, len(vals))
+	assert.Equal(t, []byte("bar"), vals[0].PubKey.Data)
+	assert.Equal(t, int64(20), vals[0].Power)
+	assert.Equal(t, int64(10), vals[1].Power)
+
+	// and so is every change
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	myApp.AddValChange([]abci.Validator{val("foo", 0), val("baz", 5)})
+	eres := myApp.EndBlock(abci.RequestEndBlock{Height: 2})
+	assert.Equal(t, 2, len(eres.ValidatorUpdates))
+	myApp.Commit()
+
+	load()
+	require.Equal(t, 2, len(vals))
+	assert.Equal(t, []byte("bar"), vals[0].PubKey.Data)
+	assert.Equal(t, []byte("baz"), vals[1].PubKey.Data)
+	assert.Equal(t, int64(5), vals[1].Power)
+}
+
+func TestBatch(t *testing.T) {
+	abciApp, err := GenerateApp("", log.NewNopLogger())
+	require.NoError(t, err)
+	myApp := abciApp.(app.BaseApp)
+
+	pk := crypto.GenPrivKeyEd25519()
+	addr := pk.PublicKey().Address()
+	testInitChain(t, myApp, addr.String())
+	testCommit(t, myApp, 1)
+
+	addr2 := crypto.GenPrivKeyEd25519().PublicKey().Address()
+	addr3 := crypto.GenPrivKeyEd25519().PublicKey().Address()
+	send := func(rcpt weave.Address, amount int64) *BatchMsg_Union {
+		msg := &cash.SendMsg{
+			Src:    addr,
+			Dest:   rcpt,
+			Amount: &x.Coin{Whole: amount, Ticker: "ETH"},
+		}
+		return &BatchMsg_Union{Sum: &BatchMsg_Union_SendMsg{msg}}
+	}
+
+	// both sends happen with one signature
+	msg := &BatchMsg{Messages: []*BatchMsg_Union{send(addr2, 1000), send(addr3, 2000)}}
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	dres := testSignAndDeliver(t, myApp, &Tx{Sum: &Tx_BatchMsg{msg}}, pk, 0)
+	require.Equal(t, uint32(0), dres.Code, dres.Log)
+	// three wallets and one nonce were touched
+	assert.Equal(t, 4, len(dres.Tags), "%#v", dres.Tags)
+	var data batch.ByteArrayList
+	require.NoError(t, data.Unmarshal(dres.Data))
+	assert.Equal(t, 2, len(data.Elements))
+	testCommit(t, myApp, 2)
+
+	var acct, acct3 cash.Set
+	testQuery(t, myApp, "/wallets", addr, &acct)
+	assert.Equal(t, int64(47000), acct.Coins[0].Whole)
+	testQuery(t, myApp, "/wallets", addr3, &acct3)
+	assert.Equal(t, int64(2000), acct3.Coins[0].Whole)
+
+	// if the second send fails, the first one is reverted
+	msg = &BatchMsg{Messages: []*BatchMsg_Union{send(addr2, 1000), send(addr3, 100000)}}
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 3}})
+	dres = testSignAndDeliver(t, myApp, &Tx{Sum: &Tx_BatchMsg{msg}}, pk, 1)
+	assert.Equal(t, uint32(cash.CodeInsufficientFunds), dres.Code, dres.Log)
+	testCommit(t, myApp, 3)
+
+	var after, after2 cash.Set
+	testQuery(t, myApp, "/wallets", addr2, &after2)
+	assert.Equal(t, int64(1000), after2.Coins[0].Whole)
+	testQuery(t, myApp, "/wallets", addr, &after)
+	assert.Equal(t, int64(47000), after.Coins[0].Whole)
+}
+
+// testSignAndDeliver signs the tx, makes sure it passes
+// CheckTx, and returns the result of DeliverTx
+func testSignAndDeliver(t *testing.T, myApp app.BaseApp, tx *Tx,
+	sender *crypto.PrivateKey, seq int64) abci.ResponseDeliverTx {
+
+	sig, err := sigs.SignTx(sender, tx, myApp.GetChainID(), seq)
+	require.NoError(t, err)
+	tx.Signatures = []*sigs.StdSignature{sig}
+	txBytes, err := tx.Marshal()
+	require.NoError(t, err)
+
+	chres := myApp.CheckTx(txBytes)
+	require.Equal(t, uint32(0), chres.Code, chres.Log)
+	return myApp.DeliverTx(txBytes)
+}
+
+func TestMultisig(t *testing.T) {
+	abciApp, err := GenerateApp("", log.NewNopLogger())
+	require.NoError(t, err)
+	myApp := abciApp.(app.BaseApp)
+
+	pk := crypto.GenPrivKeyEd25519()
+	addr := pk.PublicKey().Address()
+	testInitChain(t, myApp, addr.String())
+	testCommit(t, myApp, 1)
+
+	// create a 1 of 2 contract with another key
+	pk2 := crypto.GenPrivKeyEd25519()
+	create := &multisig.CreateContractMsg{
+		Sigs:                [][]byte{pk.PublicKey().Condition(), pk2.PublicKey().Condition()},
+		ActivationThreshold: 1,
+	}
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	dres := testSignAndDeliver(t, myApp, &Tx{Sum: &Tx_CreateContractMsg{create}}, pk, 0)
+	require.Equal(t, uint32(0), dres.Code, dres.Log)
+	id := dres.Data
+	require.NotEmpty(t, id)
+	testCommit(t, myApp, 2)
+
+	// fund the contract
+	contract := multisig.MultiSigCondition(id).Address()
+	testSendTx(t, myApp, 3, 5000, "ETH", pk, contract, 1)
+	testCommit(t, myApp, 3)
+
+	// the other signer can spend from it, when it names the contract
+	addr3 := crypto.GenPrivKeyEd25519().PublicKey().Address()
+	send := &cash.SendMsg{
+		Src:    contract,
+		Dest:   addr3,
+		Amount: &x.Coin{Whole: 1000, Ticker: "ETH"},
+	}
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 4}})
+	tx := &Tx{Sum: &Tx_SendMsg{send}, Multisig: [][]byte{id}}
+	dres = testSignAndDeliver(t, myApp, tx, pk2, 0)
+	require.Equal(t, uint32(0), dres.Code, dres.Log)
+	testCommit(t, myApp, 4)
+
+	var acct cash.Set
+	testQuery(t, myApp, "/wallets", contract, &acct)
+	assert.Equal(t, int64(4000), acct.Coins[0].Whole)
+
+	// but not without it
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 5}})
+	tx = &Tx{Sum: &Tx_SendMsg{send}}
+	sig, err := sigs.SignTx(pk2, tx, myApp.GetChainID(), 1)
+	require.NoError(t, err)
+	tx.Signatures = []*sigs.StdSignature{sig}
+	txBytes, err := tx.Marshal()
+	require.NoError(t, err)
+	chres := myApp.CheckTx(txBytes)
+	assert.Equal(t, uint32(errors.CodeUnauthorized), chres.Code, chres.Log)
+}
+
+func TestExportState(t *testing.T) {
+	abciApp, err := GenerateApp("", log.NewNopLogger())
+	require.NoError(t, err)
+	myApp := abciApp.(app.BaseApp)
+
+	pk := crypto.GenPrivKeyEd25519()
+	addr := pk.PublicKey().Address()
+	testInitChain(t, myApp, addr.String())
+	testCommit(t, myApp, 1)
+
+	// a nonce and a contract, which live outside of genesis
+	create := &multisig.CreateContractMsg{
+		Sigs:                [][]byte{pk.PublicKey().Condition()},
+		ActivationThreshold: 1,
+	}
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 2}})
+	dres := testSignAndDeliver(t, myApp, &Tx{Sum: &Tx_CreateContractMsg{create}}, pk, 0)
+	require.Equal(t, uint32(0), dres.Code, dres.Log)
+	testCommit(t, myApp, 2)
+
+	opts, height, err := myApp.ExportState(0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), height)
+	for _, key := range []string{"cash", "currencies", "sigs", "multisig"} {
+		assert.NotEmpty(t, opts[key], key)
+	}
+	assert.Equal(t, "2", string(opts[weave.ExportHeightKey]))
+
+	// a new chain starts with the same state
+	abciApp, err = GenerateApp("", log.NewNopLogger())
+	require.NoError(t, err)
+	newApp := abciApp.(app.BaseApp)
+	state, err := json.Marshal(opts)
+	require.NoError(t, err)
+	newApp.InitChain(abci.RequestInitChain{AppStateBytes: state, ChainId: "test-net-23"})
+	testCommit(t, newApp, 1)
+	_, err = newApp.CheckState(0)
+	require.NoError(t, err)
+	exported, _, err := newApp.ExportState(0)
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(exported[weave.ExportHeightKey]))
+	delete(opts, weave.ExportHeightKey)
+	delete(exported, weave.ExportHeightKey)
+	assert.Equal(t, opts, exported)
+}
+
+// TestExportHeights makes sure a timeout or unbonding that is
+// pending on export is as many blocks away on the new chain,
+// which starts again at height 1
+func TestExportHeights(t *testing.T) {
+	abciApp, err := GenerateApp("", log.NewNopLogger())
+	require.NoError(t, err)
+	myApp := abciApp.(app.BaseApp)
+
+	pk := crypto.GenPrivKeyEd25519()
+	addr := pk.PublicKey().Address()
+	appState := fmt.Sprintf(`{
+            "cash": [{"address": "%s", "coins": [{"whole": 500, "ticker": "ETH"}]}],
+            "currencies": [{"ticker": "ETH", "name": "Ether", "decimals": 9}],
+            "staking": {"ticker": "ETH", "unbonding_blocks": 10}
+        }`, addr)
+	myApp.InitChain(abci.RequestInitChain{AppStateBytes: []byte(appState), ChainId: "test-net-22"})
+	for h := int64(1); h < 4; h++ {
+		testCommit(t, myApp, h)
+	}
+
+	// an escrow until 12, and at height 5 an unbonding until 15
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 4}})
+	coin := x.NewCoin(100, 0, "ETH")
+	create := &escrow.CreateEscrowMsg{
+		Arbiter:   addr,
+		Recipient: crypto.GenPrivKeyEd25519().PublicKey().Address(),
+		Amount:    []*x.Coin{&coin},
+		Timeout:   12,
+	}
+	dres := testSignAndDeliver(t, myApp, &Tx{Sum: &Tx_CreateEscrowMsg{create}}, pk, 0)
+	require.Equal(t, uint32(0), dres.Code, dres.Log)
+	escrowID := dres.Data
+	val := &validators.PubKey{Type: validators.KeyTypeEd25519, Data: make([]byte, 32)}
+	bond := &staking.BondMsg{Validator: val, Amount: &coin}
+	dres = testSignAndDeliver(t, myApp, &Tx{Sum: &Tx_BondMsg{bond}}, pk, 1)
+	require.Equal(t, uint32(0), dres.Code, dres.Log)
+	myApp.EndBlock(abci.RequestEndBlock{})
+	myApp.Commit()
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 5}})
+	unbond := &staking.UnbondMsg{Validator: val, Amount: &coin}
+	dres = testSignAndDeliver(t, myApp, &Tx{Sum: &Tx_UnbondMsg{unbond}}, pk, 2)
+	require.Equal(t, uint32(0), dres.Code, dres.Log)
+	unbondingID := dres.Data
+	myApp.EndBlock(abci.RequestEndBlock{})
+	myApp.Commit()
+
+	opts, height, err := myApp.ExportState(0)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), height)
+	abciApp, err = GenerateApp("", log.NewNopLogger())
+	require.NoError(t, err)
+	newApp := abciApp.(app.BaseApp)
+	state, err := json.Marshal(opts)
+	require.NoError(t, err)
+	newApp.InitChain(abci.RequestInitChain{AppStateBytes: state, ChainId: "test-net-23"})
+
+	// all heights moved back by 5
+	db := newApp.DeliverStore()
+	obj, err := escrow.NewBucket().Get(db, escrowID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), escrow.AsEscrow(obj).Timeout)
+	obj, err = staking.NewUnbondingBucket().Get(db, unbondingID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), staking.AsUnbonding(obj).ReleaseHeight)
+
+	// so the coins are paid out at height 10
+	for h := int64(1); h < 10; h++ {
+		testCommit(t, newApp, h)
+	}
+	obj, err = staking.NewUnbondingBucket().Get(newApp.DeliverStore(), unbondingID)
+	require.NoError(t, err)
+	assert.NotNil(t, obj)
+	testCommit(t, newApp, 10)
+	obj, err = staking.NewUnbondingBucket().Get(newApp.DeliverStore(), unbondingID)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+	_, err = newApp.CheckState(0)
+	require.NoError(t, err)
 }
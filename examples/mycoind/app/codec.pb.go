@@ -1,14 +1,15 @@
 // Code generated by protoc-gen-gogo. DO NOT EDIT.
-// source: app/codec.proto
+// source: examples/mycoind/app/codec.proto
 
 /*
 	Package app is a generated protocol buffer package.
 
 	It is generated from these files:
-		app/codec.proto
+		examples/mycoind/app/codec.proto
 
 	It has these top-level messages:
 		Tx
+		BatchMsg
 */
 package app
 
@@ -16,7 +17,12 @@ import proto "github.com/gogo/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import cash "github.com/confio/weave/x/cash"
+import currency "github.com/confio/weave/x/currency"
+import escrow "github.com/confio/weave/x/escrow"
+import htlc "github.com/confio/weave/x/htlc"
+import multisig "github.com/confio/weave/x/multisig"
 import sigs "github.com/confio/weave/x/sigs"
+import staking "github.com/confio/weave/x/staking"
 
 import io "io"
 
@@ -37,11 +43,27 @@ type Tx struct {
 	//
 	// Types that are valid to be assigned to Sum:
 	//	*Tx_SendMsg
+	//	*Tx_BatchMsg
+	//	*Tx_CreateContractMsg
+	//	*Tx_CreateEscrowMsg
+	//	*Tx_ReleaseEscrowMsg
+	//	*Tx_ReturnEscrowMsg
+	//	*Tx_UpdateEscrowMsg
+	//	*Tx_CreateSwapMsg
+	//	*Tx_ReleaseSwapMsg
+	//	*Tx_ReturnSwapMsg
+	//	*Tx_BondMsg
+	//	*Tx_UnbondMsg
+	//	*Tx_RegisterCurrencyMsg
+	//	*Tx_MintMsg
+	//	*Tx_BurnMsg
 	Sum isTx_Sum `protobuf_oneof:"sum"`
 	// fee info, autogenerates GetFees()
 	Fees *cash.FeeInfo `protobuf:"bytes,20,opt,name=fees" json:"fees,omitempty"`
 	// signatures, autogenerates GetSignatures()
 	Signatures []*sigs.StdSignature `protobuf:"bytes,21,rep,name=signatures" json:"signatures,omitempty"`
+	// multisig contracts to act as, autogenerates GetMultisig()
+	Multisig [][]byte `protobuf:"bytes,22,rep,name=multisig" json:"multisig,omitempty"`
 }
 
 func (m *Tx) Reset()                    { *m = Tx{} }
@@ -58,8 +80,64 @@ type isTx_Sum interface {
 type Tx_SendMsg struct {
 	SendMsg *cash.SendMsg `protobuf:"bytes,1,opt,name=send_msg,json=sendMsg,oneof"`
 }
+type Tx_BatchMsg struct {
+	BatchMsg *BatchMsg `protobuf:"bytes,2,opt,name=batch_msg,json=batchMsg,oneof"`
+}
+type Tx_CreateContractMsg struct {
+	CreateContractMsg *multisig.CreateContractMsg `protobuf:"bytes,3,opt,name=create_contract_msg,json=createContractMsg,oneof"`
+}
+type Tx_CreateEscrowMsg struct {
+	CreateEscrowMsg *escrow.CreateEscrowMsg `protobuf:"bytes,4,opt,name=create_escrow_msg,json=createEscrowMsg,oneof"`
+}
+type Tx_ReleaseEscrowMsg struct {
+	ReleaseEscrowMsg *escrow.ReleaseEscrowMsg `protobuf:"bytes,5,opt,name=release_escrow_msg,json=releaseEscrowMsg,oneof"`
+}
+type Tx_ReturnEscrowMsg struct {
+	ReturnEscrowMsg *escrow.ReturnEscrowMsg `protobuf:"bytes,6,opt,name=return_escrow_msg,json=returnEscrowMsg,oneof"`
+}
+type Tx_UpdateEscrowMsg struct {
+	UpdateEscrowMsg *escrow.UpdateEscrowPartiesMsg `protobuf:"bytes,7,opt,name=update_escrow_msg,json=updateEscrowMsg,oneof"`
+}
+type Tx_CreateSwapMsg struct {
+	CreateSwapMsg *htlc.CreateSwapMsg `protobuf:"bytes,8,opt,name=create_swap_msg,json=createSwapMsg,oneof"`
+}
+type Tx_ReleaseSwapMsg struct {
+	ReleaseSwapMsg *htlc.ReleaseSwapMsg `protobuf:"bytes,9,opt,name=release_swap_msg,json=releaseSwapMsg,oneof"`
+}
+type Tx_ReturnSwapMsg struct {
+	ReturnSwapMsg *htlc.ReturnSwapMsg `protobuf:"bytes,10,opt,name=return_swap_msg,json=returnSwapMsg,oneof"`
+}
+type Tx_BondMsg struct {
+	BondMsg *staking.BondMsg `protobuf:"bytes,11,opt,name=bond_msg,json=bondMsg,oneof"`
+}
+type Tx_UnbondMsg struct {
+	UnbondMsg *staking.UnbondMsg `protobuf:"bytes,12,opt,name=unbond_msg,json=unbondMsg,oneof"`
+}
+type Tx_RegisterCurrencyMsg struct {
+	RegisterCurrencyMsg *currency.RegisterCurrencyMsg `protobuf:"bytes,13,opt,name=register_currency_msg,json=registerCurrencyMsg,oneof"`
+}
+type Tx_MintMsg struct {
+	MintMsg *currency.MintMsg `protobuf:"bytes,14,opt,name=mint_msg,json=mintMsg,oneof"`
+}
+type Tx_BurnMsg struct {
+	BurnMsg *currency.BurnMsg `protobuf:"bytes,15,opt,name=burn_msg,json=burnMsg,oneof"`
+}
 
-func (*Tx_SendMsg) isTx_Sum() {}
+func (*Tx_SendMsg) isTx_Sum()             {}
+func (*Tx_BatchMsg) isTx_Sum()            {}
+func (*Tx_CreateContractMsg) isTx_Sum()   {}
+func (*Tx_CreateEscrowMsg) isTx_Sum()     {}
+func (*Tx_ReleaseEscrowMsg) isTx_Sum()    {}
+func (*Tx_ReturnEscrowMsg) isTx_Sum()     {}
+func (*Tx_UpdateEscrowMsg) isTx_Sum()     {}
+func (*Tx_CreateSwapMsg) isTx_Sum()       {}
+func (*Tx_ReleaseSwapMsg) isTx_Sum()      {}
+func (*Tx_ReturnSwapMsg) isTx_Sum()       {}
+func (*Tx_BondMsg) isTx_Sum()             {}
+func (*Tx_UnbondMsg) isTx_Sum()           {}
+func (*Tx_RegisterCurrencyMsg) isTx_Sum() {}
+func (*Tx_MintMsg) isTx_Sum()             {}
+func (*Tx_BurnMsg) isTx_Sum()             {}
 
 func (m *Tx) GetSum() isTx_Sum {
 	if m != nil {
@@ -75,6 +153,104 @@ func (m *Tx) GetSendMsg() *cash.SendMsg {
 	return nil
 }
 
+func (m *Tx) GetBatchMsg() *BatchMsg {
+	if x, ok := m.GetSum().(*Tx_BatchMsg); ok {
+		return x.BatchMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCreateContractMsg() *multisig.CreateContractMsg {
+	if x, ok := m.GetSum().(*Tx_CreateContractMsg); ok {
+		return x.CreateContractMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCreateEscrowMsg() *escrow.CreateEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_CreateEscrowMsg); ok {
+		return x.CreateEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetReleaseEscrowMsg() *escrow.ReleaseEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_ReleaseEscrowMsg); ok {
+		return x.ReleaseEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetReturnEscrowMsg() *escrow.ReturnEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_ReturnEscrowMsg); ok {
+		return x.ReturnEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetUpdateEscrowMsg() *escrow.UpdateEscrowPartiesMsg {
+	if x, ok := m.GetSum().(*Tx_UpdateEscrowMsg); ok {
+		return x.UpdateEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCreateSwapMsg() *htlc.CreateSwapMsg {
+	if x, ok := m.GetSum().(*Tx_CreateSwapMsg); ok {
+		return x.CreateSwapMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetReleaseSwapMsg() *htlc.ReleaseSwapMsg {
+	if x, ok := m.GetSum().(*Tx_ReleaseSwapMsg); ok {
+		return x.ReleaseSwapMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetReturnSwapMsg() *htlc.ReturnSwapMsg {
+	if x, ok := m.GetSum().(*Tx_ReturnSwapMsg); ok {
+		return x.ReturnSwapMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetBondMsg() *staking.BondMsg {
+	if x, ok := m.GetSum().(*Tx_BondMsg); ok {
+		return x.BondMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetUnbondMsg() *staking.UnbondMsg {
+	if x, ok := m.GetSum().(*Tx_UnbondMsg); ok {
+		return x.UnbondMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetRegisterCurrencyMsg() *currency.RegisterCurrencyMsg {
+	if x, ok := m.GetSum().(*Tx_RegisterCurrencyMsg); ok {
+		return x.RegisterCurrencyMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetMintMsg() *currency.MintMsg {
+	if x, ok := m.GetSum().(*Tx_MintMsg); ok {
+		return x.MintMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetBurnMsg() *currency.BurnMsg {
+	if x, ok := m.GetSum().(*Tx_BurnMsg); ok {
+		return x.BurnMsg
+	}
+	return nil
+}
+
 func (m *Tx) GetFees() *cash.FeeInfo {
 	if m != nil {
 		return m.Fees
@@ -89,10 +265,31 @@ func (m *Tx) GetSignatures() []*sigs.StdSignature {
 	return nil
 }
 
+func (m *Tx) GetMultisig() [][]byte {
+	if m != nil {
+		return m.Multisig
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Tx) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Tx_OneofMarshaler, _Tx_OneofUnmarshaler, _Tx_OneofSizer, []interface{}{
 		(*Tx_SendMsg)(nil),
+		(*Tx_BatchMsg)(nil),
+		(*Tx_CreateContractMsg)(nil),
+		(*Tx_CreateEscrowMsg)(nil),
+		(*Tx_ReleaseEscrowMsg)(nil),
+		(*Tx_ReturnEscrowMsg)(nil),
+		(*Tx_UpdateEscrowMsg)(nil),
+		(*Tx_CreateSwapMsg)(nil),
+		(*Tx_ReleaseSwapMsg)(nil),
+		(*Tx_ReturnSwapMsg)(nil),
+		(*Tx_BondMsg)(nil),
+		(*Tx_UnbondMsg)(nil),
+		(*Tx_RegisterCurrencyMsg)(nil),
+		(*Tx_MintMsg)(nil),
+		(*Tx_BurnMsg)(nil),
 	}
 }
 
@@ -105,6 +302,76 @@ func _Tx_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.SendMsg); err != nil {
 			return err
 		}
+	case *Tx_BatchMsg:
+		_ = b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BatchMsg); err != nil {
+			return err
+		}
+	case *Tx_CreateContractMsg:
+		_ = b.EncodeVarint(3<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateContractMsg); err != nil {
+			return err
+		}
+	case *Tx_CreateEscrowMsg:
+		_ = b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_ReleaseEscrowMsg:
+		_ = b.EncodeVarint(5<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReleaseEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_ReturnEscrowMsg:
+		_ = b.EncodeVarint(6<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReturnEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_UpdateEscrowMsg:
+		_ = b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.UpdateEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_CreateSwapMsg:
+		_ = b.EncodeVarint(8<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateSwapMsg); err != nil {
+			return err
+		}
+	case *Tx_ReleaseSwapMsg:
+		_ = b.EncodeVarint(9<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReleaseSwapMsg); err != nil {
+			return err
+		}
+	case *Tx_ReturnSwapMsg:
+		_ = b.EncodeVarint(10<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReturnSwapMsg); err != nil {
+			return err
+		}
+	case *Tx_BondMsg:
+		_ = b.EncodeVarint(11<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BondMsg); err != nil {
+			return err
+		}
+	case *Tx_UnbondMsg:
+		_ = b.EncodeVarint(12<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.UnbondMsg); err != nil {
+			return err
+		}
+	case *Tx_RegisterCurrencyMsg:
+		_ = b.EncodeVarint(13<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.RegisterCurrencyMsg); err != nil {
+			return err
+		}
+	case *Tx_MintMsg:
+		_ = b.EncodeVarint(14<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.MintMsg); err != nil {
+			return err
+		}
+	case *Tx_BurnMsg:
+		_ = b.EncodeVarint(15<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BurnMsg); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("Tx.Sum has unexpected type %T", x)
@@ -123,6 +390,118 @@ func _Tx_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bo
 		err := b.DecodeMessage(msg)
 		m.Sum = &Tx_SendMsg{msg}
 		return true, err
+	case 2: // sum.batch_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(BatchMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_BatchMsg{msg}
+		return true, err
+	case 3: // sum.create_contract_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(multisig.CreateContractMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CreateContractMsg{msg}
+		return true, err
+	case 4: // sum.create_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.CreateEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CreateEscrowMsg{msg}
+		return true, err
+	case 5: // sum.release_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.ReleaseEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_ReleaseEscrowMsg{msg}
+		return true, err
+	case 6: // sum.return_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.ReturnEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_ReturnEscrowMsg{msg}
+		return true, err
+	case 7: // sum.update_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.UpdateEscrowPartiesMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_UpdateEscrowMsg{msg}
+		return true, err
+	case 8: // sum.create_swap_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(htlc.CreateSwapMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CreateSwapMsg{msg}
+		return true, err
+	case 9: // sum.release_swap_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(htlc.ReleaseSwapMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_ReleaseSwapMsg{msg}
+		return true, err
+	case 10: // sum.return_swap_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(htlc.ReturnSwapMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_ReturnSwapMsg{msg}
+		return true, err
+	case 11: // sum.bond_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(staking.BondMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_BondMsg{msg}
+		return true, err
+	case 12: // sum.unbond_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(staking.UnbondMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_UnbondMsg{msg}
+		return true, err
+	case 13: // sum.register_currency_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(currency.RegisterCurrencyMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_RegisterCurrencyMsg{msg}
+		return true, err
+	case 14: // sum.mint_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(currency.MintMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_MintMsg{msg}
+		return true, err
+	case 15: // sum.burn_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(currency.BurnMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_BurnMsg{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -137,6 +516,76 @@ func _Tx_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(1<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *Tx_BatchMsg:
+		s := proto.Size(x.BatchMsg)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateContractMsg:
+		s := proto.Size(x.CreateContractMsg)
+		n += proto.SizeVarint(3<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateEscrowMsg:
+		s := proto.Size(x.CreateEscrowMsg)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ReleaseEscrowMsg:
+		s := proto.Size(x.ReleaseEscrowMsg)
+		n += proto.SizeVarint(5<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ReturnEscrowMsg:
+		s := proto.Size(x.ReturnEscrowMsg)
+		n += proto.SizeVarint(6<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_UpdateEscrowMsg:
+		s := proto.Size(x.UpdateEscrowMsg)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateSwapMsg:
+		s := proto.Size(x.CreateSwapMsg)
+		n += proto.SizeVarint(8<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ReleaseSwapMsg:
+		s := proto.Size(x.ReleaseSwapMsg)
+		n += proto.SizeVarint(9<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ReturnSwapMsg:
+		s := proto.Size(x.ReturnSwapMsg)
+		n += proto.SizeVarint(10<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_BondMsg:
+		s := proto.Size(x.BondMsg)
+		n += proto.SizeVarint(11<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_UnbondMsg:
+		s := proto.Size(x.UnbondMsg)
+		n += proto.SizeVarint(12<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_RegisterCurrencyMsg:
+		s := proto.Size(x.RegisterCurrencyMsg)
+		n += proto.SizeVarint(13<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_MintMsg:
+		s := proto.Size(x.MintMsg)
+		n += proto.SizeVarint(14<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_BurnMsg:
+		s := proto.Size(x.BurnMsg)
+		n += proto.SizeVarint(15<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -144,126 +593,2082 @@ func _Tx_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
-func init() {
-	proto.RegisterType((*Tx)(nil), "app.Tx")
+// BatchMsg executes all messages in order, in one tx
+type BatchMsg struct {
+	Messages []*BatchMsg_Union `protobuf:"bytes,1,rep,name=messages" json:"messages,omitempty"`
 }
-func (m *Tx) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+
+func (m *BatchMsg) Reset()                    { *m = BatchMsg{} }
+func (m *BatchMsg) String() string            { return proto.CompactTextString(m) }
+func (*BatchMsg) ProtoMessage()               {}
+func (*BatchMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{1} }
+
+func (m *BatchMsg) GetMessages() []*BatchMsg_Union {
+	if m != nil {
+		return m.Messages
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *Tx) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Sum != nil {
-		nn1, err := m.Sum.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += nn1
-	}
-	if m.Fees != nil {
-		dAtA[i] = 0xa2
-		i++
-		dAtA[i] = 0x1
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.Fees.Size()))
-		n2, err := m.Fees.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += n2
-	}
-	if len(m.Signatures) > 0 {
-		for _, msg := range m.Signatures {
-			dAtA[i] = 0xaa
-			i++
-			dAtA[i] = 0x1
-			i++
-			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
-	}
-	return i, nil
+type BatchMsg_Union struct {
+	// sum is the same as in Tx, minus the batch itself
+	//
+	// Types that are valid to be assigned to Sum:
+	//	*BatchMsg_Union_SendMsg
+	//	*BatchMsg_Union_CreateContractMsg
+	//	*BatchMsg_Union_CreateEscrowMsg
+	//	*BatchMsg_Union_ReleaseEscrowMsg
+	//	*BatchMsg_Union_ReturnEscrowMsg
+	//	*BatchMsg_Union_UpdateEscrowMsg
+	//	*BatchMsg_Union_CreateSwapMsg
+	//	*BatchMsg_Union_ReleaseSwapMsg
+	//	*BatchMsg_Union_ReturnSwapMsg
+	//	*BatchMsg_Union_BondMsg
+	//	*BatchMsg_Union_UnbondMsg
+	//	*BatchMsg_Union_RegisterCurrencyMsg
+	//	*BatchMsg_Union_MintMsg
+	//	*BatchMsg_Union_BurnMsg
+	Sum isBatchMsg_Union_Sum `protobuf_oneof:"sum"`
 }
 
-func (m *Tx_SendMsg) MarshalTo(dAtA []byte) (int, error) {
+func (m *BatchMsg_Union) Reset()                    { *m = BatchMsg_Union{} }
+func (m *BatchMsg_Union) String() string            { return proto.CompactTextString(m) }
+func (*BatchMsg_Union) ProtoMessage()               {}
+func (*BatchMsg_Union) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{1, 0} }
+
+type isBatchMsg_Union_Sum interface {
+	isBatchMsg_Union_Sum()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type BatchMsg_Union_SendMsg struct {
+	SendMsg *cash.SendMsg `protobuf:"bytes,1,opt,name=send_msg,json=sendMsg,oneof"`
+}
+type BatchMsg_Union_CreateContractMsg struct {
+	CreateContractMsg *multisig.CreateContractMsg `protobuf:"bytes,3,opt,name=create_contract_msg,json=createContractMsg,oneof"`
+}
+type BatchMsg_Union_CreateEscrowMsg struct {
+	CreateEscrowMsg *escrow.CreateEscrowMsg `protobuf:"bytes,4,opt,name=create_escrow_msg,json=createEscrowMsg,oneof"`
+}
+type BatchMsg_Union_ReleaseEscrowMsg struct {
+	ReleaseEscrowMsg *escrow.ReleaseEscrowMsg `protobuf:"bytes,5,opt,name=release_escrow_msg,json=releaseEscrowMsg,oneof"`
+}
+type BatchMsg_Union_ReturnEscrowMsg struct {
+	ReturnEscrowMsg *escrow.ReturnEscrowMsg `protobuf:"bytes,6,opt,name=return_escrow_msg,json=returnEscrowMsg,oneof"`
+}
+type BatchMsg_Union_UpdateEscrowMsg struct {
+	UpdateEscrowMsg *escrow.UpdateEscrowPartiesMsg `protobuf:"bytes,7,opt,name=update_escrow_msg,json=updateEscrowMsg,oneof"`
+}
+type BatchMsg_Union_CreateSwapMsg struct {
+	CreateSwapMsg *htlc.CreateSwapMsg `protobuf:"bytes,8,opt,name=create_swap_msg,json=createSwapMsg,oneof"`
+}
+type BatchMsg_Union_ReleaseSwapMsg struct {
+	ReleaseSwapMsg *htlc.ReleaseSwapMsg `protobuf:"bytes,9,opt,name=release_swap_msg,json=releaseSwapMsg,oneof"`
+}
+type BatchMsg_Union_ReturnSwapMsg struct {
+	ReturnSwapMsg *htlc.ReturnSwapMsg `protobuf:"bytes,10,opt,name=return_swap_msg,json=returnSwapMsg,oneof"`
+}
+type BatchMsg_Union_BondMsg struct {
+	BondMsg *staking.BondMsg `protobuf:"bytes,11,opt,name=bond_msg,json=bondMsg,oneof"`
+}
+type BatchMsg_Union_UnbondMsg struct {
+	UnbondMsg *staking.UnbondMsg `protobuf:"bytes,12,opt,name=unbond_msg,json=unbondMsg,oneof"`
+}
+type BatchMsg_Union_RegisterCurrencyMsg struct {
+	RegisterCurrencyMsg *currency.RegisterCurrencyMsg `protobuf:"bytes,13,opt,name=register_currency_msg,json=registerCurrencyMsg,oneof"`
+}
+type BatchMsg_Union_MintMsg struct {
+	MintMsg *currency.MintMsg `protobuf:"bytes,14,opt,name=mint_msg,json=mintMsg,oneof"`
+}
+type BatchMsg_Union_BurnMsg struct {
+	BurnMsg *currency.BurnMsg `protobuf:"bytes,15,opt,name=burn_msg,json=burnMsg,oneof"`
+}
+
+func (*BatchMsg_Union_SendMsg) isBatchMsg_Union_Sum()             {}
+func (*BatchMsg_Union_CreateContractMsg) isBatchMsg_Union_Sum()   {}
+func (*BatchMsg_Union_CreateEscrowMsg) isBatchMsg_Union_Sum()     {}
+func (*BatchMsg_Union_ReleaseEscrowMsg) isBatchMsg_Union_Sum()    {}
+func (*BatchMsg_Union_ReturnEscrowMsg) isBatchMsg_Union_Sum()     {}
+func (*BatchMsg_Union_UpdateEscrowMsg) isBatchMsg_Union_Sum()     {}
+func (*BatchMsg_Union_CreateSwapMsg) isBatchMsg_Union_Sum()       {}
+func (*BatchMsg_Union_ReleaseSwapMsg) isBatchMsg_Union_Sum()      {}
+func (*BatchMsg_Union_ReturnSwapMsg) isBatchMsg_Union_Sum()       {}
+func (*BatchMsg_Union_BondMsg) isBatchMsg_Union_Sum()             {}
+func (*BatchMsg_Union_UnbondMsg) isBatchMsg_Union_Sum()           {}
+func (*BatchMsg_Union_RegisterCurrencyMsg) isBatchMsg_Union_Sum() {}
+func (*BatchMsg_Union_MintMsg) isBatchMsg_Union_Sum()             {}
+func (*BatchMsg_Union_BurnMsg) isBatchMsg_Union_Sum()             {}
+
+func (m *BatchMsg_Union) GetSum() isBatchMsg_Union_Sum {
+	if m != nil {
+		return m.Sum
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetSendMsg() *cash.SendMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_SendMsg); ok {
+		return x.SendMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetCreateContractMsg() *multisig.CreateContractMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_CreateContractMsg); ok {
+		return x.CreateContractMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetCreateEscrowMsg() *escrow.CreateEscrowMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_CreateEscrowMsg); ok {
+		return x.CreateEscrowMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetReleaseEscrowMsg() *escrow.ReleaseEscrowMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_ReleaseEscrowMsg); ok {
+		return x.ReleaseEscrowMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetReturnEscrowMsg() *escrow.ReturnEscrowMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_ReturnEscrowMsg); ok {
+		return x.ReturnEscrowMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetUpdateEscrowMsg() *escrow.UpdateEscrowPartiesMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_UpdateEscrowMsg); ok {
+		return x.UpdateEscrowMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetCreateSwapMsg() *htlc.CreateSwapMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_CreateSwapMsg); ok {
+		return x.CreateSwapMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetReleaseSwapMsg() *htlc.ReleaseSwapMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_ReleaseSwapMsg); ok {
+		return x.ReleaseSwapMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetReturnSwapMsg() *htlc.ReturnSwapMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_ReturnSwapMsg); ok {
+		return x.ReturnSwapMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetBondMsg() *staking.BondMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_BondMsg); ok {
+		return x.BondMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetUnbondMsg() *staking.UnbondMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_UnbondMsg); ok {
+		return x.UnbondMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetRegisterCurrencyMsg() *currency.RegisterCurrencyMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_RegisterCurrencyMsg); ok {
+		return x.RegisterCurrencyMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetMintMsg() *currency.MintMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_MintMsg); ok {
+		return x.MintMsg
+	}
+	return nil
+}
+
+func (m *BatchMsg_Union) GetBurnMsg() *currency.BurnMsg {
+	if x, ok := m.GetSum().(*BatchMsg_Union_BurnMsg); ok {
+		return x.BurnMsg
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*BatchMsg_Union) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _BatchMsg_Union_OneofMarshaler, _BatchMsg_Union_OneofUnmarshaler, _BatchMsg_Union_OneofSizer, []interface{}{
+		(*BatchMsg_Union_SendMsg)(nil),
+		(*BatchMsg_Union_CreateContractMsg)(nil),
+		(*BatchMsg_Union_CreateEscrowMsg)(nil),
+		(*BatchMsg_Union_ReleaseEscrowMsg)(nil),
+		(*BatchMsg_Union_ReturnEscrowMsg)(nil),
+		(*BatchMsg_Union_UpdateEscrowMsg)(nil),
+		(*BatchMsg_Union_CreateSwapMsg)(nil),
+		(*BatchMsg_Union_ReleaseSwapMsg)(nil),
+		(*BatchMsg_Union_ReturnSwapMsg)(nil),
+		(*BatchMsg_Union_BondMsg)(nil),
+		(*BatchMsg_Union_UnbondMsg)(nil),
+		(*BatchMsg_Union_RegisterCurrencyMsg)(nil),
+		(*BatchMsg_Union_MintMsg)(nil),
+		(*BatchMsg_Union_BurnMsg)(nil),
+	}
+}
+
+func _BatchMsg_Union_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*BatchMsg_Union)
+	// sum
+	switch x := m.Sum.(type) {
+	case *BatchMsg_Union_SendMsg:
+		_ = b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SendMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_CreateContractMsg:
+		_ = b.EncodeVarint(3<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateContractMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_CreateEscrowMsg:
+		_ = b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateEscrowMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_ReleaseEscrowMsg:
+		_ = b.EncodeVarint(5<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReleaseEscrowMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_ReturnEscrowMsg:
+		_ = b.EncodeVarint(6<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReturnEscrowMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_UpdateEscrowMsg:
+		_ = b.EncodeVarint(7<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.UpdateEscrowMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_CreateSwapMsg:
+		_ = b.EncodeVarint(8<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateSwapMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_ReleaseSwapMsg:
+		_ = b.EncodeVarint(9<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReleaseSwapMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_ReturnSwapMsg:
+		_ = b.EncodeVarint(10<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReturnSwapMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_BondMsg:
+		_ = b.EncodeVarint(11<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BondMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_UnbondMsg:
+		_ = b.EncodeVarint(12<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.UnbondMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_RegisterCurrencyMsg:
+		_ = b.EncodeVarint(13<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.RegisterCurrencyMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_MintMsg:
+		_ = b.EncodeVarint(14<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.MintMsg); err != nil {
+			return err
+		}
+	case *BatchMsg_Union_BurnMsg:
+		_ = b.EncodeVarint(15<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BurnMsg); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("BatchMsg_Union.Sum has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _BatchMsg_Union_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*BatchMsg_Union)
+	switch tag {
+	case 1: // sum.send_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(cash.SendMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_SendMsg{msg}
+		return true, err
+	case 3: // sum.create_contract_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(multisig.CreateContractMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_CreateContractMsg{msg}
+		return true, err
+	case 4: // sum.create_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.CreateEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_CreateEscrowMsg{msg}
+		return true, err
+	case 5: // sum.release_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.ReleaseEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_ReleaseEscrowMsg{msg}
+		return true, err
+	case 6: // sum.return_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.ReturnEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_ReturnEscrowMsg{msg}
+		return true, err
+	case 7: // sum.update_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.UpdateEscrowPartiesMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_UpdateEscrowMsg{msg}
+		return true, err
+	case 8: // sum.create_swap_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(htlc.CreateSwapMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_CreateSwapMsg{msg}
+		return true, err
+	case 9: // sum.release_swap_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(htlc.ReleaseSwapMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_ReleaseSwapMsg{msg}
+		return true, err
+	case 10: // sum.return_swap_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(htlc.ReturnSwapMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_ReturnSwapMsg{msg}
+		return true, err
+	case 11: // sum.bond_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(staking.BondMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_BondMsg{msg}
+		return true, err
+	case 12: // sum.unbond_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(staking.UnbondMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_UnbondMsg{msg}
+		return true, err
+	case 13: // sum.register_currency_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(currency.RegisterCurrencyMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_RegisterCurrencyMsg{msg}
+		return true, err
+	case 14: // sum.mint_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(currency.MintMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_MintMsg{msg}
+		return true, err
+	case 15: // sum.burn_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(currency.BurnMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &BatchMsg_Union_BurnMsg{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _BatchMsg_Union_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*BatchMsg_Union)
+	// sum
+	switch x := m.Sum.(type) {
+	case *BatchMsg_Union_SendMsg:
+		s := proto.Size(x.SendMsg)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_CreateContractMsg:
+		s := proto.Size(x.CreateContractMsg)
+		n += proto.SizeVarint(3<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_CreateEscrowMsg:
+		s := proto.Size(x.CreateEscrowMsg)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_ReleaseEscrowMsg:
+		s := proto.Size(x.ReleaseEscrowMsg)
+		n += proto.SizeVarint(5<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_ReturnEscrowMsg:
+		s := proto.Size(x.ReturnEscrowMsg)
+		n += proto.SizeVarint(6<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_UpdateEscrowMsg:
+		s := proto.Size(x.UpdateEscrowMsg)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_CreateSwapMsg:
+		s := proto.Size(x.CreateSwapMsg)
+		n += proto.SizeVarint(8<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_ReleaseSwapMsg:
+		s := proto.Size(x.ReleaseSwapMsg)
+		n += proto.SizeVarint(9<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_ReturnSwapMsg:
+		s := proto.Size(x.ReturnSwapMsg)
+		n += proto.SizeVarint(10<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_BondMsg:
+		s := proto.Size(x.BondMsg)
+		n += proto.SizeVarint(11<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_UnbondMsg:
+		s := proto.Size(x.UnbondMsg)
+		n += proto.SizeVarint(12<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_RegisterCurrencyMsg:
+		s := proto.Size(x.RegisterCurrencyMsg)
+		n += proto.SizeVarint(13<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_MintMsg:
+		s := proto.Size(x.MintMsg)
+		n += proto.SizeVarint(14<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *BatchMsg_Union_BurnMsg:
+		s := proto.Size(x.BurnMsg)
+		n += proto.SizeVarint(15<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+func init() {
+	proto.RegisterType((*Tx)(nil), "app.Tx")
+	proto.RegisterType((*BatchMsg)(nil), "app.BatchMsg")
+	proto.RegisterType((*BatchMsg_Union)(nil), "app.BatchMsg.Union")
+}
+func (m *Tx) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Tx) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Sum != nil {
+		nn1, err := m.Sum.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += nn1
+	}
+	if m.Fees != nil {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Fees.Size()))
+		n2, err := m.Fees.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if len(m.Signatures) > 0 {
+		for _, msg := range m.Signatures {
+			dAtA[i] = 0xaa
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Multisig) > 0 {
+		for _, b := range m.Multisig {
+			dAtA[i] = 0xb2
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
+}
+
+func (m *Tx_SendMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SendMsg != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SendMsg.Size()))
+		n3, err := m.SendMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+func (m *Tx_BatchMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.BatchMsg != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.BatchMsg.Size()))
+		n4, err := m.BatchMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	return i, nil
+}
+func (m *Tx_CreateContractMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateContractMsg != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateContractMsg.Size()))
+		n5, err := m.CreateContractMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	return i, nil
+}
+func (m *Tx_CreateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateEscrowMsg != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateEscrowMsg.Size()))
+		n6, err := m.CreateEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	return i, nil
+}
+func (m *Tx_ReleaseEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReleaseEscrowMsg != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReleaseEscrowMsg.Size()))
+		n7, err := m.ReleaseEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	return i, nil
+}
+func (m *Tx_ReturnEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReturnEscrowMsg != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReturnEscrowMsg.Size()))
+		n8, err := m.ReturnEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	return i, nil
+}
+func (m *Tx_UpdateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.UpdateEscrowMsg != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.UpdateEscrowMsg.Size()))
+		n9, err := m.UpdateEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	return i, nil
+}
+func (m *Tx_CreateSwapMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateSwapMsg != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateSwapMsg.Size()))
+		n10, err := m.CreateSwapMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	return i, nil
+}
+func (m *Tx_ReleaseSwapMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReleaseSwapMsg != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReleaseSwapMsg.Size()))
+		n11, err := m.ReleaseSwapMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	return i, nil
+}
+func (m *Tx_ReturnSwapMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReturnSwapMsg != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReturnSwapMsg.Size()))
+		n12, err := m.ReturnSwapMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
+	}
+	return i, nil
+}
+func (m *Tx_BondMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.BondMsg != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.BondMsg.Size()))
+		n13, err := m.BondMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	return i, nil
+}
+func (m *Tx_UnbondMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.UnbondMsg != nil {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.UnbondMsg.Size()))
+		n14, err := m.UnbondMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	return i, nil
+}
+func (m *Tx_RegisterCurrencyMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.RegisterCurrencyMsg != nil {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.RegisterCurrencyMsg.Size()))
+		n15, err := m.RegisterCurrencyMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
+	return i, nil
+}
+func (m *Tx_MintMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.MintMsg != nil {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MintMsg.Size()))
+		n16, err := m.MintMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	return i, nil
+}
+func (m *Tx_BurnMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.BurnMsg != nil {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.BurnMsg.Size()))
+		n17, err := m.BurnMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n17
+	}
+	return i, nil
+}
+func (m *BatchMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BatchMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Messages) > 0 {
+		for _, msg := range m.Messages {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *BatchMsg_Union) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BatchMsg_Union) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Sum != nil {
+		nn18, err := m.Sum.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += nn18
+	}
+	return i, nil
+}
+
+func (m *BatchMsg_Union_SendMsg) MarshalTo(dAtA []byte) (int, error) {
 	i := 0
 	if m.SendMsg != nil {
 		dAtA[i] = 0xa
 		i++
 		i = encodeVarintCodec(dAtA, i, uint64(m.SendMsg.Size()))
-		n3, err := m.SendMsg.MarshalTo(dAtA[i:])
+		n19, err := m.SendMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n19
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_CreateContractMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateContractMsg != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateContractMsg.Size()))
+		n20, err := m.CreateContractMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n20
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_CreateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateEscrowMsg != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateEscrowMsg.Size()))
+		n21, err := m.CreateEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n21
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_ReleaseEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReleaseEscrowMsg != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReleaseEscrowMsg.Size()))
+		n22, err := m.ReleaseEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n22
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_ReturnEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReturnEscrowMsg != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReturnEscrowMsg.Size()))
+		n23, err := m.ReturnEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n23
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_UpdateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.UpdateEscrowMsg != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.UpdateEscrowMsg.Size()))
+		n24, err := m.UpdateEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n24
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_CreateSwapMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateSwapMsg != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateSwapMsg.Size()))
+		n25, err := m.CreateSwapMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n25
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_ReleaseSwapMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReleaseSwapMsg != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReleaseSwapMsg.Size()))
+		n26, err := m.ReleaseSwapMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n26
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_ReturnSwapMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReturnSwapMsg != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReturnSwapMsg.Size()))
+		n27, err := m.ReturnSwapMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n27
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_BondMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.BondMsg != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.BondMsg.Size()))
+		n28, err := m.BondMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n28
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_UnbondMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.UnbondMsg != nil {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.UnbondMsg.Size()))
+		n29, err := m.UnbondMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n29
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_RegisterCurrencyMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.RegisterCurrencyMsg != nil {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.RegisterCurrencyMsg.Size()))
+		n30, err := m.RegisterCurrencyMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n30
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_MintMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.MintMsg != nil {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MintMsg.Size()))
+		n31, err := m.MintMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n31
+	}
+	return i, nil
+}
+func (m *BatchMsg_Union_BurnMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.BurnMsg != nil {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.BurnMsg.Size()))
+		n32, err := m.BurnMsg.MarshalTo(dAtA[i:])
 		if err != nil {
 			return 0, err
 		}
-		i += n3
+		i += n32
+	}
+	return i, nil
+}
+func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Tx) Size() (n int) {
+	var l int
+	_ = l
+	if m.Sum != nil {
+		n += m.Sum.Size()
+	}
+	if m.Fees != nil {
+		l = m.Fees.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	if len(m.Signatures) > 0 {
+		for _, e := range m.Signatures {
+			l = e.Size()
+			n += 2 + l + sovCodec(uint64(l))
+		}
+	}
+	if len(m.Multisig) > 0 {
+		for _, b := range m.Multisig {
+			l = len(b)
+			n += 2 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Tx_SendMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SendMsg != nil {
+		l = m.SendMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_BatchMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.BatchMsg != nil {
+		l = m.BatchMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateContractMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateContractMsg != nil {
+		l = m.CreateContractMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateEscrowMsg != nil {
+		l = m.CreateEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ReleaseEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReleaseEscrowMsg != nil {
+		l = m.ReleaseEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ReturnEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReturnEscrowMsg != nil {
+		l = m.ReturnEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_UpdateEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.UpdateEscrowMsg != nil {
+		l = m.UpdateEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateSwapMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateSwapMsg != nil {
+		l = m.CreateSwapMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ReleaseSwapMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReleaseSwapMsg != nil {
+		l = m.ReleaseSwapMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ReturnSwapMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReturnSwapMsg != nil {
+		l = m.ReturnSwapMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_BondMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.BondMsg != nil {
+		l = m.BondMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_UnbondMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.UnbondMsg != nil {
+		l = m.UnbondMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_RegisterCurrencyMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.RegisterCurrencyMsg != nil {
+		l = m.RegisterCurrencyMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_MintMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.MintMsg != nil {
+		l = m.MintMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_BurnMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.BurnMsg != nil {
+		l = m.BurnMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Messages) > 0 {
+		for _, e := range m.Messages {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *BatchMsg_Union) Size() (n int) {
+	var l int
+	_ = l
+	if m.Sum != nil {
+		n += m.Sum.Size()
+	}
+	return n
+}
+
+func (m *BatchMsg_Union_SendMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SendMsg != nil {
+		l = m.SendMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_CreateContractMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateContractMsg != nil {
+		l = m.CreateContractMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_CreateEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateEscrowMsg != nil {
+		l = m.CreateEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_ReleaseEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReleaseEscrowMsg != nil {
+		l = m.ReleaseEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_ReturnEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReturnEscrowMsg != nil {
+		l = m.ReturnEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_UpdateEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.UpdateEscrowMsg != nil {
+		l = m.UpdateEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_CreateSwapMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateSwapMsg != nil {
+		l = m.CreateSwapMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_ReleaseSwapMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReleaseSwapMsg != nil {
+		l = m.ReleaseSwapMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_ReturnSwapMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReturnSwapMsg != nil {
+		l = m.ReturnSwapMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_BondMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.BondMsg != nil {
+		l = m.BondMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_UnbondMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.UnbondMsg != nil {
+		l = m.UnbondMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_RegisterCurrencyMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.RegisterCurrencyMsg != nil {
+		l = m.RegisterCurrencyMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_MintMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.MintMsg != nil {
+		l = m.MintMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *BatchMsg_Union_BurnMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.BurnMsg != nil {
+		l = m.BurnMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func sovCodec(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozCodec(x uint64) (n int) {
+	return sovCodec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Tx) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Tx: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Tx: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SendMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &cash.SendMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SendMsg{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BatchMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &BatchMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_BatchMsg{v}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateContractMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &multisig.CreateContractMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateContractMsg{v}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.CreateEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateEscrowMsg{v}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReleaseEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.ReleaseEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ReleaseEscrowMsg{v}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReturnEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.ReturnEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ReturnEscrowMsg{v}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdateEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.UpdateEscrowPartiesMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_UpdateEscrowMsg{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateSwapMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &htlc.CreateSwapMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateSwapMsg{v}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReleaseSwapMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &htlc.ReleaseSwapMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ReleaseSwapMsg{v}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReturnSwapMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &htlc.ReturnSwapMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ReturnSwapMsg{v}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BondMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &staking.BondMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_BondMsg{v}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UnbondMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &staking.UnbondMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_UnbondMsg{v}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegisterCurrencyMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &currency.RegisterCurrencyMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_RegisterCurrencyMsg{v}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MintMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &currency.MintMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_MintMsg{v}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BurnMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &currency.BurnMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_BurnMsg{v}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fees", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fees == nil {
+				m.Fees = &cash.FeeInfo{}
+			}
+			if err := m.Fees.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signatures = append(m.Signatures, &sigs.StdSignature{})
+			if err := m.Signatures[len(m.Signatures)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Multisig", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Multisig = append(m.Multisig, make([]byte, postIndex-iNdEx))
+			copy(m.Multisig[len(m.Multisig)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	return i, nil
-}
-func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return nil
 }
-func (m *Tx) Size() (n int) {
-	var l int
-	_ = l
-	if m.Sum != nil {
-		n += m.Sum.Size()
-	}
-	if m.Fees != nil {
-		l = m.Fees.Size()
-		n += 2 + l + sovCodec(uint64(l))
-	}
-	if len(m.Signatures) > 0 {
-		for _, e := range m.Signatures {
-			l = e.Size()
-			n += 2 + l + sovCodec(uint64(l))
+func (m *BatchMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BatchMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BatchMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Messages", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Messages = append(m.Messages, &BatchMsg_Union{})
+			if err := m.Messages[len(m.Messages)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	return n
-}
-
-func (m *Tx_SendMsg) Size() (n int) {
-	var l int
-	_ = l
-	if m.SendMsg != nil {
-		l = m.SendMsg.Size()
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
 
-func sovCodec(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozCodec(x uint64) (n int) {
-	return sovCodec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *Tx) Unmarshal(dAtA []byte) error {
+func (m *BatchMsg_Union) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -273,28 +2678,156 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 			if shift >= 64 {
 				return ErrIntOverflowCodec
 			}
-			if iNdEx >= l {
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Union: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Union: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SendMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &cash.SendMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &BatchMsg_Union_SendMsg{v}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateContractMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &multisig.CreateContractMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &BatchMsg_Union_CreateContractMsg{v}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.CreateEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &BatchMsg_Union_CreateEscrowMsg{v}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReleaseEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= (uint64(b) & 0x7F) << shift
-			if b < 0x80 {
-				break
+			v := &escrow.ReleaseEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Tx: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Tx: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.Sum = &BatchMsg_Union_ReleaseEscrowMsg{v}
+			iNdEx = postIndex
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SendMsg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ReturnEscrowMsg", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -318,15 +2851,15 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &cash.SendMsg{}
+			v := &escrow.ReturnEscrowMsg{}
 			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Sum = &Tx_SendMsg{v}
+			m.Sum = &BatchMsg_Union_ReturnEscrowMsg{v}
 			iNdEx = postIndex
-		case 20:
+		case 7:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Fees", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdateEscrowMsg", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -350,16 +2883,47 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Fees == nil {
-				m.Fees = &cash.FeeInfo{}
+			v := &escrow.UpdateEscrowPartiesMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
-			if err := m.Fees.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Sum = &BatchMsg_Union_UpdateEscrowMsg{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateSwapMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &htlc.CreateSwapMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
+			m.Sum = &BatchMsg_Union_CreateSwapMsg{v}
 			iNdEx = postIndex
-		case 21:
+		case 9:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ReleaseSwapMsg", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -383,10 +2947,203 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Signatures = append(m.Signatures, &sigs.StdSignature{})
-			if err := m.Signatures[len(m.Signatures)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			v := &htlc.ReleaseSwapMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &BatchMsg_Union_ReleaseSwapMsg{v}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReturnSwapMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &htlc.ReturnSwapMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &BatchMsg_Union_ReturnSwapMsg{v}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BondMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &staking.BondMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &BatchMsg_Union_BondMsg{v}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UnbondMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &staking.UnbondMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &BatchMsg_Union_UnbondMsg{v}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RegisterCurrencyMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &currency.RegisterCurrencyMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &BatchMsg_Union_RegisterCurrencyMsg{v}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MintMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &currency.MintMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &BatchMsg_Union_MintMsg{v}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BurnMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &currency.BurnMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
+			m.Sum = &BatchMsg_Union_BurnMsg{v}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -514,22 +3271,51 @@ var (
 	ErrIntOverflowCodec   = fmt.Errorf("proto: integer overflow")
 )
 
-func init() { proto.RegisterFile("app/codec.proto", fileDescriptorCodec) }
+func init() { proto.RegisterFile("examples/mycoind/app/codec.proto", fileDescriptorCodec) }
 
 var fileDescriptorCodec = []byte{
-	// 222 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4f, 0x2c, 0x28, 0xd0,
-	0x4f, 0xce, 0x4f, 0x49, 0x4d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x4e, 0x2c, 0x28,
-	0x90, 0xd2, 0x4a, 0xcf, 0x2c, 0xc9, 0x28, 0x4d, 0xd2, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0xcf,
-	0x4b, 0xcb, 0xcc, 0xd7, 0x2f, 0x4f, 0x4d, 0x2c, 0x4b, 0xd5, 0xaf, 0xd0, 0x4f, 0x4e, 0x2c, 0xce,
-	0x40, 0xd6, 0x80, 0x4f, 0x6d, 0x71, 0x66, 0x7a, 0x31, 0xb2, 0x5a, 0xa5, 0x3e, 0x46, 0x2e, 0xa6,
-	0x90, 0x0a, 0x21, 0x2d, 0x2e, 0x8e, 0xe2, 0xd4, 0xbc, 0x94, 0xf8, 0xdc, 0xe2, 0x74, 0x09, 0x46,
-	0x05, 0x46, 0x0d, 0x6e, 0x23, 0x5e, 0x3d, 0x90, 0xb9, 0x7a, 0xc1, 0xa9, 0x79, 0x29, 0xbe, 0xc5,
-	0xe9, 0x1e, 0x0c, 0x41, 0xec, 0xc5, 0x10, 0xa6, 0x90, 0x22, 0x17, 0x4b, 0x5a, 0x6a, 0x6a, 0xb1,
-	0x84, 0x08, 0xb2, 0x3a, 0xb7, 0xd4, 0x54, 0xcf, 0xbc, 0xb4, 0xfc, 0x20, 0xb0, 0x94, 0x90, 0x11,
-	0x17, 0x57, 0x71, 0x66, 0x7a, 0x5e, 0x62, 0x49, 0x69, 0x51, 0x6a, 0xb1, 0x84, 0xa8, 0x02, 0xb3,
-	0x06, 0xb7, 0x91, 0x90, 0x1e, 0xc8, 0x72, 0xbd, 0xe0, 0x92, 0x94, 0x60, 0x98, 0x54, 0x10, 0x92,
-	0x2a, 0x27, 0x56, 0x2e, 0xe6, 0xe2, 0xd2, 0x5c, 0x27, 0x81, 0x13, 0x8f, 0xe4, 0x18, 0x2f, 0x3c,
-	0x92, 0x63, 0x7c, 0xf0, 0x48, 0x8e, 0x71, 0xc2, 0x63, 0x39, 0x86, 0x24, 0x36, 0xb0, 0x4b, 0x8d,
-	0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0xef, 0xfa, 0xa2, 0xdc, 0x19, 0x01, 0x00, 0x00,
+	// 673 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x96, 0xdf, 0x4e, 0xdb, 0x3c,
+	0x18, 0xc6, 0x29, 0x05, 0x1a, 0xcc, 0x7f, 0x17, 0xbe, 0x2f, 0xea, 0xb4, 0xaa, 0xe3, 0x08, 0x21,
+	0x70, 0x24, 0x38, 0x9e, 0x34, 0x15, 0x31, 0x31, 0x69, 0x48, 0x53, 0x3a, 0x8e, 0x2b, 0xc7, 0x31,
+	0xa9, 0xb5, 0xc6, 0x8e, 0x6c, 0x67, 0x85, 0x3b, 0xd8, 0xe1, 0xae, 0x62, 0x97, 0x32, 0xed, 0x70,
+	0x97, 0x30, 0xb1, 0x1b, 0x99, 0x62, 0x3b, 0x21, 0xe9, 0x34, 0xc4, 0xa4, 0x1d, 0x72, 0xe6, 0x3c,
+	0x7e, 0x9e, 0x1f, 0xaf, 0x5f, 0xec, 0x57, 0x05, 0x03, 0x7a, 0x83, 0xd3, 0x6c, 0x4a, 0x55, 0x90,
+	0xde, 0x12, 0xc1, 0x78, 0x1c, 0xe0, 0x2c, 0x0b, 0x88, 0x88, 0x29, 0x41, 0x99, 0x14, 0x5a, 0xc0,
+	0x36, 0xce, 0xb2, 0xde, 0x61, 0xc2, 0xf4, 0x24, 0x8f, 0x10, 0x11, 0x69, 0x40, 0x04, 0xbf, 0x66,
+	0x22, 0x98, 0x51, 0xfc, 0x91, 0x06, 0x37, 0x01, 0xc1, 0x6a, 0x52, 0x0f, 0xf4, 0xd0, 0x03, 0xde,
+	0x5c, 0x4a, 0xca, 0xc9, 0x6d, 0xc3, 0x7f, 0xf4, 0x67, 0x3f, 0x55, 0x44, 0x8a, 0x59, 0xc3, 0xfd,
+	0x40, 0x25, 0x13, 0x3d, 0x25, 0x8f, 0xad, 0x24, 0xcd, 0xa7, 0x9a, 0x29, 0x96, 0x3c, 0x96, 0xad,
+	0x58, 0xa2, 0x1a, 0xde, 0xe3, 0x07, 0xbc, 0x1a, 0x7f, 0x60, 0xbc, 0x81, 0xde, 0xff, 0xe2, 0x81,
+	0xc5, 0xf7, 0x37, 0xf0, 0x10, 0x78, 0x8a, 0xf2, 0x78, 0x9c, 0xaa, 0xc4, 0x6f, 0x0d, 0x5a, 0x07,
+	0x6b, 0x27, 0x1b, 0xa8, 0x68, 0x20, 0x1a, 0x51, 0x1e, 0x5f, 0xaa, 0xe4, 0x62, 0x21, 0xec, 0x28,
+	0xbb, 0x84, 0x47, 0x60, 0x35, 0xc2, 0x9a, 0x4c, 0x8c, 0x79, 0xd1, 0x99, 0x71, 0x96, 0xa1, 0x61,
+	0xa1, 0x5a, 0xb3, 0x17, 0xb9, 0x35, 0xbc, 0x04, 0x5d, 0x22, 0x29, 0xd6, 0x74, 0x4c, 0x04, 0xd7,
+	0x12, 0x13, 0x6d, 0x72, 0x6d, 0x93, 0x7b, 0x86, 0xca, 0xf3, 0xa2, 0x33, 0x63, 0x3a, 0x73, 0x1e,
+	0x4b, 0xd9, 0x21, 0xf3, 0x22, 0x3c, 0x07, 0x4e, 0x1c, 0xdb, 0xff, 0x81, 0x81, 0x2d, 0x19, 0xd8,
+	0xff, 0xc8, 0x4a, 0x0e, 0x75, 0x6e, 0x3e, 0x2c, 0x68, 0x8b, 0x34, 0x25, 0x78, 0x01, 0xa0, 0xa4,
+	0x53, 0x8a, 0x55, 0x83, 0xb3, 0x6c, 0x38, 0x7e, 0xc9, 0x09, 0xad, 0xa3, 0x0e, 0xda, 0x96, 0x73,
+	0x5a, 0x51, 0x90, 0xa4, 0x3a, 0x97, 0xbc, 0x0e, 0x5a, 0x69, 0x16, 0x14, 0x1a, 0x43, 0xa3, 0x20,
+	0xd9, 0x94, 0xe0, 0x5b, 0xb0, 0x93, 0x67, 0xf1, 0xdc, 0xb9, 0x3a, 0x06, 0xd3, 0x2f, 0x31, 0x57,
+	0xc6, 0x60, 0x33, 0xef, 0xb0, 0xd4, 0x8c, 0x2a, 0x47, 0xcb, 0x6b, 0x3b, 0x05, 0xed, 0x25, 0x70,
+	0x27, 0x1e, 0xab, 0x19, 0xce, 0x0c, 0xcb, 0x33, 0xac, 0x2e, 0x2a, 0x2e, 0xa3, 0xeb, 0xd0, 0x68,
+	0x86, 0x33, 0x0b, 0xd8, 0x20, 0x75, 0x01, 0xbe, 0x02, 0xe5, 0x39, 0xef, 0xf3, 0xab, 0x26, 0xbf,
+	0x6b, 0xf3, 0xae, 0x33, 0xf7, 0x80, 0x4d, 0xd9, 0x50, 0x8a, 0x02, 0x5c, 0x57, 0x2a, 0x00, 0xa8,
+	0x17, 0x60, 0x3b, 0x52, 0x2b, 0x40, 0xd6, 0x05, 0x78, 0x0c, 0xbc, 0x48, 0xb8, 0xeb, 0xb8, 0x66,
+	0x72, 0xdb, 0xc8, 0xdd, 0x5e, 0x34, 0x14, 0xd5, 0x8d, 0x8c, 0xec, 0x12, 0x9e, 0x02, 0x90, 0xf3,
+	0x2a, 0xb0, 0x6e, 0x02, 0xb0, 0x0a, 0x5c, 0xf1, 0xa8, 0x8a, 0xac, 0xe6, 0xe5, 0x07, 0x1c, 0x81,
+	0x3d, 0x49, 0x13, 0xa6, 0x34, 0x95, 0xe3, 0xf2, 0xfd, 0x9b, 0xfc, 0x86, 0xc9, 0x3f, 0x47, 0xa5,
+	0x88, 0x42, 0x67, 0x3b, 0x73, 0x82, 0x45, 0x75, 0xe5, 0xef, 0x32, 0x44, 0xc0, 0x4b, 0x19, 0xb7,
+	0x57, 0x7c, 0xd3, 0x70, 0x76, 0xee, 0x39, 0x97, 0x8c, 0xbb, 0x8b, 0xdd, 0x49, 0xed, 0xb2, 0xf0,
+	0x47, 0x45, 0x97, 0x0a, 0xff, 0xd6, 0xbc, 0x7f, 0x98, 0x4b, 0x5e, 0x9e, 0xd4, 0x2e, 0xe1, 0x0b,
+	0xb0, 0x74, 0x4d, 0xa9, 0xf2, 0x77, 0xeb, 0x6f, 0xf4, 0x35, 0xa5, 0x6f, 0xf8, 0xb5, 0x08, 0xcd,
+	0x16, 0x3c, 0x01, 0x40, 0xb1, 0x84, 0x63, 0x9d, 0x4b, 0xaa, 0xfc, 0xbd, 0x41, 0xdb, 0x36, 0x83,
+	0x25, 0x0a, 0x8d, 0x74, 0x3c, 0x2a, 0xb7, 0xc2, 0x9a, 0x0b, 0xf6, 0x80, 0x57, 0x3e, 0x44, 0xff,
+	0xbf, 0x41, 0xfb, 0x60, 0x3d, 0xac, 0xbe, 0x87, 0xcb, 0xa0, 0xad, 0xf2, 0x74, 0xff, 0x6b, 0x07,
+	0x78, 0xe5, 0x03, 0x87, 0x01, 0xf0, 0x52, 0xaa, 0x14, 0x4e, 0xa8, 0xf2, 0x5b, 0xe6, 0x2f, 0x74,
+	0x1b, 0x13, 0x00, 0x5d, 0x71, 0x26, 0x78, 0x58, 0x99, 0x7a, 0x9f, 0x3a, 0x60, 0xd9, 0x68, 0x7f,
+	0x35, 0x69, 0x9e, 0x66, 0xc7, 0xd3, 0xec, 0x78, 0x9a, 0x1d, 0xff, 0x68, 0x76, 0xb8, 0x87, 0x3c,
+	0xdc, 0xfe, 0x76, 0xd7, 0x6f, 0x7d, 0xbf, 0xeb, 0xb7, 0x7e, 0xdc, 0xf5, 0x5b, 0x9f, 0x7f, 0xf6,
+	0x17, 0xa2, 0x15, 0xf3, 0x53, 0xe0, 0xf4, 0xd7, 0x00, 0x2d, 0x81, 0x6b, 0x2f, 0x74, 0x09, 0x00,
+	0x00,
 }
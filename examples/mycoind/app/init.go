@@ -3,6 +3,8 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	abci "github.com/tendermint/abci/types"
@@ -10,12 +12,13 @@ import (
 
 	"github.com/confio/weave"
 	"github.com/confio/weave/crypto"
+	"github.com/confio/weave/store/iavl"
 	"github.com/confio/weave/x"
-	"github.com/confio/weave/x/cash"
 )
 
 // GenInitOptions will produce some basic options for one rich
-// account, to use for dev mode
+// account, to use for dev mode. The account is also the issuer
+// of the currency, so it can mint more
 //
 // You can set
 func GenInitOptions(args []string) (json.RawMessage, error) {
@@ -49,13 +52,88 @@ func GenInitOptions(args []string) (json.RawMessage, error) {
           }
         ]
       }
+    ],
+    "currencies": [
+      {
+        "ticker": "%s",
+        "name": "%s",
+        "decimals": 9,
+        "issuer": "%s"
+      }
     ]
-  }`, addr, code)
+  }`, addr, code, code, code, addr)
 	return []byte(opts), nil
 }
 
+// ConfigFile holds the settings of this node, that are not
+// part of the chain, in the config dir under home
+const ConfigFile = "app.json"
+
+// Backends to store the state, see Config
+const (
+	// BackendIAVL is a merkle tree with history and proofs
+	BackendIAVL = "iavl"
+	// BackendLevelDB is a plain leveldb without either,
+	// which is faster
+	BackendLevelDB = "leveldb"
+)
+
+// Config is read from ConfigFile, fields that are not set
+// keep their default values
+type Config struct {
+	// Backend is one of BackendIAVL or BackendLevelDB,
+	// it cannot change once the node has stored any state
+	Backend string `json:"backend"`
+	// Pruning decides how much history of the state we keep,
+	// only used by BackendIAVL
+	Pruning iavl.Pruning `json:"pruning"`
+}
+
+// DefaultConfig is used if there is no ConfigFile
+func DefaultConfig() Config {
+	return Config{
+		Backend: BackendIAVL,
+		Pruning: iavl.DefaultPruning,
+	}
+}
+
+// Validate makes sure we know the backend, and
+// the values are in range
+func (c Config) Validate() error {
+	if c.Backend != BackendIAVL && c.Backend != BackendLevelDB {
+		return fmt.Errorf("Unknown backend: %s", c.Backend)
+	}
+	return c.Pruning.Validate()
+}
+
+// LoadConfig reads the ConfigFile from home, or returns
+// the DefaultConfig if there is none
+func LoadConfig(home string) (Config, error) {
+	conf := DefaultConfig()
+	if home == "" {
+		return conf, nil
+	}
+	bz, err := ioutil.ReadFile(filepath.Join(home, "config", ConfigFile))
+	if os.IsNotExist(err) {
+		return conf, nil
+	}
+	if err != nil {
+		return conf, err
+	}
+	err = json.Unmarshal(bz, &conf)
+	if err != nil {
+		return conf, err
+	}
+	return conf, conf.Validate()
+}
+
 // GenerateApp is used to create a stub for server/start.go command
 func GenerateApp(home string, logger log.Logger) (abci.Application, error) {
+	conf, err := LoadConfig(home)
+	if err != nil {
+		return nil, err
+	}
+
 	// db goes in a subdir, but "" -> "" for memdb
 	var dbPath string
 	if home != "" {
@@ -63,11 +141,11 @@ func GenerateApp(home string, logger log.Logger) (abci.Application, error) {
 	}
 
 	stack := Stack(x.Coin{})
-	app, err := Application("mycoin", stack, TxDecoder, dbPath)
+	app, err := Application("mycoin", stack, TxDecoder, dbPath, conf)
 	if err != nil {
 		return nil, err
 	}
-	app.WithInit(cash.Initializer{})
+	app.WithInit(Initializers())
 
 	// set the logger and return
 	app.WithLogger(logger)
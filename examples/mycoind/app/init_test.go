@@ -2,10 +2,15 @@ package app
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/store/iavl"
 )
 
 func TestGenInitOptions(t *testing.T) {
@@ -37,3 +42,50 @@ func TestGenInitOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfig(t *testing.T) {
+	home, err := ioutil.TempDir("", "mycoind")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	// defaults without a file
+	conf, err := LoadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), conf)
+
+	confDir := filepath.Join(home, "config")
+	require.NoError(t, os.MkdirAll(confDir, 0755))
+	file := filepath.Join(confDir, ConfigFile)
+
+	// archive node
+	err = ioutil.WriteFile(file, []byte(`{"pruning": {"keep_recent": 0}}`), 0600)
+	require.NoError(t, err)
+	conf, err = LoadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, iavl.KeepEverything(), conf.Pruning)
+
+	// checkpoints
+	err = ioutil.WriteFile(file, []byte(`{"pruning": {"keep_recent": 100, "keep_every": 1000}}`), 0600)
+	require.NoError(t, err)
+	conf, err = LoadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, iavl.KeepEveryAndRecent(1000, 100), conf.Pruning)
+
+	// plain leveldb
+	err = ioutil.WriteFile(file, []byte(`{"backend": "leveldb"}`), 0600)
+	require.NoError(t, err)
+	conf, err = LoadConfig(home)
+	require.NoError(t, err)
+	assert.Equal(t, BackendLevelDB, conf.Backend)
+	assert.Equal(t, iavl.DefaultPruning, conf.Pruning)
+
+	// invalid values
+	err = ioutil.WriteFile(file, []byte(`{"pruning": {"keep_recent": -1}}`), 0600)
+	require.NoError(t, err)
+	_, err = LoadConfig(home)
+	assert.Error(t, err)
+	err = ioutil.WriteFile(file, []byte(`{"backend": "redis"}`), 0600)
+	require.NoError(t, err)
+	_, err = LoadConfig(home)
+	assert.Error(t, err)
+}
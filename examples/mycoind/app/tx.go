@@ -3,7 +3,9 @@ package app
 import (
 	"github.com/confio/weave"
 	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x/batch"
 	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/multisig"
 	"github.com/confio/weave/x/sigs"
 )
 
@@ -21,6 +23,7 @@ func TxDecoder(bz []byte) (weave.Tx, error) {
 var _ weave.Tx = (*Tx)(nil)
 var _ cash.FeeTx = (*Tx)(nil)
 var _ sigs.SignedTx = (*Tx)(nil)
+var _ multisig.MultiSigTx = (*Tx)(nil)
 
 // GetMsg switches over all types defined in the protobuf file
 func (tx *Tx) GetMsg() (weave.Msg, error) {
@@ -33,6 +36,34 @@ func (tx *Tx) GetMsg() (weave.Msg, error) {
 	switch t := sum.(type) {
 	case *Tx_SendMsg:
 		return t.SendMsg, nil
+	case *Tx_BatchMsg:
+		return t.BatchMsg, nil
+	case *Tx_CreateContractMsg:
+		return t.CreateContractMsg, nil
+	case *Tx_CreateEscrowMsg:
+		return t.CreateEscrowMsg, nil
+	case *Tx_ReleaseEscrowMsg:
+		return t.ReleaseEscrowMsg, nil
+	case *Tx_ReturnEscrowMsg:
+		return t.ReturnEscrowMsg, nil
+	case *Tx_UpdateEscrowMsg:
+		return t.UpdateEscrowMsg, nil
+	case *Tx_CreateSwapMsg:
+		return t.CreateSwapMsg, nil
+	case *Tx_ReleaseSwapMsg:
+		return t.ReleaseSwapMsg, nil
+	case *Tx_ReturnSwapMsg:
+		return t.ReturnSwapMsg, nil
+	case *Tx_BondMsg:
+		return t.BondMsg, nil
+	case *Tx_UnbondMsg:
+		return t.UnbondMsg, nil
+	case *Tx_RegisterCurrencyMsg:
+		return t.RegisterCurrencyMsg, nil
+	case *Tx_MintMsg:
+		return t.MintMsg, nil
+	case *Tx_BurnMsg:
+		return t.BurnMsg, nil
 	}
 
 	// we must have covered it above
@@ -53,3 +84,50 @@ func (tx *Tx) GetSignBytes() ([]byte, error) {
 	tx.Signatures = sigs
 	return bz, err
 }
+
+var _ batch.Msg = (*BatchMsg)(nil)
+
+// Path is handled by the batch.Decorator, before the Router
+func (*BatchMsg) Path() string {
+	return batch.PathExecuteBatchMsg
+}
+
+// MsgList switches over all types defined in the protobuf file
+func (m *BatchMsg) MsgList() ([]weave.Msg, error) {
+	msgs := make([]weave.Msg, len(m.Messages))
+	for i, msg := range m.Messages {
+		switch t := msg.GetSum().(type) {
+		case *BatchMsg_Union_SendMsg:
+			msgs[i] = t.SendMsg
+		case *BatchMsg_Union_CreateContractMsg:
+			msgs[i] = t.CreateContractMsg
+		case *BatchMsg_Union_CreateEscrowMsg:
+			msgs[i] = t.CreateEscrowMsg
+		case *BatchMsg_Union_ReleaseEscrowMsg:
+			msgs[i] = t.ReleaseEscrowMsg
+		case *BatchMsg_Union_ReturnEscrowMsg:
+			msgs[i] = t.ReturnEscrowMsg
+		case *BatchMsg_Union_UpdateEscrowMsg:
+			msgs[i] = t.UpdateEscrowMsg
+		case *BatchMsg_Union_CreateSwapMsg:
+			msgs[i] = t.CreateSwapMsg
+		case *BatchMsg_Union_ReleaseSwapMsg:
+			msgs[i] = t.ReleaseSwapMsg
+		case *BatchMsg_Union_ReturnSwapMsg:
+			msgs[i] = t.ReturnSwapMsg
+		case *BatchMsg_Union_BondMsg:
+			msgs[i] = t.BondMsg
+		case *BatchMsg_Union_UnbondMsg:
+			msgs[i] = t.UnbondMsg
+		case *BatchMsg_Union_RegisterCurrencyMsg:
+			msgs[i] = t.RegisterCurrencyMsg
+		case *BatchMsg_Union_MintMsg:
+			msgs[i] = t.MintMsg
+		case *BatchMsg_Union_BurnMsg:
+			msgs[i] = t.BurnMsg
+		default:
+			return nil, errors.ErrDecoding()
+		}
+	}
+	return msgs, nil
+}
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/confio/weave/commands/server"
+	"github.com/confio/weave/examples/mycoind/app"
+)
+
+func TestCheck(t *testing.T) {
+	home := setupConfig(t)
+	defer os.RemoveAll(home)
+
+	logger := log.NewNopLogger()
+	args := []string{"ETH", "ABCD123456789000DEADBEEF00ABCD1234567890"}
+	err := server.InitCmd(app.GenInitOptions, logger, home, args)
+	require.NoError(t, err)
+	genesis := readGenesis(t, filepath.Join(home, "config", "genesis.json"))
+
+	// run the genesis block
+	myApp, err := app.GenerateApp(home, logger)
+	require.NoError(t, err)
+	myApp.InitChain(abci.RequestInitChain{
+		ChainId:       "test-chain-tspYJj",
+		AppStateBytes: genesis[server.AppStateKey],
+	})
+	myApp.Commit()
+
+	// we cannot open the db twice, so reuse the app
+	gen := func(string, log.Logger) (abci.Application, error) {
+		return myApp, nil
+	}
+	err = server.CheckCmd(gen, logger, home, []string{"-height", "1"})
+	assert.NoError(t, err)
+
+	// unknown heights fail
+	err = server.CheckCmd(gen, logger, home, []string{"-height", "7"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/commands/server"
+	"github.com/confio/weave/examples/mycoind/app"
+)
+
+func TestExport(t *testing.T) {
+	home := setupConfig(t)
+	defer os.RemoveAll(home)
+
+	logger := log.NewNopLogger()
+	args := []string{"ETH", "ABCD123456789000DEADBEEF00ABCD1234567890"}
+	err := server.InitCmd(app.GenInitOptions, logger, home, args)
+	require.NoError(t, err)
+	genFile := filepath.Join(home, "config", "genesis.json")
+	genesis := readGenesis(t, genFile)
+
+	// run the genesis block
+	myApp, err := app.GenerateApp(home, logger)
+	require.NoError(t, err)
+	var genVals []struct {
+		PubKey struct {
+			Data []byte `json:"_v"`
+		} `json:"pub_key"`
+		Power int64 `json:"power"`
+	}
+	require.NoError(t, json.Unmarshal(genesis["validators"], &genVals))
+	require.Len(t, genVals, 1)
+	val := abci.Validator{
+		PubKey: abci.PubKey{Type: "ed25519", Data: genVals[0].PubKey.Data},
+		Power:  genVals[0].Power,
+	}
+	myApp.InitChain(abci.RequestInitChain{
+		ChainId:       "test-chain-tspYJj",
+		Validators:    []abci.Validator{val},
+		AppStateBytes: genesis[server.AppStateKey],
+	})
+	myApp.Commit()
+
+	// we cannot open the db twice, so reuse the app
+	gen := func(string, log.Logger) (abci.Application, error) {
+		return myApp, nil
+	}
+	out := filepath.Join(home, "export.json")
+
+	// we need a new chain id
+	err = server.ExportCmd(gen, logger, home, []string{"-height", "1", "-out", out})
+	assert.Error(t, err)
+	err = server.ExportCmd(gen, logger, home,
+		[]string{"-height", "1", "-out", out, "-chain-id", "test-chain-tspYJj"})
+	assert.Error(t, err)
+	err = server.ExportCmd(gen, logger, home,
+		[]string{"-height", "1", "-out", out, "-chain-id", "test-chain-2"})
+	require.NoError(t, err)
+
+	// with the current validators, in the format tendermint reads
+	exported := readGenesis(t, out)
+	assert.JSONEq(t, `"test-chain-2"`, string(exported["chain_id"]))
+	assert.JSONEq(t, string(genesis["validators"]), string(exported["validators"]))
+	assert.Equal(t, genesis["genesis_time"], exported["genesis_time"])
+
+	// and the state has the same content, along with the
+	// height it was exported at
+	var orig, dump map[string]interface{}
+	require.NoError(t, json.Unmarshal(genesis[server.AppStateKey], &orig))
+	require.NoError(t, json.Unmarshal(exported[server.AppStateKey], &dump))
+	assert.Equal(t, float64(1), dump[weave.ExportHeightKey])
+	delete(dump, weave.ExportHeightKey)
+	assert.Equal(t, orig, dump)
+
+	// unknown heights fail
+	err = server.ExportCmd(gen, logger, home, []string{"-height", "7", "-chain-id", "test-chain-2"})
+	assert.Error(t, err)
+}
+
+func readGenesis(t *testing.T, filename string) server.GenesisDoc {
+	bz, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	var doc server.GenesisDoc
+	require.NoError(t, json.Unmarshal(bz, &doc))
+	return doc
+}
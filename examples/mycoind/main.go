@@ -33,6 +33,8 @@ func helpMessage() {
 	fmt.Println("help    Print this message")
 	fmt.Println("init    Initialize app options in genesis file")
 	fmt.Println("start   Run the abci server")
+	fmt.Println("export  Dump the app state as a genesis file")
+	fmt.Println("check   Verify the invariants of the app state")
 	fmt.Println("testgen Generate protobuf/json files for test cases")
 	fmt.Println("version Print the app version")
 	fmt.Println(`
@@ -63,6 +65,10 @@ func main() {
 		err = server.InitCmd(app.GenInitOptions, logger, *varHome, rest)
 	case "start":
 		err = server.StartCmd(app.GenerateApp, logger, *varHome, rest)
+	case "export":
+		err = server.ExportCmd(app.GenerateApp, logger, *varHome, rest)
+	case "check":
+		err = server.CheckCmd(app.GenerateApp, logger, *varHome, rest)
 	case "testgen":
 		err = commands.TestGenCmd(app.Examples(), rest)
 	case "version":
@@ -109,7 +109,8 @@ type BlogBucket struct {
 // add run-time check on Save
 func NewBlogBucket() BlogBucket {
 	bucket := orm.NewBucket(BlogBucketName,
-		orm.NewSimpleObj(nil, new(Blog)))
+		orm.NewSimpleObj(nil, new(Blog))).
+		WithMultiKeyIndex("author", idxBlogAuthors, false)
 	return BlogBucket{
 		Bucket: bucket,
 	}
@@ -123,6 +124,18 @@ func (b BlogBucket) Save(db weave.KVStore, obj orm.Object) error {
 	return b.Bucket.Save(db, obj)
 }
 
+// idxBlogAuthors indexes a blog by each of its authors
+func idxBlogAuthors(obj orm.Object) ([][]byte, error) {
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	blog, ok := obj.Value().(*Blog)
+	if !ok {
+		return nil, errors.New("Can only take index of Blog")
+	}
+	return blog.Authors, nil
+}
+
 //------ Post Bucket
 
 const PostBucketName = "posts"
@@ -0,0 +1,60 @@
+package weave
+
+import (
+	"github.com/confio/weave/errors"
+)
+
+// GasMeter keeps track of how much work a transaction performed,
+// and aborts it once it passes its limit.
+//
+// It is carried in the Context, see WithGasMeter
+type GasMeter interface {
+	// ConsumeGas adds the amount to the gas consumed.
+	// It panics with ErrOutOfGas if this passes the limit,
+	// descriptor is used to explain what used up the gas
+	ConsumeGas(amount int64, descriptor string)
+
+	// GasConsumed returns the total gas used so far
+	GasConsumed() int64
+
+	// GasLimit returns the maximum allowed gas, or 0 if unlimited
+	GasLimit() int64
+}
+
+// gasMeter is a simple GasMeter with an optional limit
+type gasMeter struct {
+	limit    int64
+	consumed int64
+}
+
+var _ GasMeter = (*gasMeter)(nil)
+
+// NewGasMeter returns a GasMeter that panics once more
+// than limit gas was consumed.
+func NewGasMeter(limit int64) GasMeter {
+	return &gasMeter{limit: limit}
+}
+
+// NewInfiniteGasMeter returns a GasMeter that only counts,
+// but never runs out of gas
+func NewInfiniteGasMeter() GasMeter {
+	return &gasMeter{}
+}
+
+// ConsumeGas adds the amount and panics if we pass the limit
+func (g *gasMeter) ConsumeGas(amount int64, descriptor string) {
+	g.consumed += amount
+	if g.limit > 0 && g.consumed > g.limit {
+		panic(errors.ErrOutOfGas(descriptor))
+	}
+}
+
+// GasConsumed returns the total gas used so far
+func (g *gasMeter) GasConsumed() int64 {
+	return g.consumed
+}
+
+// GasLimit returns the maximum allowed gas, or 0 if unlimited
+func (g *gasMeter) GasLimit() int64 {
+	return g.limit
+}
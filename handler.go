@@ -2,6 +2,8 @@ package weave
 
 import (
 	"encoding/json"
+
+	abci "github.com/tendermint/abci/types"
 )
 
 // Handler is a core engine that can process a few specific messages
@@ -38,6 +40,28 @@ type Ticker interface {
 	Tick(ctx Context, store KVStore) (TickResult, error)
 }
 
+// EndBlocker is a method that is called at the end of every block,
+// after all transactions, which can be used to settle the block,
+// like distributing rewards or flushing aggregated data
+type EndBlocker interface {
+	EndBlock(ctx Context, store KVStore) (EndBlockResult, error)
+}
+
+// ValidatorUpdater keeps track of the validator set. It is called
+// with the genesis validators and every change after that, so it
+// always knows the current power table
+type ValidatorUpdater interface {
+	UpdateValidators(store KVStore, diff []abci.Validator) error
+	GetValidators(store ReadOnlyKVStore) ([]abci.Validator, error)
+}
+
+// InvariantChecker verifies that the state is consistent, like
+// all coins adding up to the supply, and returns an error naming
+// the offending keys if it is not
+type InvariantChecker interface {
+	CheckInvariants(store ReadOnlyKVStore) error
+}
+
 // Registry is an interface to register your handler,
 // the setup side of a Router
 type Registry interface {
@@ -60,8 +84,39 @@ func (o Options) ReadOptions(key string, obj interface{}) error {
 	return json.Unmarshal(msg, obj)
 }
 
+// WriteOptions serializes obj as json and stores it
+// under the given key, overwriting any previous value.
+func (o Options) WriteOptions(key string, obj interface{}) error {
+	bz, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	o[key] = bz
+	return nil
+}
+
+// ExportHeightKey is where an export of the state records the
+// height it was read at. A chain restarted from it begins again
+// at height 1, so all stored heights must move back by as much.
+const ExportHeightKey = "export_height"
+
+// ExportHeight returns the height the options were exported at,
+// or 0 if they are not an export of a running chain
+func (o Options) ExportHeight() (int64, error) {
+	var height int64
+	err := o.ReadOptions(ExportHeightKey, &height)
+	return height, err
+}
+
 // Initializer implementations are used to initialize
 // extensions from genesis file contents
 type Initializer interface {
 	FromGenesis(Options, KVStore) error
 }
+
+// Exporter is the inverse of Initializer. It dumps the state
+// of an extension into the options, in the same format that
+// FromGenesis reads, so we can restart a chain from it.
+type Exporter interface {
+	ToGenesis(Options, ReadOnlyKVStore) error
+}
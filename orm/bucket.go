@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 
 	"github.com/confio/weave"
 )
@@ -44,9 +45,10 @@ type Bucket struct {
 	prefix  []byte
 	proto   Cloneable
 	indexes map[string]Index
+	json    JSONMarshaller
 }
 
-var _ weave.QueryHandler = Bucket{}
+var _ weave.PagedQueryHandler = Bucket{}
 
 // NewBucket creates a bucket to store data
 func NewBucket(name string, proto Cloneable) Bucket {
@@ -58,12 +60,23 @@ func NewBucket(name string, proto Cloneable) Bucket {
 		name:   name,
 		prefix: append([]byte(name), ':'),
 		proto:  proto,
+		json:   DefaultJSON,
 	}
 }
 
+// WithJSON sets how the objects are marshalled for "?json"
+// queries, in place of DefaultJSON
+func (b Bucket) WithJSON(m JSONMarshaller) Bucket {
+	b.json = m
+	return b
+}
+
 // Register registers this Bucket and all indexes.
 // You can define a name here for queries, which is
 // different than the bucket name used to prefix the data
+//
+// As all of them return objects of this bucket, they can
+// all be queried with "?json" as well.
 func (b Bucket) Register(name string, r weave.QueryRouter) {
 	if name == "" {
 		name = b.name
@@ -73,6 +86,25 @@ func (b Bucket) Register(name string, r weave.QueryRouter) {
 	for name, idx := range b.indexes {
 		r.Register(root+"/"+name, idx)
 	}
+
+	// the root bucket has no type to decode into
+	if b.proto == nil || b.json == nil {
+		return
+	}
+	r.RegisterJSON(root, b.ToJSON)
+	for name := range b.indexes {
+		r.RegisterJSON(root+"/"+name, b.ToJSON)
+	}
+}
+
+// ToJSON decodes the value of an object of this bucket,
+// and marshals it as JSON
+func (b Bucket) ToJSON(value []byte) ([]byte, error) {
+	obj, err := b.Parse(nil, value)
+	if err != nil {
+		return nil, err
+	}
+	return b.json(obj)
 }
 
 // Query handles queries from the QueryRouter
@@ -92,11 +124,28 @@ func (b Bucket) Query(db weave.ReadOnlyKVStore, mod string,
 	case weave.PrefixQueryMod:
 		prefix := b.DBKey(data)
 		return queryPrefix(db, prefix), nil
+	case weave.RangeQueryMod:
+		q, err := ParseRangeQuery(data)
+		if err != nil {
+			return nil, err
+		}
+		return queryRange(db, b.prefix, q), nil
 	default:
 		return nil, errors.New("not implemented: " + mod)
 	}
 }
 
+// QueryPage handles paged prefix queries from the QueryRouter
+func (b Bucket) QueryPage(db weave.ReadOnlyKVStore, mod string,
+	data []byte, page weave.Page) ([]weave.Model, []byte, error) {
+
+	err := checkPageMod(mod)
+	if err != nil {
+		return nil, nil, err
+	}
+	return queryPrefixPage(db, b.DBKey(data), page)
+}
+
 // DBKey is the full key we store in the db, including prefix
 // We copy into a new array rather than use append, as we don't
 // want consequetive calls to overwrite the same byte array.
@@ -142,6 +191,26 @@ func (b Bucket) Parse(key, value []byte) (Object, error) {
 	return obj, nil
 }
 
+// All loads every object in the bucket, ordered by key.
+//
+// This reads the whole bucket into memory, so it is meant for
+// exporting state, not for use inside transactions.
+func (b Bucket) All(db weave.ReadOnlyKVStore) ([]Object, error) {
+	itr := db.Iterator(prefixRange(b.prefix))
+	defer itr.Close()
+
+	var res []Object
+	for ; itr.Valid(); itr.Next() {
+		key := itr.Key()[len(b.prefix):]
+		obj, err := b.Parse(key, itr.Value())
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, obj)
+	}
+	return res, nil
+}
+
 // Save will write a model, it must be of the same type as proto
 func (b Bucket) Save(db weave.KVStore, model Object) error {
 	err := model.Validate()
@@ -193,6 +262,38 @@ func (b Bucket) updateIndexes(db weave.KVStore, key []byte, model Object) error
 	return nil
 }
 
+// CheckIndexes verifies that every secondary index points to
+// exactly the objects in the bucket, and returns an error naming
+// all index keys that don't match.
+//
+// This reads the whole bucket into memory, like All.
+func (b Bucket) CheckIndexes(db weave.ReadOnlyKVStore) error {
+	if len(b.indexes) == 0 {
+		return nil
+	}
+	objs, err := b.All(db)
+	if err != nil {
+		return err
+	}
+	for _, name := range b.indexNames() {
+		err := b.indexes[name].Check(db, objs)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexNames returns the names of all indexes, sorted
+func (b Bucket) indexNames() []string {
+	names := make([]string, 0, len(b.indexes))
+	for name := range b.indexes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Sequence returns a Sequence by name
 func (b Bucket) Sequence(name string) Sequence {
 	return NewSequence(b.name, name)
@@ -203,13 +304,19 @@ func (b Bucket) Sequence(name string) Sequence {
 //
 // Designed to be chained.
 func (b Bucket) WithIndex(name string, indexer Indexer, unique bool) Bucket {
+	return b.WithMultiKeyIndex(name, asMultiKey(indexer), unique)
+}
+
+// WithMultiKeyIndex is like WithIndex, but each object may be
+// found under several index keys, like a blog by all authors.
+func (b Bucket) WithMultiKeyIndex(name string, indexer MultiKeyIndexer, unique bool) Bucket {
 	// no duplicate indexes! (panic on init)
 	if _, ok := b.indexes[name]; ok {
 		panic(fmt.Sprintf("Index %s registered twice", name))
 	}
 
 	iname := b.name + "_" + name
-	add := NewIndex(iname, indexer, unique, b.DBKey)
+	add := NewMultiKeyIndex(iname, indexer, unique, b.DBKey)
 	indexes := make(map[string]Index, len(b.indexes)+1)
 	for n, i := range b.indexes {
 		indexes[n] = i
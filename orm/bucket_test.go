@@ -289,6 +289,101 @@ func TestBucketIndex(t *testing.T) {
 			for _, q := range tc.queries {
 				q.check(t, b, db)
 			}
+			assert.NoError(t, b.CheckIndexes(db))
+		})
+	}
+}
+
+// Make sure an object is found under all keys of a multi-key index
+func TestBucketMultiKeyIndex(t *testing.T) {
+	bucket := NewBucket("tagged", NewSimpleObj(nil, new(MultiRef))).
+		WithMultiKeyIndex("tags", all, false)
+	a, b := []byte("a"), []byte("b")
+	foo, bar := []byte("foo"), []byte("bar")
+
+	db := store.MemStore()
+	oa := makeRefObj(a, foo, bar)
+	ob := makeRefObj(b, bar)
+	require.NoError(t, bucket.Save(db, oa))
+	require.NoError(t, bucket.Save(db, ob))
+	assert.NoError(t, bucket.CheckIndexes(db))
+
+	found, err := bucket.GetIndexed(db, "tags", bar)
+	require.NoError(t, err)
+	assert.Equal(t, []Object{oa, ob}, found)
+
+	// dropping a tag only removes that entry
+	oa2 := makeRefObj(a, foo)
+	require.NoError(t, bucket.Save(db, oa2))
+	found, err = bucket.GetIndexed(db, "tags", bar)
+	require.NoError(t, err)
+	assert.Equal(t, []Object{ob}, found)
+	found, err = bucket.GetIndexed(db, "tags", foo)
+	require.NoError(t, err)
+	assert.Equal(t, []Object{oa2}, found)
+	assert.NoError(t, bucket.CheckIndexes(db))
+
+	// and so does a missing entry
+	db.Delete(append([]byte("_i.tagged_tags:"), foo...))
+	err = bucket.CheckIndexes(db)
+	assert.True(t, IsInconsistentIndexErr(err), "%+v", err)
+}
+
+// Make sure we detect indexes that don't match the data
+func TestBucketCheckIndexes(t *testing.T) {
+	bucket := NewBucket("special", NewSimpleObj(nil, new(Counter))).
+		WithIndex("uniq", count, true).
+		WithIndex("mini", countByte, false)
+	oa := NewSimpleObj([]byte("a"), NewCounter(5))
+	ob := NewSimpleObj([]byte("b"), NewCounter(256+5))
+	raw := func(obj Object) []byte {
+		bz, err := obj.Value().Marshal()
+		require.NoError(t, err)
+		return bz
+	}
+
+	cases := map[string]struct {
+		corrupt func(weave.KVStore)
+		broken  string
+	}{
+		"consistent": {
+			corrupt: func(weave.KVStore) {},
+		},
+		"object without index": {
+			corrupt: func(db weave.KVStore) {
+				oc := NewSimpleObj([]byte("c"), NewCounter(7))
+				db.Set(bucket.DBKey(oc.Key()), raw(oc))
+			},
+			broken: "special_mini: 07",
+		},
+		"dangling index": {
+			corrupt: func(db weave.KVStore) {
+				db.Delete(bucket.DBKey(oa.Key()))
+			},
+			broken: "special_mini: 05",
+		},
+		"stale index": {
+			corrupt: func(db weave.KVStore) {
+				db.Set(bucket.DBKey(ob.Key()), raw(NewSimpleObj(ob.Key(), NewCounter(256+6))))
+			},
+			broken: "special_mini: 05, 06",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := store.MemStore()
+			require.NoError(t, bucket.Save(db, oa))
+			require.NoError(t, bucket.Save(db, ob))
+			tc.corrupt(db)
+
+			err := bucket.CheckIndexes(db)
+			if tc.broken == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.True(t, IsInconsistentIndexErr(err), "%+v", err)
+			assert.Contains(t, err.Error(), tc.broken)
 		})
 	}
 }
@@ -300,6 +395,84 @@ func toModel(t *testing.T, bucket Bucket, obj Object) weave.Model {
 	return weave.Model{Key: dbkey, Value: val}
 }
 
+// Check we can page through prefix queries on buckets and indexes
+func TestBucketQueryPage(t *testing.T) {
+	const mini = "mini"
+
+	bucket := NewBucket("spec", NewSimpleObj(nil, new(Counter))).
+		WithIndex(mini, countByte, false)
+	qr := weave.NewQueryRouter()
+	bucket.Register("", qr)
+
+	// 5 objects, 4 of them with the same index
+	db := store.MemStore()
+	var all []weave.Model
+	for i, count := range []int64{5, 5, 5, 2, 5} {
+		obj := NewSimpleObj([]byte{'a', byte(i)}, NewCounter(count))
+		err := bucket.Save(db, obj)
+		require.NoError(t, err)
+		all = append(all, toModel(t, bucket, obj))
+	}
+
+	cases := map[string]struct {
+		path     string
+		mod      string
+		limit    int
+		expected [][]weave.Model
+	}{
+		"bucket": {"/spec", "prefix", 2, [][]weave.Model{
+			all[0:2], all[2:4], all[4:5],
+		}},
+		"bucket exact pages": {"/spec", "prefix", 5, [][]weave.Model{
+			all,
+		}},
+		"index splits refs": {"/spec/mini", "prefix", 3, [][]weave.Model{
+			{all[3], all[0], all[1]}, {all[2], all[4]},
+		}},
+		"index fills entry": {"/spec/mini", "prefix", 1, [][]weave.Model{
+			{all[3]}, {all[0]}, {all[1]}, {all[2]}, {all[4]},
+		}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			qh, ok := qr.Handler(tc.path).(weave.PagedQueryHandler)
+			require.True(t, ok)
+
+			var cursor []byte
+			for i, expected := range tc.expected {
+				page := weave.Page{Limit: tc.limit, Cursor: cursor}
+				res, next, err := qh.QueryPage(db, tc.mod, nil, page)
+				require.NoError(t, err)
+				assert.Equal(t, expected, res, "page %d", i)
+				if i == len(tc.expected)-1 {
+					assert.Nil(t, next)
+				} else {
+					assert.NotNil(t, next)
+				}
+				cursor = next
+			}
+		})
+	}
+
+	// only prefix queries and valid cursors
+	qh := qr.Handler("/spec").(weave.PagedQueryHandler)
+	_, _, err := qh.QueryPage(db, "", []byte{'a', 0}, weave.Page{Limit: 2})
+	assert.Error(t, err)
+	bad, err := (&Cursor{Key: []byte("zzz")}).Marshal()
+	require.NoError(t, err)
+	_, _, err = qh.QueryPage(db, "prefix", nil, weave.Page{Limit: 2, Cursor: bad})
+	assert.Error(t, err)
+}
+
+// rangeData serializes a RangeQuery
+func rangeData(t *testing.T, start, end []byte, reverse bool, limit int64) []byte {
+	q := RangeQuery{Start: start, End: end, Reverse: reverse, Limit: limit}
+	bz, err := q.Marshal()
+	require.NoError(t, err)
+	return bz
+}
+
 // Check query interface works, also with embedded indexes
 func TestBucketQuery(t *testing.T) {
 	// make some buckets for testing
@@ -411,6 +584,43 @@ func TestBucketQuery(t *testing.T) {
 		13: {
 			uiPath, "prefix", nil, false, false, []weave.Model{dbc, dba, dbb},
 		},
+		// range query - everything
+		14: {
+			bPath, "range", rangeData(t, nil, nil, false, 0), false, false,
+			[]weave.Model{dba, dbb, dbc},
+		},
+		// range query - bounded, end is exclusive
+		15: {
+			bPath, "range", rangeData(t, b, c, false, 0), false, false,
+			[]weave.Model{dbb},
+		},
+		// range query - reverse with limit
+		16: {
+			bPath, "range", rangeData(t, nil, nil, true, 2), false, false,
+			[]weave.Model{dbc, dbb},
+		},
+		// range query - invalid
+		17: {
+			bPath, "range", rangeData(t, c, a, false, 0), false, true, nil,
+		},
+		18: {
+			bPath, "range", []byte{0x12, 0x77}, false, true, nil,
+		},
+		// range index - everything from 5 on
+		19: {
+			iPath, "range", rangeData(t, e5, nil, false, 0), false, false,
+			[]weave.Model{dba, dbb},
+		},
+		// range index - reverse, limit cuts multiref
+		20: {
+			iPath, "range", rangeData(t, nil, nil, true, 2), false, false,
+			[]weave.Model{dbb, dba},
+		},
+		// unique range index - reverse with end
+		21: {
+			uiPath, "range", rangeData(t, nil, encodeSequence(256+5), true, 0), false, false,
+			[]weave.Model{dba, dbc},
+		},
 	}
 
 	for i, tc := range cases {
@@ -2,14 +2,16 @@
 // source: orm/codec.proto
 
 /*
-Package orm is a generated protocol buffer package.
+	Package orm is a generated protocol buffer package.
 
-It is generated from these files:
-	orm/codec.proto
+	It is generated from these files:
+		orm/codec.proto
 
-It has these top-level messages:
-	MultiRef
-	Counter
+	It has these top-level messages:
+		MultiRef
+		Counter
+		RangeQuery
+		Cursor
 */
 package orm
 
@@ -64,9 +66,83 @@ func (m *Counter) GetCount() int64 {
 	return 0
 }
 
+// RangeQuery is the data passed to a "?range" query.
+// Start and End are relative to the bucket or index
+// (without prefix). End is exclusive, and an empty
+// bound means no limit on that side.
+// A Limit of 0 returns all results.
+type RangeQuery struct {
+	Start   []byte `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End     []byte `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	Reverse bool   `protobuf:"varint,3,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	Limit   int64  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (m *RangeQuery) Reset()                    { *m = RangeQuery{} }
+func (m *RangeQuery) String() string            { return proto.CompactTextString(m) }
+func (*RangeQuery) ProtoMessage()               {}
+func (*RangeQuery) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{2} }
+
+func (m *RangeQuery) GetStart() []byte {
+	if m != nil {
+		return m.Start
+	}
+	return nil
+}
+
+func (m *RangeQuery) GetEnd() []byte {
+	if m != nil {
+		return m.End
+	}
+	return nil
+}
+
+func (m *RangeQuery) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
+func (m *RangeQuery) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// Cursor points to the next result of a paged query.
+// Key is the db key to continue from, and Offset the
+// number of references to skip at this key (for indexes)
+type Cursor struct {
+	Key    []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Offset int64  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (m *Cursor) Reset()                    { *m = Cursor{} }
+func (m *Cursor) String() string            { return proto.CompactTextString(m) }
+func (*Cursor) ProtoMessage()               {}
+func (*Cursor) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{3} }
+
+func (m *Cursor) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *Cursor) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*MultiRef)(nil), "orm.MultiRef")
 	proto.RegisterType((*Counter)(nil), "orm.Counter")
+	proto.RegisterType((*RangeQuery)(nil), "orm.RangeQuery")
+	proto.RegisterType((*Cursor)(nil), "orm.Cursor")
 }
 func (m *MultiRef) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -117,6 +193,80 @@ func (m *Counter) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *RangeQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RangeQuery) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Start) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Start)))
+		i += copy(dAtA[i:], m.Start)
+	}
+	if len(m.End) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.End)))
+		i += copy(dAtA[i:], m.End)
+	}
+	if m.Reverse {
+		dAtA[i] = 0x18
+		i++
+		if m.Reverse {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Limit != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Limit))
+	}
+	return i, nil
+}
+
+func (m *Cursor) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Cursor) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if m.Offset != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Offset))
+	}
+	return i, nil
+}
+
 func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -147,6 +297,39 @@ func (m *Counter) Size() (n int) {
 	return n
 }
 
+func (m *RangeQuery) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Start)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.End)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Reverse {
+		n += 2
+	}
+	if m.Limit != 0 {
+		n += 1 + sovCodec(uint64(m.Limit))
+	}
+	return n
+}
+
+func (m *Cursor) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Offset != 0 {
+		n += 1 + sovCodec(uint64(m.Offset))
+	}
+	return n
+}
+
 func sovCodec(x uint64) (n int) {
 	for {
 		n++
@@ -308,6 +491,257 @@ func (m *Counter) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *RangeQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RangeQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RangeQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Start = append(m.Start[:0], dAtA[iNdEx:postIndex]...)
+			if m.Start == nil {
+				m.Start = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.End = append(m.End[:0], dAtA[iNdEx:postIndex]...)
+			if m.End == nil {
+				m.End = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reverse", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Reverse = bool(v != 0)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
+			}
+			m.Limit = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Limit |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Cursor) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Cursor: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Cursor: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipCodec(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -416,13 +850,19 @@ var (
 func init() { proto.RegisterFile("orm/codec.proto", fileDescriptorCodec) }
 
 var fileDescriptorCodec = []byte{
-	// 125 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcf, 0x2f, 0xca, 0xd5,
-	0x4f, 0xce, 0x4f, 0x49, 0x4d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0xce, 0x2f, 0xca,
-	0x55, 0x92, 0xe3, 0xe2, 0xf0, 0x2d, 0xcd, 0x29, 0xc9, 0x0c, 0x4a, 0x4d, 0x13, 0x12, 0xe2, 0x62,
-	0x29, 0x4a, 0x4d, 0x2b, 0x96, 0x60, 0x54, 0x60, 0xd6, 0xe0, 0x09, 0x02, 0xb3, 0x95, 0xe4, 0xb9,
-	0xd8, 0x9d, 0xf3, 0x4b, 0xf3, 0x4a, 0x52, 0x8b, 0x84, 0x44, 0xb8, 0x58, 0x93, 0x41, 0x4c, 0x09,
-	0x46, 0x05, 0x46, 0x0d, 0xe6, 0x20, 0x08, 0xc7, 0x49, 0xe0, 0xc4, 0x23, 0x39, 0xc6, 0x0b, 0x8f,
-	0xe4, 0x18, 0x1f, 0x3c, 0x92, 0x63, 0x9c, 0xf0, 0x58, 0x8e, 0x21, 0x89, 0x0d, 0x6c, 0xbc, 0x31,
-	0x20, 0x00, 0x00, 0xff, 0xff, 0xa8, 0xc3, 0x7c, 0x5d, 0x71, 0x00, 0x00, 0x00,
+	// 221 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x34, 0x8f, 0x41, 0x4a, 0xc4, 0x30,
+	0x14, 0x86, 0x8d, 0x19, 0x3b, 0xc3, 0x63, 0xc0, 0x21, 0x88, 0x64, 0x15, 0x4b, 0x57, 0x5d, 0x29,
+	0xe8, 0x0d, 0x9c, 0xb5, 0x0b, 0x73, 0x83, 0xb1, 0x7d, 0x91, 0xe2, 0xb4, 0x4f, 0x5e, 0x12, 0x61,
+	0x6e, 0xe1, 0xb1, 0x5c, 0x7a, 0x04, 0xa9, 0x17, 0x91, 0x24, 0x75, 0xf7, 0x7d, 0x8f, 0x9f, 0xff,
+	0xe7, 0xc1, 0x25, 0xf1, 0x78, 0xd7, 0x51, 0x8f, 0xdd, 0xed, 0x3b, 0x53, 0x20, 0x25, 0x89, 0xc7,
+	0xc6, 0xc0, 0xe6, 0x29, 0x1e, 0xc3, 0x60, 0xd1, 0x29, 0x05, 0x2b, 0x46, 0xe7, 0xb5, 0xa8, 0x65,
+	0xbb, 0xb5, 0x99, 0x9b, 0x1b, 0x58, 0xef, 0x29, 0x4e, 0x01, 0x59, 0x5d, 0xc1, 0x45, 0x97, 0x50,
+	0x8b, 0x5a, 0xb4, 0xd2, 0x16, 0x69, 0x7a, 0x00, 0x7b, 0x98, 0x5e, 0xf1, 0x39, 0x22, 0x9f, 0x52,
+	0xc6, 0x87, 0x03, 0x97, 0xcc, 0xd6, 0x16, 0x51, 0x3b, 0x90, 0x38, 0xf5, 0xfa, 0x3c, 0xdf, 0x12,
+	0x2a, 0x0d, 0x6b, 0xc6, 0x0f, 0x64, 0x8f, 0x5a, 0xd6, 0xa2, 0xdd, 0xd8, 0x7f, 0x4d, 0x0d, 0xc7,
+	0x61, 0x1c, 0x82, 0x5e, 0x95, 0x95, 0x2c, 0xcd, 0x3d, 0x54, 0xfb, 0xc8, 0x9e, 0x38, 0x75, 0xbd,
+	0xe1, 0x69, 0xe9, 0x4f, 0xa8, 0xae, 0xa1, 0x22, 0xe7, 0x3c, 0x86, 0x3c, 0x20, 0xed, 0x62, 0x8f,
+	0xbb, 0xaf, 0xd9, 0x88, 0xef, 0xd9, 0x88, 0x9f, 0xd9, 0x88, 0xcf, 0x5f, 0x73, 0xf6, 0x52, 0xe5,
+	0xc7, 0x1f, 0xfe, 0x06, 0x00, 0x50, 0x7b, 0x7a, 0x39, 0x0b, 0x01, 0x00, 0x00,
 }
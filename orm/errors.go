@@ -15,6 +15,10 @@ const (
 	CodeInvalidModification = 13
 	CodeInvalidObject       = 14
 	CodeProgrammer          = 15
+	CodeInvalidQuery        = 16
+	CodeInconsistent        = 17
+	CodeInvalidKey          = 18
+	CodeInvalidGenesis      = 19
 )
 
 var (
@@ -35,6 +39,12 @@ var (
 
 	errUpdateNil = fmt.Errorf("update requires at least one non-nil object")
 	errBoolean   = fmt.Errorf("You have violated the rules of boolean logic")
+
+	errInvalidRange = fmt.Errorf("Invalid range query")
+
+	errInconsistentIndex = fmt.Errorf("Index does not match the data")
+	errInvalidKey        = fmt.Errorf("Invalid composite key")
+	errInvalidGenesis    = fmt.Errorf("Invalid bucket state")
 )
 
 func ErrInvalidObject(obj interface{}) error {
@@ -94,3 +104,31 @@ func ErrUpdateNil() error {
 func ErrBoolean() error {
 	return errors.WithCode(errBoolean, CodeProgrammer)
 }
+
+func ErrInvalidRange(reason string) error {
+	return errors.WithLog(reason, errInvalidRange, CodeInvalidQuery)
+}
+func IsInvalidRangeErr(err error) bool {
+	return errors.IsSameError(errInvalidRange, err)
+}
+
+func ErrInconsistentIndex(reason string) error {
+	return errors.WithLog(reason, errInconsistentIndex, CodeInconsistent)
+}
+func IsInconsistentIndexErr(err error) bool {
+	return errors.IsSameError(errInconsistentIndex, err)
+}
+
+func ErrInvalidKey(reason string) error {
+	return errors.WithLog(reason, errInvalidKey, CodeInvalidKey)
+}
+func IsInvalidKeyErr(err error) bool {
+	return errors.IsSameError(errInvalidKey, err)
+}
+
+func ErrInvalidGenesis(reason string) error {
+	return errors.WithLog(reason, errInvalidGenesis, CodeInvalidGenesis)
+}
+func IsInvalidGenesisErr(err error) bool {
+	return errors.IsSameError(errInvalidGenesis, err)
+}
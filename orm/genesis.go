@@ -0,0 +1,185 @@
+package orm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+
+	"github.com/confio/weave"
+)
+
+// BucketState is the content of a bucket in the genesis file,
+// so the state of a module can be exported and loaded into a
+// new chain. Indexes are not stored, they are rebuilt on import.
+type BucketState struct {
+	Objects []GenesisObject `json:"objects"`
+	// Sequences holds the current value of each sequence by
+	// name, so new ids never collide with the imported ones
+	Sequences map[string]int64 `json:"sequences,omitempty"`
+}
+
+// GenesisObject is one object of a bucket, the key in hex and
+// the value in the protobuf json mapping of its type
+type GenesisObject struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+// IsEmpty is true if there is nothing to export
+func (s *BucketState) IsEmpty() bool {
+	return len(s.Objects) == 0 && len(s.Sequences) == 0
+}
+
+// Export returns all objects and sequences of the bucket
+func (b Bucket) Export(db weave.ReadOnlyKVStore) (*BucketState, error) {
+	objs, err := b.All(db)
+	if err != nil {
+		return nil, err
+	}
+	state := &BucketState{Objects: make([]GenesisObject, len(objs))}
+	marshaler := jsonpb.Marshaler{OrigName: true}
+	for i, obj := range objs {
+		msg, err := asMessage(obj.Value())
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshaler.MarshalToString(msg)
+		if err != nil {
+			return nil, err
+		}
+		state.Objects[i] = GenesisObject{
+			Key:   strings.ToUpper(hex.EncodeToString(obj.Key())),
+			Value: json.RawMessage(value),
+		}
+	}
+
+	prefix := seqBucketPrefix(b.name)
+	for _, m := range queryPrefix(db, prefix) {
+		if state.Sequences == nil {
+			state.Sequences = make(map[string]int64)
+		}
+		name := string(m.Key[len(prefix):])
+		state.Sequences[name] = decodeSequence(m.Value)
+	}
+	return state, nil
+}
+
+// Import validates and saves all objects, with their indexes,
+// and sets the sequences.
+//
+// If the state was exported from a running chain at exportHeight,
+// all objects that are a Rebaser are moved to the new chain first.
+func (b Bucket) Import(db weave.KVStore, state *BucketState, exportHeight int64) error {
+	for _, o := range state.Objects {
+		key, err := hex.DecodeString(o.Key)
+		if err != nil {
+			return ErrInvalidGenesis("key " + o.Key)
+		}
+		obj := b.proto.Clone()
+		msg, err := asMessage(obj.Value())
+		if err != nil {
+			return err
+		}
+		err = jsonpb.Unmarshal(bytes.NewReader(o.Value), msg)
+		if err != nil {
+			return err
+		}
+		if r, ok := msg.(Rebaser); ok && exportHeight > 0 {
+			if !r.Rebase(exportHeight) {
+				continue
+			}
+		}
+		obj.SetKey(key)
+		err = b.Save(db, obj)
+		if err != nil {
+			return err
+		}
+	}
+
+	prefix := seqBucketPrefix(b.name)
+	for name, val := range state.Sequences {
+		if val < 0 {
+			return ErrInvalidGenesis("negative sequence " + name)
+		}
+		db.Set(joinKey(prefix, []byte(name)), encodeSequence(val))
+	}
+	return nil
+}
+
+// RebaseHeight moves a pending height, like a timeout, from the
+// exported chain to the new one. Heights that had already passed
+// are moved to the first block, so they stay passed.
+func RebaseHeight(height, exportHeight int64) int64 {
+	if height <= exportHeight {
+		return 1
+	}
+	return height - exportHeight
+}
+
+// asMessage makes sure we can use the protobuf json mapping,
+// which also supports oneof fields
+func asMessage(data weave.Persistent) (proto.Message, error) {
+	msg, ok := data.(proto.Message)
+	if !ok {
+		return nil, ErrInvalidObject(data)
+	}
+	return msg, nil
+}
+
+// ExportBuckets writes the state of the buckets to the options
+// under key, by bucket name, so ImportBuckets can load them.
+// Nothing is written if all of them are empty.
+func ExportBuckets(opts weave.Options, key string,
+	db weave.ReadOnlyKVStore, buckets ...Bucket) error {
+
+	states := make(map[string]*BucketState, len(buckets))
+	for _, b := range buckets {
+		state, err := b.Export(db)
+		if err != nil {
+			return err
+		}
+		if !state.IsEmpty() {
+			states[b.name] = state
+		}
+	}
+	if len(states) == 0 {
+		return nil
+	}
+	return opts.WriteOptions(key, states)
+}
+
+// ImportBuckets loads the state of the buckets stored under
+// key by ExportBuckets, if there is any, rebased to the
+// export height of the options
+func ImportBuckets(opts weave.Options, key string,
+	db weave.KVStore, buckets ...Bucket) error {
+
+	var states map[string]*BucketState
+	err := opts.ReadOptions(key, &states)
+	if err != nil {
+		return err
+	}
+	exportHeight, err := opts.ExportHeight()
+	if err != nil {
+		return err
+	}
+	for _, b := range buckets {
+		state, ok := states[b.name]
+		if !ok {
+			continue
+		}
+		delete(states, b.name)
+		err := b.Import(db, state, exportHeight)
+		if err != nil {
+			return err
+		}
+	}
+	for name := range states {
+		return ErrInvalidGenesis("unknown bucket " + name)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+)
+
+func TestExportImport(t *testing.T) {
+	newBucket := func(name string) Bucket {
+		return NewBucket(name, NewSimpleObj(nil, new(Counter))).
+			WithIndex("mini", countByte, false)
+	}
+	counters, empty := newBucket("counter"), newBucket("empty")
+
+	db := store.MemStore()
+	seq := counters.Sequence(SeqID)
+	for _, n := range []int64{5, 300} {
+		obj := NewSimpleObj(seq.NextVal(db), NewCounter(n))
+		require.NoError(t, counters.Save(db, obj))
+	}
+
+	// empty buckets are left out
+	opts := make(weave.Options)
+	require.NoError(t, ExportBuckets(opts, "test", db, counters, empty))
+	state, err := counters.Export(db)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(state.Objects))
+	assert.Equal(t, "0000000000000001", state.Objects[0].Key)
+	assert.JSONEq(t, `{"count":"5"}`, string(state.Objects[0].Value))
+	assert.Equal(t, map[string]int64{SeqID: 2}, state.Sequences)
+
+	// a new chain has the same data, indexes and sequences
+	db2 := store.MemStore()
+	require.NoError(t, ImportBuckets(opts, "test", db2, counters, empty))
+	all, err := counters.All(db2)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(all))
+	assert.Equal(t, NewCounter(300), all[1].Value())
+	require.NoError(t, counters.CheckIndexes(db2))
+	seq2 := counters.Sequence(SeqID)
+	assert.Equal(t, int64(3), seq2.NextInt(db2))
+
+	// nothing to import is fine, unknown buckets are not
+	require.NoError(t, ImportBuckets(opts, "other", db2, counters))
+	err = ImportBuckets(opts, "test", store.MemStore(), empty)
+	assert.True(t, IsInvalidGenesisErr(err), "%+v", err)
+	bad := &BucketState{Objects: []GenesisObject{{Key: "zz", Value: []byte("{}")}}}
+	err = counters.Import(store.MemStore(), bad, 0)
+	assert.True(t, IsInvalidGenesisErr(err), "%+v", err)
+}
+
+func TestRebaseHeight(t *testing.T) {
+	cases := []struct {
+		height, exportHeight, expected int64
+	}{
+		{12, 5, 7},
+		{6, 5, 1},
+		// passed heights stay passed
+		{5, 5, 1},
+		{2, 5, 1},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.expected, RebaseHeight(tc.height, tc.exportHeight))
+	}
+}
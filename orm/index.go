@@ -3,6 +3,9 @@ package orm
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/confio/weave"
 )
@@ -12,25 +15,38 @@ var indPrefix = []byte("_i.")
 // Indexer calculates the secondary index key for a given object
 type Indexer func(Object) ([]byte, error)
 
+// MultiKeyIndexer calculates all secondary index keys for a
+// given object, like one for every author of a blog.
+// Duplicates and empty keys are ignored
+type MultiKeyIndexer func(Object) ([][]byte, error)
+
 // Index represents a secondary index on some data.
-// It is indexed by an arbitrary key returned by Indexer.
+// It is indexed by arbitrary keys returned by MultiKeyIndexer.
 // The value is one primary key (unique),
 // Or an array of primary keys (!unique).
 type Index struct {
 	name   string
 	id     []byte
 	unique bool
-	index  Indexer
+	index  MultiKeyIndexer
 	refKey func([]byte) []byte
 }
 
-var _ weave.QueryHandler = Index{}
+var _ weave.PagedQueryHandler = Index{}
 
 // NewIndex constructs an index
 // Indexer calculcates the index for an object
 // unique enforces a unique constraint on the index
 // refKey calculates the absolute dbkey for a ref
 func NewIndex(name string, indexer Indexer, unique bool,
+	refKey func([]byte) []byte) Index {
+	return NewMultiKeyIndex(name, asMultiKey(indexer), unique, refKey)
+}
+
+// NewMultiKeyIndex is like NewIndex, but every object may
+// be stored under any number of index keys. With unique,
+// no two objects may share any key
+func NewMultiKeyIndex(name string, indexer MultiKeyIndexer, unique bool,
 	refKey func([]byte) []byte) Index {
 	// TODO: index name must be [a-z_]
 	return Index{
@@ -42,6 +58,37 @@ func NewIndex(name string, indexer Indexer, unique bool,
 	}
 }
 
+// asMultiKey wraps an Indexer to return one key, or none
+// if it is empty
+func asMultiKey(indexer Indexer) MultiKeyIndexer {
+	return func(obj Object) ([][]byte, error) {
+		key, err := indexer(obj)
+		if err != nil || len(key) == 0 {
+			return nil, err
+		}
+		return [][]byte{key}, nil
+	}
+}
+
+// keys returns the non-empty index keys of obj without
+// duplicates, in the order the indexer returned them
+func (i Index) keys(obj Object) ([][]byte, error) {
+	keys, err := i.index(obj)
+	if err != nil {
+		return nil, err
+	}
+	res := make([][]byte, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if len(key) == 0 || seen[string(key)] {
+			continue
+		}
+		seen[string(key)] = true
+		res = append(res, key)
+	}
+	return res, nil
+}
+
 // IndexKey is the full key we store in the db, including prefix
 // We copy into a new array rather than use append, as we don't
 // want consequetive calls to overwrite the same byte array.
@@ -70,31 +117,104 @@ func (i Index) Update(db weave.KVStore, prev Object, save Object) error {
 	case s{true, true}:
 		return ErrUpdateNil()
 	case s{true, false}:
-		key, err := i.index(save)
+		keys, err := i.keys(save)
 		if err != nil {
 			return err
 		}
-		return i.insert(db, key, save.Key())
+		return i.insertAll(db, keys, save.Key())
 	case s{false, true}:
-		key, err := i.index(prev)
+		keys, err := i.keys(prev)
 		if err != nil {
 			return err
 		}
-		return i.remove(db, key, prev.Key())
+		return i.removeAll(db, keys, prev.Key())
 	case s{false, false}:
 		return i.move(db, prev, save)
 	}
 	return ErrBoolean()
 }
 
-// GetLike calculates the index for the given pattern, and
-// returns a list of all pk that match (may be empty), or an error
+// Check compares the index with objs, which must be all objects
+// in the bucket ordered by key, as returned by Bucket.All.
+// Returns an error naming every index key that is missing,
+// dangling, or points to the wrong objects
+func (i Index) Check(db weave.ReadOnlyKVStore, objs []Object) error {
+	// we expect these refs, in the order of the primary key
+	want := make(map[string][][]byte)
+	for _, obj := range objs {
+		keys, err := i.keys(obj)
+		if err != nil {
+			return err
+		}
+		for _, index := range keys {
+			want[string(index)] = append(want[string(index)], obj.Key())
+		}
+	}
+
+	var bad []string
+	itr := db.Iterator(prefixRange(i.id))
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		index := string(itr.Key()[len(i.id):])
+		refs, err := i.parseRefs(itr.Value())
+		if err != nil {
+			return err
+		}
+		if !equalRefs(refs, want[index]) {
+			bad = append(bad, fmt.Sprintf("%X", index))
+		}
+		delete(want, index)
+	}
+	// those were never written
+	for index := range want {
+		bad = append(bad, fmt.Sprintf("%X", index))
+	}
+
+	if len(bad) > 0 {
+		sort.Strings(bad)
+		msg := fmt.Sprintf("%s: %s", i.name, strings.Join(bad, ", "))
+		return ErrInconsistentIndex(msg)
+	}
+	return nil
+}
+
+// equalRefs returns true if both have the same refs in
+// the same order
+func equalRefs(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for j := range a {
+		if !bytes.Equal(a[j], b[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetLike calculates the indexes for the given pattern, and
+// returns a list of all pk that match any of them (may be
+// empty), or an error
 func (i Index) GetLike(db weave.ReadOnlyKVStore, pattern Object) ([][]byte, error) {
-	index, err := i.index(pattern)
+	keys, err := i.keys(pattern)
 	if err != nil {
 		return nil, err
 	}
-	return i.GetAt(db, index)
+	var res [][]byte
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		refs, err := i.GetAt(db, key)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			if !seen[string(ref)] {
+				seen[string(ref)] = true
+				res = append(res, ref)
+			}
+		}
+	}
+	return res, nil
 }
 
 // GetAt returns a list of all pk at that index (may be empty), or an error
@@ -120,24 +240,110 @@ func (i Index) GetAt(db weave.ReadOnlyKVStore, index []byte) ([][]byte, error) {
 func (i Index) GetPrefix(db weave.ReadOnlyKVStore, prefix []byte) ([][]byte, error) {
 	dbPrefix := i.IndexKey(prefix)
 	itr := db.Iterator(prefixRange(dbPrefix))
-	var data [][]byte
+	return i.consumeRefs(itr, false, 0)
+}
+
+// GetRange returns the references for all indexes in the
+// given range, in the order of the index (or reversed).
+// With a limit, it returns at most limit references.
+func (i Index) GetRange(db weave.ReadOnlyKVStore, q *RangeQuery) ([][]byte, error) {
+	return i.consumeRefs(q.Iterator(db, i.id), q.Reverse, q.Limit)
+}
+
+// consumeRefs reads all references from the iterator and
+// closes it. If reverse is set, the refs of a non-unique
+// index are also returned in reverse order.
+func (i Index) consumeRefs(itr weave.Iterator, reverse bool, limit int64) ([][]byte, error) {
+	defer itr.Close()
 
+	var data [][]byte
 	for ; itr.Valid(); itr.Next() {
-		if i.unique {
-			data = append(data, itr.Value())
-		} else {
-			tmp := new(MultiRef)
-			err := tmp.Unmarshal(itr.Value())
-			if err != nil {
-				return nil, err
-			}
-			data = append(data, tmp.Refs...)
+		refs, err := i.parseRefs(itr.Value())
+		if err != nil {
+			return nil, err
+		}
+		if reverse {
+			refs = reverseRefs(refs)
+		}
+		data = append(data, refs...)
+		if limit > 0 && int64(len(data)) >= limit {
+			return data[:limit], nil
 		}
 	}
 
 	return data, nil
 }
 
+// GetPrefixPage is like GetPrefix, but returns at most page.Limit
+// references, along with a cursor for the next page
+func (i Index) GetPrefixPage(db weave.ReadOnlyKVStore, prefix []byte,
+	page weave.Page) ([][]byte, []byte, error) {
+
+	start, end := prefixRange(i.IndexKey(prefix))
+	cursor, err := parseCursor(page.Cursor, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	var offset int64
+	if cursor != nil {
+		start, offset = cursor.Key, cursor.Offset
+	}
+
+	itr := db.Iterator(start, end)
+	defer itr.Close()
+
+	var data [][]byte
+	for ; itr.Valid(); itr.Next() {
+		if len(data) == page.Limit {
+			next := Cursor{Key: itr.Key()}
+			bz, err := next.Marshal()
+			return data, bz, err
+		}
+		refs, err := i.parseRefs(itr.Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		if offset > int64(len(refs)) {
+			return nil, nil, errCursorOutOfRange()
+		}
+		refs = refs[offset:]
+
+		// we may have to split the refs of one index
+		room := page.Limit - len(data)
+		if len(refs) > room {
+			data = append(data, refs[:room]...)
+			next := Cursor{Key: itr.Key(), Offset: offset + int64(room)}
+			bz, err := next.Marshal()
+			return data, bz, err
+		}
+		data = append(data, refs...)
+		offset = 0
+	}
+	return data, nil, nil
+}
+
+// parseRefs returns all references stored in one index value
+func (i Index) parseRefs(value []byte) ([][]byte, error) {
+	if i.unique {
+		return [][]byte{value}, nil
+	}
+	var data = new(MultiRef)
+	err := data.Unmarshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return data.GetRefs(), nil
+}
+
+// reverseRefs returns a copy of the refs in reverse order
+func reverseRefs(refs [][]byte) [][]byte {
+	res := make([][]byte, len(refs))
+	for j, ref := range refs {
+		res[len(refs)-1-j] = ref
+	}
+	return res
+}
+
 // Query handles queries from the QueryRouter
 func (i Index) Query(db weave.ReadOnlyKVStore, mod string,
 	data []byte) ([]weave.Model, error) {
@@ -155,11 +361,36 @@ func (i Index) Query(db weave.ReadOnlyKVStore, mod string,
 			return nil, err
 		}
 		return i.loadRefs(db, refs), nil
+	case weave.RangeQueryMod:
+		q, err := ParseRangeQuery(data)
+		if err != nil {
+			return nil, err
+		}
+		refs, err := i.GetRange(db, q)
+		if err != nil {
+			return nil, err
+		}
+		return i.loadRefs(db, refs), nil
 	default:
 		return nil, errors.New("no implemented: " + mod)
 	}
 }
 
+// QueryPage handles paged prefix queries from the QueryRouter
+func (i Index) QueryPage(db weave.ReadOnlyKVStore, mod string,
+	data []byte, page weave.Page) ([]weave.Model, []byte, error) {
+
+	err := checkPageMod(mod)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs, next, err := i.GetPrefixPage(db, data, page)
+	if err != nil {
+		return nil, nil, err
+	}
+	return i.loadRefs(db, refs), next, nil
+}
+
 func (i Index) loadRefs(db weave.ReadOnlyKVStore,
 	refs [][]byte) []weave.Model {
 
@@ -183,33 +414,88 @@ func (i Index) move(db weave.KVStore, prev Object, save Object) error {
 		return ErrModifiedPK()
 	}
 
-	// if the keys don't change, then
-	oldKey, err := i.index(prev)
+	oldKeys, err := i.keys(prev)
 	if err != nil {
 		return err
 	}
-	newKey, err := i.index(save)
+	newKeys, err := i.keys(save)
 	if err != nil {
 		return err
 	}
-	if bytes.Equal(oldKey, newKey) {
+	// only touch the keys that change
+	removed := subtractKeys(oldKeys, newKeys)
+	added := subtractKeys(newKeys, oldKeys)
+	if len(removed) == 0 && len(added) == 0 {
 		return nil
 	}
 
 	// check unique constraint before removing
-	if i.unique && len(oldKey) != 0 {
-		k := i.IndexKey(newKey)
-		val := db.Get(k)
-		if val != nil {
+	err = i.checkUnique(db, added)
+	if err != nil {
+		return err
+	}
+	err = i.removeAll(db, removed, prev.Key())
+	if err != nil {
+		return err
+	}
+	return i.insertAll(db, added, save.Key())
+}
+
+// subtractKeys returns all keys in a that are not in b
+func subtractKeys(a, b [][]byte) [][]byte {
+	skip := make(map[string]bool, len(b))
+	for _, key := range b {
+		skip[string(key)] = true
+	}
+	var res [][]byte
+	for _, key := range a {
+		if !skip[string(key)] {
+			res = append(res, key)
+		}
+	}
+	return res
+}
+
+// removeAll removes pk from all the given indexes
+func (i Index) removeAll(db weave.KVStore, indexes [][]byte, pk []byte) error {
+	for _, index := range indexes {
+		err := i.remove(db, index, pk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkUnique returns an error if this is a unique index,
+// and any of the given indexes is taken
+func (i Index) checkUnique(db weave.ReadOnlyKVStore, indexes [][]byte) error {
+	if !i.unique {
+		return nil
+	}
+	for _, index := range indexes {
+		if db.Get(i.IndexKey(index)) != nil {
 			return ErrUniqueConstraint(i.name)
 		}
 	}
+	return nil
+}
 
-	err = i.remove(db, oldKey, prev.Key())
+// insertAll adds pk to all the given indexes. With unique,
+// it checks all of them first, so nothing is written on
+// a conflict
+func (i Index) insertAll(db weave.KVStore, indexes [][]byte, pk []byte) error {
+	err := i.checkUnique(db, indexes)
 	if err != nil {
 		return err
 	}
-	return i.insert(db, newKey, save.Key())
+	for _, index := range indexes {
+		err := i.insert(db, index, pk)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (i Index) remove(db weave.KVStore, index []byte, pk []byte) error {
@@ -207,3 +207,117 @@ func TestNullableIndex(t *testing.T) {
 	}
 
 }
+
+// indexer for MultiRef, returns all values
+func all(obj Object) ([][]byte, error) {
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	multi, ok := obj.Value().(*MultiRef)
+	if !ok {
+		return nil, errors.New("Can only take index of MultiRef")
+	}
+	return multi.Refs, nil
+}
+
+func TestMultiKeyIndex(t *testing.T) {
+	multi := NewMultiKeyIndex("tags", all, false, nil)
+	uniq := NewMultiKeyIndex("names", all, true, nil)
+
+	k1, k2 := []byte("abc"), []byte("def")
+	foo, bar, baz := []byte("foo"), []byte("bar"), []byte("baz")
+
+	cases := map[string]struct {
+		idx        Index
+		setup      []Object
+		prev, next Object
+		isError    bool
+		// the refs at each index after the update
+		at map[string][][]byte
+	}{
+		"insert all, ignoring duplicates and empty": {
+			idx:  multi,
+			next: makeRefObj(k1, foo, nil, bar, foo),
+			at:   map[string][][]byte{"foo": {k1}, "bar": {k1}, "baz": nil},
+		},
+		"shared keys": {
+			idx:   multi,
+			setup: []Object{makeRefObj(k1, foo, bar)},
+			next:  makeRefObj(k2, bar, baz),
+			at:    map[string][][]byte{"foo": {k1}, "bar": {k1, k2}, "baz": {k2}},
+		},
+		"move only changes the difference": {
+			idx:   multi,
+			setup: []Object{makeRefObj(k1, foo, bar), makeRefObj(k2, bar)},
+			prev:  makeRefObj(k1, foo, bar),
+			next:  makeRefObj(k1, bar, baz),
+			at:    map[string][][]byte{"foo": nil, "bar": {k1, k2}, "baz": {k1}},
+		},
+		"remove all": {
+			idx:   multi,
+			setup: []Object{makeRefObj(k1, foo, bar), makeRefObj(k2, bar)},
+			prev:  makeRefObj(k1, foo, bar),
+			at:    map[string][][]byte{"foo": nil, "bar": {k2}},
+		},
+		"remove not inserted fails": {
+			idx:     multi,
+			setup:   []Object{makeRefObj(k1, foo)},
+			prev:    makeRefObj(k1, foo, bar),
+			isError: true,
+		},
+		"unique conflict on any key": {
+			idx:     uniq,
+			setup:   []Object{makeRefObj(k1, foo, bar)},
+			next:    makeRefObj(k2, baz, bar),
+			isError: true,
+			// nothing written
+			at: map[string][][]byte{"baz": nil, "bar": {k1}},
+		},
+		"unique conflict on move": {
+			idx:     uniq,
+			setup:   []Object{makeRefObj(k1, foo), makeRefObj(k2, bar)},
+			prev:    makeRefObj(k1, foo),
+			next:    makeRefObj(k1, baz, bar),
+			isError: true,
+			at:      map[string][][]byte{"foo": {k1}, "bar": {k2}, "baz": nil},
+		},
+		"unique keeps own keys on move": {
+			idx:   uniq,
+			setup: []Object{makeRefObj(k1, foo, bar)},
+			prev:  makeRefObj(k1, foo, bar),
+			next:  makeRefObj(k1, bar, baz),
+			at:    map[string][][]byte{"foo": nil, "bar": {k1}, "baz": {k1}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := store.MemStore()
+			for _, obj := range tc.setup {
+				require.NoError(t, tc.idx.Update(db, nil, obj))
+			}
+			err := tc.idx.Update(db, tc.prev, tc.next)
+			if tc.isError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			for at, refs := range tc.at {
+				res, err := tc.idx.GetAt(db, []byte(at))
+				require.NoError(t, err)
+				assert.EqualValues(t, refs, res, at)
+			}
+		})
+	}
+
+	// GetLike finds everything sharing any key, once
+	db := store.MemStore()
+	require.NoError(t, multi.Update(db, nil, makeRefObj(k1, foo, bar)))
+	require.NoError(t, multi.Update(db, nil, makeRefObj(k2, bar, baz)))
+	res, err := multi.GetLike(db, makeRefObj(nil, foo, bar))
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{k1, k2}, res)
+	res, err = multi.GetLike(db, makeRefObj(nil, baz))
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{k2}, res)
+}
@@ -30,6 +30,17 @@ type Cloneable interface {
 	Clone() Object
 }
 
+// Rebaser is implemented by data that holds block heights.
+// A chain restarted from an export of its state begins again
+// at height 1, so Import moves these heights back.
+type Rebaser interface {
+	// Rebase moves all heights back by the export height.
+	// It returns false if the data only makes sense on the
+	// old chain, like a record of a past block, so it is
+	// not imported.
+	Rebase(exportHeight int64) bool
+}
+
 // CloneableData is an intelligent Value that can be embedded
 // in a simple object to handle much of the details.
 type CloneableData interface {
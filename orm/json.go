@@ -0,0 +1,258 @@
+package orm
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/confio/weave"
+)
+
+// JSONMarshaller turns an object of a bucket into
+// human-readable JSON
+type JSONMarshaller func(Object) ([]byte, error)
+
+// DefaultJSON marshals the value of the object like
+// encoding/json does, except that all []byte fields are
+// hex-encoded like weave.Address, instead of base64.
+//
+// Protobuf models only hold bytes for addresses, hashes,
+// and keys, which are all easier to read in hex.
+func DefaultJSON(obj Object) ([]byte, error) {
+	if obj == nil || obj.Value() == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(hexBytes(reflect.ValueOf(obj.Value())))
+}
+
+var (
+	bytesType     = reflect.TypeOf([]byte(nil))
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// hexBytes returns a copy of v that json marshals the same
+// way, but with all []byte replaced by weave.Address
+func hexBytes(v reflect.Value) interface{} {
+	// types that know how to marshal themselves, like weave.Address
+	if v.CanInterface() && v.Type().Implements(marshalerType) &&
+		!(v.Kind() == reflect.Ptr && v.IsNil()) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return hexBytes(v.Elem())
+	case reflect.Struct:
+		return hexStruct(v)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().ConvertibleTo(bytesType) {
+			return weave.Address(v.Bytes())
+		}
+		return hexList(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		res := make(map[string]interface{}, v.Len())
+		for _, k := range v.MapKeys() {
+			res[fmt.Sprint(hexBytes(k))] = hexBytes(v.MapIndex(k))
+		}
+		return res
+	}
+
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	// fields promoted from unexported embedded structs
+	// cannot be read with Interface
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32:
+		return float32(v.Float())
+	case reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Array:
+		return hexList(v)
+	}
+	return nil
+}
+
+// hexList applies hexBytes to every element of a slice or array
+func hexList(v reflect.Value) []interface{} {
+	res := make([]interface{}, v.Len())
+	for i := range res {
+		res[i] = hexBytes(v.Index(i))
+	}
+	return res
+}
+
+// jsonField is a field of a struct, as encoding/json sees it
+type jsonField struct {
+	name      string
+	index     []int
+	tagged    bool
+	omitEmpty bool
+}
+
+// hexStruct maps all exported fields to the names in
+// their json tags, honoring "-" and "omitempty".
+//
+// Like encoding/json, the fields of embedded structs without
+// a name in the tag are promoted, even if the struct type is
+// unexported. If several fields get the same name, the least
+// nested one wins, and if there are several of those, the only
+// tagged one. Otherwise none of them is marshalled.
+func hexStruct(v reflect.Value) map[string]interface{} {
+	var names []string
+	byName := make(map[string][]jsonField)
+	for _, f := range structFields(v.Type(), nil, map[reflect.Type]bool{}) {
+		if _, ok := byName[f.name]; !ok {
+			names = append(names, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	res := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		f, ok := dominantField(byName[name])
+		if !ok {
+			continue
+		}
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		res[name] = hexBytes(fv)
+	}
+	return res
+}
+
+// structFields lists all fields of t that encoding/json would
+// marshal, with embedded structs flattened. index is the path
+// to t, visited the embedded types on that path
+func structFields(t reflect.Type, index []int,
+	visited map[reflect.Type]bool) []jsonField {
+
+	var res []jsonField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		ft := field.Type
+		if field.Anonymous && ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		embedded := field.Anonymous && ft.Kind() == reflect.Struct
+		// unexported structs may still have exported fields
+		if field.PkgPath != "" && !embedded {
+			continue
+		}
+		if strings.HasPrefix(field.Name, "XXX_") {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.SplitN(tag, ",", 2)
+		idx := append(append([]int{}, index...), i)
+
+		if embedded && parts[0] == "" {
+			if !visited[ft] {
+				visited[ft] = true
+				res = append(res, structFields(ft, idx, visited)...)
+				delete(visited, ft)
+			}
+			continue
+		}
+		f := jsonField{
+			name:   field.Name,
+			index:  idx,
+			tagged: parts[0] != "",
+		}
+		if f.tagged {
+			f.name = parts[0]
+		}
+		if len(parts) == 2 {
+			f.omitEmpty = strings.Contains(parts[1], "omitempty")
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+// dominantField picks the field to marshal from all with
+// the same name, or returns false if it is ambiguous
+func dominantField(fields []jsonField) (jsonField, bool) {
+	depth := len(fields[0].index)
+	for _, f := range fields[1:] {
+		if len(f.index) < depth {
+			depth = len(f.index)
+		}
+	}
+	var found []jsonField
+	var tagged []jsonField
+	for _, f := range fields {
+		if len(f.index) != depth {
+			continue
+		}
+		found = append(found, f)
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	switch {
+	case len(found) == 1:
+		return found[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+	return jsonField{}, false
+}
+
+// fieldByIndex is v.FieldByIndex, but returns false instead
+// of panicking if an embedded pointer on the way is nil
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// isEmptyValue is what encoding/json omits with "omitempty"
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package orm
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+)
+
+func TestDefaultJSON(t *testing.T) {
+	marshal := func(v interface{}) string {
+		bz, err := json.Marshal(hexBytes(reflect.ValueOf(v)))
+		require.NoError(t, err)
+		return string(bz)
+	}
+
+	// bytes are hex, empty fields are omitted
+	q := &RangeQuery{Start: []byte{0xCA, 0xFE}, Limit: 5}
+	assert.Equal(t, `{"limit":5,"start":"CAFE"}`, marshal(q))
+	assert.Equal(t, `{}`, marshal(&RangeQuery{}))
+	assert.Equal(t, `null`, marshal((*RangeQuery)(nil)))
+
+	// also inside lists, and when they marshal themselves
+	refs := &MultiRef{Refs: [][]byte{{0x01}, {0xAB, 0x02}}}
+	assert.Equal(t, `{"refs":["01","AB02"]}`, marshal(refs))
+	addr := struct {
+		Owner weave.Address `json:"owner"`
+		Names []string      `json:"names,omitempty"`
+		Skip  int           `json:"-"`
+	}{Owner: weave.Address{0x12}, Skip: 7}
+	assert.Equal(t, `{"owner":"12"}`, marshal(addr))
+
+	bz, err := DefaultJSON(NewSimpleObj([]byte("foo"), NewCounter(42)))
+	require.NoError(t, err)
+	assert.Equal(t, `{"count":42}`, string(bz))
+}
+
+func TestBucketJSON(t *testing.T) {
+	bucket := NewBucket("refs", NewSimpleObj(nil, new(MultiRef))).
+		WithIndex("first", func(obj Object) ([]byte, error) {
+			return obj.Value().(*MultiRef).Refs[0], nil
+		}, false)
+	qr := weave.NewQueryRouter()
+	bucket.Register("", qr)
+	RegisterQuery(qr)
+
+	// bucket and index return the same objects, root has no type
+	enc := qr.JSONEncoder("/refs")
+	require.NotNil(t, enc)
+	require.NotNil(t, qr.JSONEncoder("/refs/first"))
+	assert.Nil(t, qr.JSONEncoder("/"))
+
+	value, err := (&MultiRef{Refs: [][]byte{{0xFF}}}).Marshal()
+	require.NoError(t, err)
+	bz, err := enc(value)
+	require.NoError(t, err)
+	assert.Equal(t, `{"refs":["FF"]}`, string(bz))
+	_, err = enc([]byte{0xFF, 0xFF})
+	assert.Error(t, err)
+
+	// custom marshaller
+	custom := NewBucket("custom", NewSimpleObj(nil, new(MultiRef))).
+		WithJSON(func(obj Object) ([]byte, error) {
+			return json.Marshal(len(obj.Value().(*MultiRef).Refs))
+		})
+	bz, err = custom.ToJSON(value)
+	require.NoError(t, err)
+	assert.Equal(t, `1`, string(bz))
+}
+
+// inner is embedded in the test structs below
+type inner struct {
+	Hash  []byte `json:"hash"`
+	Count int64  `json:"count,omitempty"`
+	Name  string
+}
+
+// Inner is an exported embedded struct
+type Inner struct {
+	Hash []byte `json:"hash"`
+	Size uint32 `json:"size"`
+}
+
+func TestEmbeddedJSON(t *testing.T) {
+	marshal := func(v interface{}) string {
+		bz, err := json.Marshal(hexBytes(reflect.ValueOf(v)))
+		require.NoError(t, err)
+		return string(bz)
+	}
+
+	// fields of embedded structs are promoted, also from
+	// unexported types and pointers
+	flat := struct {
+		inner
+		*Inner `json:",omitempty"`
+		Owner  weave.Address `json:"owner"`
+	}{
+		inner: inner{Hash: []byte{0xAB}, Count: 3, Name: "foo"},
+		Inner: &Inner{Hash: []byte{0xCD}, Size: 7},
+		Owner: weave.Address{0x12},
+	}
+	// hash is ambiguous, so neither is marshalled
+	assert.Equal(t, `{"Name":"foo","count":3,"owner":"12","size":7}`, marshal(flat))
+	// same as encoding/json, as there are no bytes left
+	std, err := json.Marshal(flat)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(std), marshal(flat))
+
+	// the outer field wins, nil pointers have no fields
+	outer := struct {
+		*Inner
+		inner
+		Hash string `json:"hash"`
+	}{inner: inner{Hash: []byte{0xAB}}, Hash: "mine"}
+	assert.Equal(t, `{"Name":"","hash":"mine"}`, marshal(outer))
+	std, err = json.Marshal(outer)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(std), marshal(outer))
+
+	// a name in the tag keeps the struct nested
+	named := struct {
+		inner `json:"inner"`
+	}{inner{Hash: []byte{0xAB}}}
+	assert.Equal(t, `{"inner":{"Name":"","hash":"AB"}}`, marshal(named))
+}
@@ -0,0 +1,59 @@
+package orm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// fieldLen is the size of the length prefix of every field
+const fieldLen = 2
+
+// CompositeKey joins the fields into one key, to index or
+// store an object by several values, like (author, height).
+//
+// Every field is prefixed with its length, so different fields
+// never run into each other, and the key of the first fields
+// is a prefix of the full key. That lets you use GetPrefix on
+// an index to find all objects with the same first fields.
+// The keys sort by field in the given order; use fixed-width
+// fields, like addresses or big-endian numbers, where the
+// order matters.
+//
+// Returns an error if a field is longer than 65535 bytes
+func CompositeKey(fields ...[]byte) ([]byte, error) {
+	size := 0
+	for _, f := range fields {
+		if len(f) > math.MaxUint16 {
+			return nil, ErrInvalidKey(fmt.Sprintf("Field of %d bytes", len(f)))
+		}
+		size += fieldLen + len(f)
+	}
+	res := make([]byte, 0, size)
+	for _, f := range fields {
+		var l [fieldLen]byte
+		binary.BigEndian.PutUint16(l[:], uint16(len(f)))
+		res = append(res, l[:]...)
+		res = append(res, f...)
+	}
+	return res, nil
+}
+
+// SplitCompositeKey returns the fields a CompositeKey was
+// made of, or an error if key is not a CompositeKey
+func SplitCompositeKey(key []byte) ([][]byte, error) {
+	var res [][]byte
+	for len(key) > 0 {
+		if len(key) < fieldLen {
+			return nil, ErrInvalidKey("Truncated length")
+		}
+		l := int(binary.BigEndian.Uint16(key))
+		key = key[fieldLen:]
+		if len(key) < l {
+			return nil, ErrInvalidKey("Truncated field")
+		}
+		res = append(res, key[:l])
+		key = key[l:]
+	}
+	return res, nil
+}
@@ -0,0 +1,58 @@
+package orm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeKey(t *testing.T) {
+	cases := map[string][][]byte{
+		"nothing":     nil,
+		"one":         {[]byte("foo")},
+		"many":        {[]byte("foo"), []byte("bar"), encodeSequence(17)},
+		"empty field": {[]byte("foo"), {}, []byte("bar")},
+	}
+	for name, fields := range cases {
+		t.Run(name, func(t *testing.T) {
+			key, err := CompositeKey(fields...)
+			require.NoError(t, err)
+			split, err := SplitCompositeKey(key)
+			require.NoError(t, err)
+			require.Equal(t, len(fields), len(split))
+			for i := range fields {
+				assert.Equal(t, string(fields[i]), string(split[i]))
+			}
+		})
+	}
+
+	// fields never run into each other
+	ab, err := CompositeKey([]byte("a"), []byte("bc"))
+	require.NoError(t, err)
+	abc, err := CompositeKey([]byte("ab"), []byte("c"))
+	require.NoError(t, err)
+	assert.NotEqual(t, ab, abc)
+
+	// the first fields are a prefix of the full key
+	prefix, err := CompositeKey([]byte("a"))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(ab, prefix))
+	assert.False(t, bytes.HasPrefix(abc, prefix))
+
+	// fixed-width fields sort by value
+	low, err := CompositeKey([]byte("a"), encodeSequence(9))
+	require.NoError(t, err)
+	high, err := CompositeKey([]byte("a"), encodeSequence(256))
+	require.NoError(t, err)
+	assert.Equal(t, -1, bytes.Compare(low, high))
+
+	// bad input
+	_, err = CompositeKey(make([]byte, 70000))
+	assert.True(t, IsInvalidKeyErr(err), "%+v", err)
+	_, err = SplitCompositeKey([]byte{0})
+	assert.True(t, IsInvalidKeyErr(err), "%+v", err)
+	_, err = SplitCompositeKey([]byte{0, 4, 'a', 'b'})
+	assert.True(t, IsInvalidKeyErr(err), "%+v", err)
+}
@@ -0,0 +1,98 @@
+package orm
+
+import (
+	"github.com/confio/weave"
+)
+
+// Rewriter upgrades one stored object, given its key and the
+// raw value. It returns the object to store in its place,
+// which may have another key, or nil to delete it.
+type Rewriter func(key, value []byte) (Object, error)
+
+// Rewrite passes every object in the bucket through fn, and
+// stores what it returns. The raw value is passed on, so fn can
+// parse data that the current model cannot read.
+// Indexes are rebuilt afterwards.
+//
+// This reads the whole bucket into memory, so it is meant for
+// migrations, not for use inside transactions.
+func (b Bucket) Rewrite(db weave.KVStore, fn Rewriter) error {
+	models := queryPrefix(db, b.prefix)
+	for _, m := range models {
+		db.Delete(m.Key)
+	}
+	for _, m := range models {
+		obj, err := fn(m.Key[len(b.prefix):], m.Value)
+		if err != nil {
+			return err
+		}
+		if obj == nil {
+			continue
+		}
+		err = obj.Validate()
+		if err != nil {
+			return err
+		}
+		bz, err := obj.Value().Marshal()
+		if err != nil {
+			return err
+		}
+		db.Set(b.DBKey(obj.Key()), bz)
+	}
+	return b.RebuildIndexes(db)
+}
+
+// RebuildIndexes drops all secondary indexes and adds every
+// object again, like after adding an index to a live bucket.
+func (b Bucket) RebuildIndexes(db weave.KVStore) error {
+	if len(b.indexes) == 0 {
+		return nil
+	}
+	objs, err := b.All(db)
+	if err != nil {
+		return err
+	}
+	for _, name := range b.indexNames() {
+		idx := b.indexes[name]
+		idx.drop(db)
+		for _, obj := range objs {
+			err := idx.Update(db, nil, obj)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MoveTo renames this bucket to the other one. It moves all
+// data and sequences, drops the indexes of this bucket and
+// builds the indexes of the other one.
+//
+// This bucket must be set up with its old name and indexes,
+// the indexers are not called.
+func (b Bucket) MoveTo(db weave.KVStore, to Bucket) error {
+	movePrefix(db, b.prefix, to.prefix)
+	movePrefix(db, seqBucketPrefix(b.name), seqBucketPrefix(to.name))
+	for _, name := range b.indexNames() {
+		b.indexes[name].drop(db)
+	}
+	return to.RebuildIndexes(db)
+}
+
+// drop deletes all entries of the index
+func (i Index) drop(db weave.KVStore) {
+	for _, m := range queryPrefix(db, i.id) {
+		db.Delete(m.Key)
+	}
+}
+
+// movePrefix moves every key from one prefix to the other
+func movePrefix(db weave.KVStore, from, to []byte) {
+	for _, m := range queryPrefix(db, from) {
+		key := make([]byte, 0, len(to)+len(m.Key)-len(from))
+		key = append(append(key, to...), m.Key[len(from):]...)
+		db.Delete(m.Key)
+		db.Set(key, m.Value)
+	}
+}
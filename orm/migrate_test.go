@@ -0,0 +1,117 @@
+package orm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/store"
+)
+
+func TestRebuildIndexes(t *testing.T) {
+	db := store.MemStore()
+	plain := NewBucket("special", NewSimpleObj(nil, new(Counter)))
+	oa := NewSimpleObj([]byte("a"), NewCounter(5))
+	ob := NewSimpleObj([]byte("b"), NewCounter(256+5))
+	require.NoError(t, plain.Save(db, oa))
+	require.NoError(t, plain.Save(db, ob))
+
+	// adding an index leaves the old data out
+	indexed := plain.WithIndex("uniq", count, true).
+		WithIndex("mini", countByte, false)
+	err := indexed.CheckIndexes(db)
+	assert.True(t, IsInconsistentIndexErr(err), "%+v", err)
+
+	require.NoError(t, indexed.RebuildIndexes(db))
+	assert.NoError(t, indexed.CheckIndexes(db))
+	found, err := indexed.GetIndexed(db, "mini", []byte{5})
+	require.NoError(t, err)
+	assert.Equal(t, []Object{oa, ob}, found)
+
+	// and it also drops stale entries
+	db.Set(indexed.DBKey(oa.Key()), []byte{})
+	require.NoError(t, indexed.RebuildIndexes(db))
+	assert.NoError(t, indexed.CheckIndexes(db))
+}
+
+func TestRewrite(t *testing.T) {
+	db := store.MemStore()
+	bucket := NewBucket("special", NewSimpleObj(nil, new(Counter))).
+		WithIndex("mini", countByte, false)
+	for i, key := range []string{"a", "b", "c"} {
+		obj := NewSimpleObj([]byte(key), NewCounter(int64(i+1)))
+		require.NoError(t, bucket.Save(db, obj))
+	}
+
+	// double all counters, drop b, and rename c to d
+	err := bucket.Rewrite(db, func(key, value []byte) (Object, error) {
+		obj, err := bucket.Parse(key, value)
+		if err != nil {
+			return nil, err
+		}
+		cntr := obj.Value().(*Counter)
+		cntr.Count *= 2
+		switch string(key) {
+		case "b":
+			return nil, nil
+		case "c":
+			obj.SetKey([]byte("d"))
+		}
+		return obj, nil
+	})
+	require.NoError(t, err)
+
+	objs, err := bucket.All(db)
+	require.NoError(t, err)
+	assert.Equal(t, []Object{
+		NewSimpleObj([]byte("a"), NewCounter(2)),
+		NewSimpleObj([]byte("d"), NewCounter(6)),
+	}, objs)
+	assert.NoError(t, bucket.CheckIndexes(db))
+
+	// invalid data is rejected
+	err = bucket.Rewrite(db, func(key, value []byte) (Object, error) {
+		return NewSimpleObj(key, new(MultiRef)), nil
+	})
+	assert.Error(t, err)
+}
+
+func TestMoveTo(t *testing.T) {
+	db := store.MemStore()
+	old := NewBucket("old", NewSimpleObj(nil, new(Counter))).
+		WithIndex("mini", countByte, false)
+	seq := old.Sequence(SeqID)
+	for _, n := range []int64{5, 7} {
+		obj := NewSimpleObj(seq.NextVal(db), NewCounter(n))
+		require.NoError(t, old.Save(db, obj))
+	}
+	other := NewBucket("oldie", NewSimpleObj(nil, new(Counter)))
+	require.NoError(t, other.Save(db, NewSimpleObj([]byte("x"), NewCounter(1))))
+
+	renamed := NewBucket("renamed", NewSimpleObj(nil, new(Counter))).
+		WithIndex("uniq", count, true)
+	require.NoError(t, old.MoveTo(db, renamed))
+
+	// all data and the sequence moved
+	objs, err := old.All(db)
+	require.NoError(t, err)
+	assert.Empty(t, objs)
+	objs, err = renamed.All(db)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(objs))
+	nextSeq := renamed.Sequence(SeqID)
+	assert.Equal(t, int64(3), nextSeq.NextInt(db))
+
+	// with the new indexes only
+	assert.NoError(t, renamed.CheckIndexes(db))
+	found, err := renamed.GetIndexed(db, "uniq", encodeSequence(7))
+	require.NoError(t, err)
+	assert.Equal(t, objs[1:], found)
+	assert.Empty(t, queryPrefix(db, old.indexes["mini"].id))
+
+	// other buckets are not touched
+	objs, err = other.All(db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(objs))
+}
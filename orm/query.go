@@ -1,6 +1,11 @@
 package orm
 
-import "github.com/confio/weave"
+import (
+	"bytes"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+)
 
 // RegisterQuery will register a root query (literal keys)
 // under "/"
@@ -10,12 +15,16 @@ func RegisterQuery(qr weave.QueryRouter) {
 }
 
 // consumeIterator will read all remaining data into an
-// array and close the iterator
-func consumeIterator(itr weave.Iterator) []weave.Model {
+// array and close the iterator.
+// If limit > 0, it returns at most limit models.
+func consumeIterator(itr weave.Iterator, limit int64) []weave.Model {
 	defer itr.Close()
 
 	var res []weave.Model
 	for ; itr.Valid(); itr.Next() {
+		if limit > 0 && int64(len(res)) >= limit {
+			break
+		}
 		mod := weave.Model{
 			Key:   itr.Key(),
 			Value: itr.Value(),
@@ -25,6 +34,28 @@ func consumeIterator(itr weave.Iterator) []weave.Model {
 	return res
 }
 
+// consumePage reads up to limit models from the iterator
+// and closes it. It returns a cursor pointing to the next
+// model, or nil if there are no more.
+func consumePage(itr weave.Iterator, limit int) ([]weave.Model, []byte, error) {
+	defer itr.Close()
+
+	var res []weave.Model
+	for ; itr.Valid(); itr.Next() {
+		if len(res) == limit {
+			next := Cursor{Key: itr.Key()}
+			bz, err := next.Marshal()
+			return res, bz, err
+		}
+		mod := weave.Model{
+			Key:   itr.Key(),
+			Value: itr.Value(),
+		}
+		res = append(res, mod)
+	}
+	return res, nil, nil
+}
+
 // prefixRange turns a prefix into (start, end) to create
 // and iterator
 func prefixRange(prefix []byte) ([]byte, []byte) {
@@ -54,5 +85,107 @@ func prefixRange(prefix []byte) ([]byte, []byte) {
 
 // queryPrefix returns a prefix query as Models
 func queryPrefix(db weave.ReadOnlyKVStore, prefix []byte) []weave.Model {
-	return consumeIterator(db.Iterator(prefixRange(prefix)))
+	return consumeIterator(db.Iterator(prefixRange(prefix)), 0)
+}
+
+// queryPrefixPage returns one page of a prefix query as Models,
+// along with the cursor for the next page
+func queryPrefixPage(db weave.ReadOnlyKVStore, prefix []byte,
+	page weave.Page) ([]weave.Model, []byte, error) {
+
+	start, end := prefixRange(prefix)
+	cursor, err := parseCursor(page.Cursor, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cursor != nil {
+		start = cursor.Key
+	}
+	return consumePage(db.Iterator(start, end), page.Limit)
+}
+
+// parseCursor loads the cursor of a paged query, and makes
+// sure it lies inside the queried domain.
+// Returns nil if no cursor was given.
+func parseCursor(data []byte, start, end []byte) (*Cursor, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var cursor Cursor
+	err := cursor.Unmarshal(data)
+	if err != nil {
+		return nil, errors.ErrInvalidPage(err.Error())
+	}
+	if bytes.Compare(cursor.Key, start) < 0 ||
+		(end != nil && bytes.Compare(cursor.Key, end) >= 0) ||
+		cursor.Offset < 0 {
+		return nil, errCursorOutOfRange()
+	}
+	return &cursor, nil
+}
+
+func errCursorOutOfRange() error {
+	return errors.ErrInvalidPage("cursor out of range")
+}
+
+// checkPageMod ensures we only page over prefix queries
+func checkPageMod(mod string) error {
+	if mod != weave.PrefixQueryMod {
+		return errors.ErrInvalidPage("cannot page query: " + mod)
+	}
+	return nil
+}
+
+// ParseRangeQuery parses the data passed to a "?range" query
+func ParseRangeQuery(data []byte) (*RangeQuery, error) {
+	var q RangeQuery
+	err := q.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit < 0 {
+		return nil, ErrInvalidRange("negative limit")
+	}
+	if len(q.Start) > 0 && len(q.End) > 0 &&
+		bytes.Compare(q.Start, q.End) > 0 {
+		return nil, ErrInvalidRange("start after end")
+	}
+	return &q, nil
+}
+
+// DBRange returns the (start, end) db keys to iterate over
+// this range, inside the domain of the given prefix
+func (q RangeQuery) DBRange(prefix []byte) ([]byte, []byte) {
+	start, end := prefixRange(prefix)
+	if len(q.Start) > 0 {
+		start = joinKey(prefix, q.Start)
+	}
+	if len(q.End) > 0 {
+		end = joinKey(prefix, q.End)
+	}
+	return start, end
+}
+
+// Iterator returns an iterator over the range in the
+// requested direction
+func (q RangeQuery) Iterator(db weave.ReadOnlyKVStore, prefix []byte) weave.Iterator {
+	start, end := q.DBRange(prefix)
+	if q.Reverse {
+		return db.ReverseIterator(start, end)
+	}
+	return db.Iterator(start, end)
+}
+
+// queryRange returns a range query as Models
+func queryRange(db weave.ReadOnlyKVStore, prefix []byte, q *RangeQuery) []weave.Model {
+	return consumeIterator(q.Iterator(db, prefix), q.Limit)
+}
+
+// joinKey returns a new slice with prefix followed by key
+func joinKey(prefix, key []byte) []byte {
+	l := len(prefix)
+	out := make([]byte, l+len(key))
+	copy(out, prefix)
+	copy(out[l:], key)
+	return out
 }
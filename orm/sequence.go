@@ -25,6 +25,13 @@ func NewSequence(bucket, name string) Sequence {
 	}
 }
 
+// seqBucketPrefix is the prefix of the keys of all
+// sequences of a bucket
+func seqBucketPrefix(bucket string) []byte {
+	id := NewSequence(bucket, "").id
+	return append(append([]byte{}, seqPrefix...), id...)
+}
+
 // NextVal increments the sequence and returns next val as 8 bytes
 func (s *Sequence) NextVal(db weave.KVStore) []byte {
 	_, bz := s.increment(db)
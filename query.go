@@ -1,7 +1,12 @@
 package weave
 
 import (
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/confio/weave/errors"
 )
 
 const (
@@ -9,8 +14,8 @@ const (
 	KeyQueryMod = ""
 	// PrefixQueryMod means to query for anything with this prefix
 	PrefixQueryMod = "prefix"
-	// RangeQueryMod means to expect complex range query
-	// TODO: implement
+	// RangeQueryMod means to expect complex range query,
+	// data is a serialized orm.RangeQuery
 	RangeQueryMod = "range"
 )
 
@@ -33,6 +38,116 @@ type QueryHandler interface {
 	Query(db ReadOnlyKVStore, mod string, data []byte) ([]Model, error)
 }
 
+// PagedQueryHandler is a QueryHandler that can also split
+// large results into pages
+type PagedQueryHandler interface {
+	QueryHandler
+	// QueryPage returns up to page.Limit models starting
+	// at page.Cursor, along with a cursor for the next page.
+	// next is nil if there are no more results.
+	QueryPage(db ReadOnlyKVStore, mod string, data []byte,
+		page Page) (models []Model, next []byte, err error)
+}
+
+// Page selects one part of a large result set.
+//
+// It is appended to the query modifier in the form of url
+// parameters, like "prefix&limit=50&cursor=0A1B" (hex cursor).
+type Page struct {
+	// Limit is the maximum number of results to return
+	Limit int
+	// Cursor is the opaque next value returned from the
+	// previous page, or empty for the first one
+	Cursor []byte
+}
+
+const (
+	// DefaultPageLimit is the page size of prefix queries
+	// that don't ask for a limit
+	DefaultPageLimit = 100
+	// MaxPageLimit is the largest page a client can request
+	MaxPageLimit = 1000
+)
+
+const (
+	pageLimit  = "limit"
+	pageCursor = "cursor"
+	// jsonOption asks for the values as JSON, like
+	// "prefix&json" or just "json"
+	jsonOption = "json"
+)
+
+// ParsePage splits the pagination options from the query
+// modifier. It returns the modifier for the QueryHandler
+// and the requested page, or nil if there are no options.
+//
+// Prefix queries are always paged, with DefaultPageLimit
+// if no limit is given, and no page may be larger
+// than MaxPageLimit.
+func ParsePage(mod string) (string, *Page, error) {
+	parts := strings.Split(mod, "&")
+	if len(parts) == 1 {
+		if mod == PrefixQueryMod {
+			return mod, &Page{Limit: DefaultPageLimit}, nil
+		}
+		return mod, nil, nil
+	}
+
+	page := Page{Limit: DefaultPageLimit}
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return "", nil, errors.ErrInvalidPage(part)
+		}
+		var err error
+		switch kv[0] {
+		case pageLimit:
+			page.Limit, err = strconv.Atoi(kv[1])
+		case pageCursor:
+			page.Cursor, err = hex.DecodeString(kv[1])
+		default:
+			return "", nil, errors.ErrInvalidPage(part)
+		}
+		if err != nil {
+			return "", nil, errors.ErrInvalidPage(part)
+		}
+	}
+	if page.Limit <= 0 {
+		return "", nil, errors.ErrInvalidPage("limit must be positive")
+	}
+	if page.Limit > MaxPageLimit {
+		msg := fmt.Sprintf("limit must be at most %d", MaxPageLimit)
+		return "", nil, errors.ErrInvalidPage(msg)
+	}
+	return parts[0], &page, nil
+}
+
+// ParseJSON splits the json option from the query modifier.
+// It returns the modifier without it, and whether the values
+// should be returned as JSON.
+func ParseJSON(mod string) (string, bool) {
+	parts := strings.Split(mod, "&")
+	rest := make([]string, 0, len(parts))
+	var asJSON bool
+	for i, part := range parts {
+		switch {
+		case part != jsonOption:
+			rest = append(rest, part)
+		case i == 0:
+			// "json" alone is a key query
+			rest = append(rest, KeyQueryMod)
+			asJSON = true
+		default:
+			asJSON = true
+		}
+	}
+	return strings.Join(rest, "&"), asJSON
+}
+
+// JSONEncoder turns the raw value of a query result into
+// human-readable JSON, for scripts and dashboards
+type JSONEncoder func(value []byte) ([]byte, error)
+
 // QueryRegister is a function that adds some handlers
 // to this router
 type QueryRegister func(QueryRouter)
@@ -43,13 +158,15 @@ type QueryRegister func(QueryRouter)
 //
 // Minimal interface modeled after net/http.ServeMux
 type QueryRouter struct {
-	routes map[string]QueryHandler
+	routes   map[string]QueryHandler
+	encoders map[string]JSONEncoder
 }
 
 // NewQueryRouter initializes a QueryRouter with no routes
 func NewQueryRouter() QueryRouter {
 	return QueryRouter{
-		routes: make(map[string]QueryHandler, 10),
+		routes:   make(map[string]QueryHandler, 10),
+		encoders: make(map[string]JSONEncoder, 10),
 	}
 }
 
@@ -75,3 +192,19 @@ func (r QueryRouter) Register(path string, h QueryHandler) {
 func (r QueryRouter) Handler(path string) QueryHandler {
 	return r.routes[path]
 }
+
+// RegisterJSON adds a JSONEncoder for the values returned
+// from the given path, so it can be queried with "?json".
+// panics if another JSONEncoder was already registered
+func (r QueryRouter) RegisterJSON(path string, enc JSONEncoder) {
+	if _, ok := r.encoders[path]; ok {
+		panic(fmt.Sprintf("Re-registering json: %s", path))
+	}
+	r.encoders[path] = enc
+}
+
+// JSONEncoder returns the registered JSONEncoder for this path,
+// or nil if the values cannot be returned as JSON
+func (r QueryRouter) JSONEncoder(path string) JSONEncoder {
+	return r.encoders[path]
+}
@@ -0,0 +1,82 @@
+package weave
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/errors"
+)
+
+func TestParsePage(t *testing.T) {
+	cases := []struct {
+		mod     string
+		base    string
+		page    *Page
+		isError bool
+	}{
+		{"", "", nil, false},
+		// prefix queries get a default page
+		{"prefix", "prefix", &Page{Limit: DefaultPageLimit}, false},
+		{"range", "range", nil, false},
+		{"prefix&limit=20", "prefix", &Page{Limit: 20}, false},
+		{"prefix&cursor=0A1b&limit=5", "prefix",
+			&Page{Limit: 5, Cursor: []byte{0x0A, 0x1B}}, false},
+		{"&limit=3", "", &Page{Limit: 3}, false},
+		// limit defaults, but must be positive if given
+		{"prefix&cursor=0A1b", "prefix",
+			&Page{Limit: DefaultPageLimit, Cursor: []byte{0x0A, 0x1B}}, false},
+		{"prefix&limit=0", "", nil, true},
+		{"prefix&limit=-4", "", nil, true},
+		// limit is bounded
+		{fmt.Sprintf("prefix&limit=%d", MaxPageLimit), "prefix",
+			&Page{Limit: MaxPageLimit}, false},
+		{fmt.Sprintf("prefix&limit=%d", MaxPageLimit+1), "", nil, true},
+		// bad format
+		{"prefix&limit=abc", "", nil, true},
+		{"prefix&limit", "", nil, true},
+		{"prefix&cursor=0A1&limit=5", "", nil, true},
+		{"prefix&size=5", "", nil, true},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			base, page, err := ParsePage(tc.mod)
+			if tc.isError {
+				require.Error(t, err)
+				assert.True(t, errors.IsInvalidPageErr(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.base, base)
+			assert.Equal(t, tc.page, page)
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	cases := []struct {
+		mod    string
+		base   string
+		asJSON bool
+	}{
+		{"", "", false},
+		{"prefix", "prefix", false},
+		{"prefix&limit=20", "prefix&limit=20", false},
+		{"json", "", true},
+		{"prefix&json", "prefix", true},
+		{"prefix&json&limit=5", "prefix&limit=5", true},
+		{"json&limit=5", "&limit=5", true},
+		{"range&jsonp", "range&jsonp", false},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			base, asJSON := ParseJSON(tc.mod)
+			assert.Equal(t, tc.base, base)
+			assert.Equal(t, tc.asJSON, asJSON)
+		})
+	}
+}
@@ -141,16 +141,14 @@ type CommitKVStore interface {
 	// returns nil iff key doesn't exist. Panics on nil key.
 	Get(key []byte) []byte
 
-	// TODO: Get with proof, also historical queries
-	// GetVersionedWithProof(key []byte, version int64) (value []byte)
+	// GetVersion returns a read-only view of the state as it was
+	// committed at the given version. Only versions that are still
+	// kept in the history are available, all others return an error.
+	GetVersion(version int64) (ReadOnlyKVStore, error)
 
-	// func (b *Bonsai) GetWithProof(key []byte) ([]byte, iavl.KeyProof, error) {
-	//   return b.Tree.GetWithProof(key)
-	// }
-
-	// func (b *Bonsai) GetVersionedWithProof(key []byte, version int64) ([]byte, iavl.KeyProof, error) {
-	//   return b.Tree.GetVersionedWithProof(key, uint64(version))
-	// }
+	// GetVersionWithProof is like GetVersion, but the returned
+	// view also records merkle proofs for all data read from it.
+	GetVersionWithProof(version int64) (ProvableKVStore, error)
 
 	// Get a CacheWrap to perform actions
 	// TODO: add Batch to atomic writes and efficiency
@@ -176,6 +174,17 @@ type CommitKVStore interface {
 	// LoadVersion(ver int64) error
 }
 
+// ProvableKVStore is a read-only view of some committed state,
+// which records a merkle proof for every read made on it.
+type ProvableKVStore interface {
+	ReadOnlyKVStore
+
+	// Proof returns a serialized proof of all reads made so far,
+	// which can be verified against the root hash of this version.
+	// The format depends on the underlying merkle tree.
+	Proof() ([]byte, error)
+}
+
 // CommitID contains the tree version number and its merkle root.
 type CommitID struct {
 	Version int64
@@ -362,9 +362,12 @@ func (i *itemIter) Value() (value []byte) {
 	}
 }
 
-// Close releases the Iterator.
+// Close releases the Iterator, along with the parent.
 func (i *itemIter) Close() {
 	i.data = nil
+	if i.parent != nil {
+		i.parent.Close()
+	}
 }
 
 // skipAllDeleted loops and skips any number of deleted items
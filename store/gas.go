@@ -0,0 +1,220 @@
+package store
+
+import "github.com/confio/weave"
+
+// GasConfig defines the gas charged for every store operation
+type GasConfig struct {
+	HasCost          int64
+	DeleteCost       int64
+	ReadCostFlat     int64
+	ReadCostPerByte  int64
+	WriteCostFlat    int64
+	WriteCostPerByte int64
+	IterNextCostFlat int64
+}
+
+// DefaultGasConfig returns the costs we use if nothing
+// else is configured. Writes are much more expensive than
+// reads, as they must be persisted and hashed.
+func DefaultGasConfig() GasConfig {
+	return GasConfig{
+		HasCost:          10,
+		DeleteCost:       10,
+		ReadCostFlat:     10,
+		ReadCostPerByte:  1,
+		WriteCostFlat:    10,
+		WriteCostPerByte: 10,
+		IterNextCostFlat: 30,
+	}
+}
+
+// NewGasStore wraps a store and charges the meter for
+// all data read from or written to it, using the cached
+// alternative if possible
+//
+// Like NewRecordingStore, we need to expose CacheWrap
+// so downstream components (like Savepoint) can use it.
+func NewGasStore(db KVStore, meter weave.GasMeter, config GasConfig) KVStore {
+	gas := &gasStore{
+		KVStore: db,
+		meter:   meter,
+		config:  config,
+	}
+	if cached, ok := db.(CacheableKVStore); ok {
+		return cacheableGasStore{
+			gasStore: gas,
+			parent:   cached,
+		}
+	}
+	return gas
+}
+
+//------- non-cached gas store
+
+// gasStore wraps a normal KVStore and charges for every operation
+type gasStore struct {
+	KVStore
+	meter  weave.GasMeter
+	config GasConfig
+}
+
+var _ KVStore = (*gasStore)(nil)
+
+// Get charges for the lookup and all bytes returned
+func (g *gasStore) Get(key []byte) []byte {
+	g.meter.ConsumeGas(g.config.ReadCostFlat, "ReadFlat")
+	value := g.KVStore.Get(key)
+	g.meter.ConsumeGas(g.config.ReadCostPerByte*int64(len(value)), "ReadPerByte")
+	return value
+}
+
+// Has charges a flat fee
+func (g *gasStore) Has(key []byte) bool {
+	g.meter.ConsumeGas(g.config.HasCost, "Has")
+	return g.KVStore.Has(key)
+}
+
+// Set charges for all bytes written
+func (g *gasStore) Set(key, value []byte) {
+	chargeWrite(g.meter, g.config, key, value)
+	g.KVStore.Set(key, value)
+}
+
+// Delete charges a flat fee
+func (g *gasStore) Delete(key []byte) {
+	g.meter.ConsumeGas(g.config.DeleteCost, "Delete")
+	g.KVStore.Delete(key)
+}
+
+// Iterator charges for every step
+func (g *gasStore) Iterator(start, end []byte) Iterator {
+	return newGasIterator(g.KVStore.Iterator(start, end), g.meter, g.config)
+}
+
+// ReverseIterator charges for every step
+func (g *gasStore) ReverseIterator(start, end []byte) Iterator {
+	return newGasIterator(g.KVStore.ReverseIterator(start, end), g.meter, g.config)
+}
+
+// NewBatch makes sure all writes are charged
+func (g *gasStore) NewBatch() Batch {
+	return &gasBatch{
+		b:      g.KVStore.NewBatch(),
+		meter:  g.meter,
+		config: g.config,
+	}
+}
+
+//------- cached gas store
+
+// cacheableGasStore wraps a CacheableKVStore and charges for
+// every operation
+type cacheableGasStore struct {
+	*gasStore
+	parent CacheableKVStore
+}
+
+var _ CacheableKVStore = cacheableGasStore{}
+
+// CacheWrap meters the CacheWrap of the parent store.
+//
+// Operations are charged when they are performed on the cache,
+// so writes are paid for even if the cache is discarded later.
+func (g cacheableGasStore) CacheWrap() KVCacheWrap {
+	cache := g.parent.CacheWrap()
+	return gasCacheWrap{
+		cacheableGasStore: cacheableGasStore{
+			gasStore: &gasStore{
+				KVStore: cache,
+				meter:   g.meter,
+				config:  g.config,
+			},
+			parent: cache,
+		},
+		cache: cache,
+	}
+}
+
+// gasCacheWrap is a metered KVCacheWrap
+type gasCacheWrap struct {
+	cacheableGasStore
+	cache KVCacheWrap
+}
+
+var _ KVCacheWrap = gasCacheWrap{}
+
+// Write syncs with the underlying store.
+func (g gasCacheWrap) Write() {
+	g.cache.Write()
+}
+
+// Discard invalidates this CacheWrap and releases all data
+func (g gasCacheWrap) Discard() {
+	g.cache.Discard()
+}
+
+//----- batch and iterator charging to the same meter
+
+type gasBatch struct {
+	b      Batch
+	meter  weave.GasMeter
+	config GasConfig
+}
+
+var _ Batch = (*gasBatch)(nil)
+
+func (g *gasBatch) Set(key, value []byte) {
+	chargeWrite(g.meter, g.config, key, value)
+	g.b.Set(key, value)
+}
+
+func (g *gasBatch) Delete(key []byte) {
+	g.meter.ConsumeGas(g.config.DeleteCost, "Delete")
+	g.b.Delete(key)
+}
+
+func (g *gasBatch) Write() {
+	g.b.Write()
+}
+
+// gasIterator charges for every item it visits,
+// including the bytes of key and value
+type gasIterator struct {
+	Iterator
+	meter  weave.GasMeter
+	config GasConfig
+}
+
+var _ Iterator = (*gasIterator)(nil)
+
+func newGasIterator(parent Iterator, meter weave.GasMeter, config GasConfig) *gasIterator {
+	g := &gasIterator{
+		Iterator: parent,
+		meter:    meter,
+		config:   config,
+	}
+	g.charge()
+	return g
+}
+
+// Next moves to the next item, and charges for it
+func (g *gasIterator) Next() {
+	g.Iterator.Next()
+	g.charge()
+}
+
+// charge consumes gas for the current item if there is one
+func (g *gasIterator) charge() {
+	if !g.Iterator.Valid() {
+		return
+	}
+	g.meter.ConsumeGas(g.config.IterNextCostFlat, "IterNextFlat")
+	size := len(g.Iterator.Key()) + len(g.Iterator.Value())
+	g.meter.ConsumeGas(g.config.ReadCostPerByte*int64(size), "ReadPerByte")
+}
+
+func chargeWrite(meter weave.GasMeter, config GasConfig, key, value []byte) {
+	meter.ConsumeGas(config.WriteCostFlat, "WriteFlat")
+	size := len(key) + len(value)
+	meter.ConsumeGas(config.WriteCostPerByte*int64(size), "WritePerByte")
+}
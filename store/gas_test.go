@@ -0,0 +1,104 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+)
+
+func TestGasStore(t *testing.T) {
+	config := GasConfig{
+		HasCost:          1,
+		DeleteCost:       2,
+		ReadCostFlat:     3,
+		ReadCostPerByte:  1,
+		WriteCostFlat:    5,
+		WriteCostPerByte: 2,
+		IterNextCostFlat: 7,
+	}
+	k1, v1 := []byte("foo"), []byte("bar1")
+	k2, v2 := []byte("food"), []byte("yum")
+
+	cases := map[string]struct {
+		op   func(KVStore)
+		cost int64
+	}{
+		"set": {
+			op:   func(db KVStore) { db.Set(k1, v1) },
+			cost: 5 + 2*7,
+		},
+		"get": {
+			op:   func(db KVStore) { db.Get(k2) },
+			cost: 3 + 3,
+		},
+		"get missing": {
+			op:   func(db KVStore) { db.Get(k1) },
+			cost: 3,
+		},
+		"has and delete": {
+			op: func(db KVStore) {
+				db.Has(k2)
+				db.Delete(k2)
+			},
+			cost: 1 + 2,
+		},
+		"batch": {
+			op: func(db KVStore) {
+				b := db.NewBatch()
+				b.Set(k1, v1)
+				b.Delete(k2)
+				b.Write()
+			},
+			cost: 5 + 2*7 + 2,
+		},
+		"iterate": {
+			op: func(db KVStore) {
+				db.Set(k1, v1)
+				itr := db.ReverseIterator(nil, nil)
+				for ; itr.Valid(); itr.Next() {
+				}
+				itr.Close()
+			},
+			cost: 5 + 2*7 + 7 + 7 + 7 + 7,
+		},
+		"discarded cache": {
+			op: func(db KVStore) {
+				cache := db.(CacheableKVStore).CacheWrap()
+				cache.Set(k1, v1)
+				cache.Get(k2)
+				cache.Discard()
+			},
+			cost: 5 + 2*7 + 3 + 3,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			base := MemStore()
+			base.Set(k2, v2)
+			meter := weave.NewInfiniteGasMeter()
+			tc.op(NewGasStore(base, meter, config))
+			assert.Equal(t, tc.cost, meter.GasConsumed())
+		})
+	}
+}
+
+func TestGasStoreLimit(t *testing.T) {
+	meter := weave.NewGasMeter(100)
+	db := NewGasStore(MemStore(), meter, DefaultGasConfig())
+	db.Set([]byte("a"), []byte("b"))
+	assert.Equal(t, int64(30), meter.GasConsumed())
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if assert.True(t, ok, "%v", r) {
+			assert.True(t, errors.IsOutOfGasErr(err))
+		}
+	}()
+	db.Set([]byte("c"), make([]byte, 20))
+	t.Fatal("should run out of gas")
+}
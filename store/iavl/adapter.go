@@ -1,13 +1,17 @@
 package iavl
 
 import (
+	"bytes"
+
 	"github.com/tendermint/iavl"
 	dbm "github.com/tendermint/tmlibs/db"
 
+	"github.com/confio/weave/errors"
 	"github.com/confio/weave/store"
 )
 
-// TODO: make these configurable?
+// Defaults for the iavl store, use WithPruning to
+// keep a different history
 const (
 	DefaultCacheSize int   = 10000
 	DefaultHistory   int64 = 20
@@ -15,8 +19,8 @@ const (
 
 // CommitStore manages a iavl committed state
 type CommitStore struct {
-	tree       *iavl.VersionedTree
-	numHistory int64
+	tree    *iavl.VersionedTree
+	pruning Pruning
 }
 
 var _ store.CommitKVStore = CommitStore{}
@@ -30,8 +34,7 @@ func NewCommitStore(path, name string) CommitStore {
 		panic(err)
 	}
 
-	tree := iavl.NewVersionedTree(db, DefaultCacheSize)
-	commit := CommitStore{tree, DefaultHistory}
+	commit := newCommitStore(db)
 	commit.LoadLatestVersion()
 	return commit
 }
@@ -39,8 +42,21 @@ func NewCommitStore(path, name string) CommitStore {
 // MockCommitStore creates a new in-memory store for testing
 func MockCommitStore() CommitStore {
 	var db dbm.DB = dbm.NewMemDB()
-	tree := iavl.NewVersionedTree(db, DefaultCacheSize)
-	return CommitStore{tree, DefaultHistory}
+	return newCommitStore(db)
+}
+
+func newCommitStore(db dbm.DB) CommitStore {
+	return CommitStore{
+		tree:    iavl.NewVersionedTree(db, DefaultCacheSize),
+		pruning: DefaultPruning,
+	}
+}
+
+// WithPruning returns a CommitStore that prunes old versions
+// with the given strategy
+func (s CommitStore) WithPruning(pruning Pruning) CommitStore {
+	s.pruning = pruning
+	return s
 }
 
 // Get returns the value at last committed state
@@ -59,8 +75,7 @@ func (s CommitStore) Commit() store.CommitID {
 	}
 
 	// Potentially release an old version of history
-	if s.numHistory > 0 && (s.numHistory < version) {
-		toRelease := version - s.numHistory
+	if toRelease, ok := s.pruning.toRelease(version); ok {
 		s.tree.DeleteVersion(toRelease)
 	}
 
@@ -86,6 +101,28 @@ func (s CommitStore) LatestVersion() store.CommitID {
 	}
 }
 
+// GetVersion returns a read-only view of the tree as it was
+// committed at the given version.
+//
+// Returns an error if the version was never committed, or
+// was already pruned from the history.
+func (s CommitStore) GetVersion(version int64) (store.ReadOnlyKVStore, error) {
+	if !s.tree.VersionExists(version) {
+		return nil, errors.ErrUnknownHeight(version)
+	}
+	return versionReader{s.tree, version}, nil
+}
+
+// GetVersionWithProof returns a read-only view of the tree at
+// the given version, just like GetVersion, which records
+// proofs for every read.
+func (s CommitStore) GetVersionWithProof(version int64) (store.ProvableKVStore, error) {
+	if !s.tree.VersionExists(version) {
+		return nil, errors.ErrUnknownHeight(version)
+	}
+	return newProver(versionReader{s.tree, version}), nil
+}
+
 // Adapter returns a wrapped version of the tree.
 //
 // Data written here is stored in the tip of the version tree,
@@ -93,7 +130,7 @@ func (s CommitStore) LatestVersion() store.CommitID {
 // to rollback writes here, without throwing away the CommitStore
 // and re-loading from disk.
 func (s CommitStore) Adapter() store.CacheableKVStore {
-	var kv store.KVStore = adapter{s.tree.Tree()}
+	var kv store.KVStore = adapter{reader{s.tree.Tree()}}
 	return store.BTreeCacheable{kv}
 }
 
@@ -103,69 +140,155 @@ func (s CommitStore) CacheWrap() store.KVCacheWrap {
 	return s.Adapter().CacheWrap()
 }
 
-// func (b *Bonsai) GetVersionedWithProof(key []byte, version int64) ([]byte, iavl.KeyProof, error) {
-//   return b.Tree.GetVersionedWithProof(key, uint64(version))
-// }
+// TODO: create batch and wrap the rest in btree...
+
+// reader converts any iavl.Tree to match the ReadOnlyKVStore interface
+type reader struct {
+	tree *iavl.Tree
+}
 
-// TODO: create batch and reader and wrap the rest in btree...
+var _ store.ReadOnlyKVStore = reader{}
 
 // adapter converts the working iavl.Tree to match these interfaces
 type adapter struct {
-	tree *iavl.Tree
+	reader
 }
 
 var _ store.KVStore = adapter{}
 
 // Get returns nil iff key doesn't exist. Panics on nil key.
-func (a adapter) Get(key []byte) []byte {
-	_, val := a.tree.Get(key)
+func (r reader) Get(key []byte) []byte {
+	_, val := r.tree.Get(key)
 	return val
 }
 
 // Has checks if a key exists. Panics on nil key.
-func (a adapter) Has(key []byte) bool {
-	return a.tree.Has(key)
+func (r reader) Has(key []byte) bool {
+	return r.tree.Has(key)
 }
 
-// Set adds a new value
-func (a adapter) Set(key, value []byte) {
-	a.tree.Set(key, value)
+// Iterator over a domain of keys in ascending order. End is exclusive.
+// Start must be less than end, or the Iterator is invalid.
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+//
+// Items are loaded one page at a time, as the Iterator advances.
+func (r reader) Iterator(start, end []byte) store.Iterator {
+	return newPageIterator(treePages(r.tree), start, end, true)
 }
 
-// Delete removes from the tree
-func (a adapter) Delete(key []byte) {
-	a.tree.Remove(key)
+// ReverseIterator over a domain of keys in descending order. End is exclusive.
+// Start must be less than end, or the Iterator is invalid.
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+//
+// Items are loaded one page at a time, as the Iterator advances.
+func (r reader) ReverseIterator(start, end []byte) store.Iterator {
+	return newPageIterator(treePages(r.tree), start, end, false)
 }
 
-// NewBatch returns a batch that can write multiple ops atomically
-func (a adapter) NewBatch() store.Batch {
-	return store.NewNonAtomicBatch(a)
+// versionReader reads one committed version through the
+// VersionedTree, which keeps the roots of all versions that
+// are still on disk, so we never load a copy of the tree
+type versionReader struct {
+	tree    *iavl.VersionedTree
+	version int64
+}
+
+var _ store.ReadOnlyKVStore = versionReader{}
+
+// Get returns nil iff key doesn't exist. Panics on nil key.
+func (r versionReader) Get(key []byte) []byte {
+	_, val := r.tree.GetVersioned(key, r.version)
+	return val
+}
+
+// Has checks if a key exists. Panics on nil key.
+func (r versionReader) Has(key []byte) bool {
+	return r.Get(key) != nil
 }
 
 // Iterator over a domain of keys in ascending order. End is exclusive.
 // Start must be less than end, or the Iterator is invalid.
 // CONTRACT: No writes may happen within a domain while an iterator exists over it.
-func (a adapter) Iterator(start, end []byte) store.Iterator {
-	var res []store.Model
-	add := func(key []byte, value []byte) bool {
-		m := store.Model{Key: key, Value: value}
-		res = append(res, m)
-		return false
-	}
-	a.tree.IterateRange(start, end, true, add)
-	return store.NewSliceIterator(res)
+//
+// Items are loaded one page at a time, as the Iterator advances.
+func (r versionReader) Iterator(start, end []byte) store.Iterator {
+	return newPageIterator(r.pages, start, end, true)
 }
 
 // ReverseIterator over a domain of keys in descending order. End is exclusive.
-// Start must be greater than end, or the Iterator is invalid.
+// Start must be less than end, or the Iterator is invalid.
 // CONTRACT: No writes may happen within a domain while an iterator exists over it.
-func (a adapter) ReverseIterator(start, end []byte) store.Iterator {
-	var res []store.Model
-	add := func(key []byte, value []byte) bool {
-		m := store.Model{Key: key, Value: value}
-		res = append(res, m)
-		return false
-	}
-	a.tree.IterateRange(start, end, false, add)
-	return store.NewSliceIterator(res)
+//
+// Items are loaded one page at a time, as the Iterator advances.
+func (r versionReader) ReverseIterator(start, end []byte) store.Iterator {
+	return newPageIterator(r.pages, start, end, false)
+}
+
+// pages is the pageLoader for this version.
+//
+// iavl only serves ranges of old versions along with a proof,
+// which include the end and are descending if we swap start
+// and end, so we translate our domain into those terms.
+func (r versionReader) pages(start, end []byte, ascending bool, limit int) []store.Model {
+	if start == nil {
+		start = []byte{}
+	}
+	if end != nil && bytes.Compare(start, end) >= 0 {
+		return nil
+	}
+	to := end
+	if to == nil {
+		to = r.lastKey(start)
+		if to == nil {
+			return nil
+		}
+	}
+
+	from := start
+	if !ascending {
+		from, to = to, from
+	}
+	// one more, in case the end is included
+	keys, values, _, err := r.tree.GetVersionedRangeWithProof(from, to, limit+1, r.version)
+	if err == iavl.ErrNilRoot {
+		return nil
+	}
+	if err != nil {
+		panic(err)
+	}
+	res := make([]store.Model, 0, len(keys))
+	for i, key := range keys {
+		if end == nil || !bytes.Equal(key, end) {
+			res = append(res, store.Pair(key, values[i]))
+		}
+	}
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
+
+// lastKey returns the last key from start until the end
+// of the tree, or nil if there is none
+func (r versionReader) lastKey(start []byte) []byte {
+	last, _, _, err := r.tree.GetVersionedLastInRangeWithProof(start, nil, r.version)
+	if err != nil {
+		return nil
+	}
+	return last
+}
+
+// Set adds a new value
+func (a adapter) Set(key, value []byte) {
+	a.tree.Set(key, value)
+}
+
+// Delete removes from the tree
+func (a adapter) Delete(key []byte) {
+	a.tree.Remove(key)
+}
+
+// NewBatch returns a batch that can write multiple ops atomically
+func (a adapter) NewBatch() store.Batch {
+	return store.NewNonAtomicBatch(a)
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tmlibs/db"
 
 	"github.com/confio/weave/store"
 )
@@ -187,7 +188,7 @@ func TestCommitOverwrite(t *testing.T) {
 	for i, tc := range cases {
 		commit, close := makeCommitStore()
 		// only one to trigger a cleanup
-		commit.numHistory = 1
+		commit.pruning = KeepRecent(1)
 
 		id := commit.LatestVersion()
 		assert.Equal(t, int64(0), id.Version)
@@ -243,6 +244,98 @@ func TestCommitOverwrite(t *testing.T) {
 	}
 }
 
+// TestCommitHistory checks that we can read old versions
+// as long as they are still in the history
+func TestCommitHistory(t *testing.T) {
+	ks := randKeys(3, 16)
+	vs := randKeys(3, 40)
+
+	db := dbm.NewMemDB()
+	commit := newCommitStore(db)
+	commit.pruning = KeepRecent(2)
+
+	// nothing committed yet
+	_, err := commit.GetVersion(1)
+	assert.Error(t, err)
+
+	// commit one value per version, overwriting the first key
+	for i := range vs {
+		cache := commit.CacheWrap()
+		cache.Set(ks[0], vs[i])
+		cache.Set(ks[i], vs[i])
+		cache.Write()
+		id := commit.Commit()
+		assert.Equal(t, int64(i+1), id.Version)
+	}
+
+	// version 1 is already pruned
+	_, err = commit.GetVersion(1)
+	assert.Error(t, err)
+	// version 4 was never written
+	_, err = commit.GetVersion(4)
+	assert.Error(t, err)
+
+	checkVersions := func(cs CommitStore) {
+		v2, err := cs.GetVersion(2)
+		require.NoError(t, err)
+		assert.Equal(t, vs[1], v2.Get(ks[0]))
+		assert.Equal(t, vs[1], v2.Get(ks[1]))
+		assert.False(t, v2.Has(ks[2]))
+		verifyIterator(t, sortModels([]Model{store.Pair(ks[0], vs[1]), store.Pair(ks[1], vs[1])}),
+			v2.Iterator(nil, nil), "version 2")
+
+		v3, err := cs.GetVersion(3)
+		require.NoError(t, err)
+		assert.Equal(t, vs[2], v3.Get(ks[0]))
+		assert.Equal(t, vs[2], v3.Get(ks[2]))
+	}
+	checkVersions(commit)
+
+	// make sure we can still read history after a restart
+	reload := newCommitStore(db)
+	err = reload.LoadLatestVersion()
+	require.NoError(t, err)
+	checkVersions(reload)
+}
+
+// TestVersionIterator reads ranges of an old version,
+// over several pages
+func TestVersionIterator(t *testing.T) {
+	models := sortModels(randModels(2*pageSize+3, 8, 40))
+
+	commit := MockCommitStore()
+	cache := commit.CacheWrap()
+	for _, op := range makeSetOps(models...) {
+		op.Apply(cache)
+	}
+	cache.Write()
+	commit.Commit()
+
+	// change the next version, so we read the old one
+	cache = commit.CacheWrap()
+	for _, op := range makeDelOps(models[:10]...) {
+		op.Apply(cache)
+	}
+	cache.Write()
+	commit.Commit()
+
+	v1, err := commit.GetVersion(1)
+	require.NoError(t, err)
+	verifyIterator(t, models, v1.Iterator(nil, nil), "ascending")
+	verifyIterator(t, reverse(models), v1.ReverseIterator(nil, nil), "descending")
+
+	low, high := models[2].Key, models[2+pageSize].Key
+	verifyIterator(t, models[2:2+pageSize], v1.Iterator(low, high), "ascending domain")
+	verifyIterator(t, reverse(models[2:2+pageSize]), v1.ReverseIterator(low, high), "descending domain")
+	verifyIterator(t, models[2:], v1.Iterator(low, nil), "open end")
+	verifyIterator(t, reverse(models[:2+pageSize]), v1.ReverseIterator(nil, high), "open start")
+	verifyIterator(t, nil, v1.Iterator(high, low), "empty domain")
+
+	v2, err := commit.GetVersion(2)
+	require.NoError(t, err)
+	verifyIterator(t, models[10:], v2.Iterator(nil, nil), "version 2")
+}
+
 // TestFuzzCacheIterator makes sure the basic iterator
 // works. Includes random deletes, but not nested iterators.
 func TestFuzzCacheIterator(t *testing.T) {
@@ -0,0 +1,123 @@
+package iavl
+
+import (
+	"github.com/tendermint/iavl"
+
+	"github.com/confio/weave/store"
+)
+
+// pageSize is the number of items an iterator reads
+// from the tree at once
+const pageSize = 100
+
+// pageLoader returns up to limit items of the domain from start
+// (inclusive) to end (exclusive), in the order of the iterator.
+// A nil start or end is unbounded on that side.
+type pageLoader func(start, end []byte, ascending bool, limit int) []store.Model
+
+// treePages loads pages from a tree in memory
+func treePages(tree *iavl.Tree) pageLoader {
+	return func(start, end []byte, ascending bool, limit int) []store.Model {
+		res := make([]store.Model, 0, limit)
+		tree.IterateRange(start, end, ascending, func(key, value []byte) bool {
+			res = append(res, store.Pair(key, value))
+			return len(res) == limit
+		})
+		return res
+	}
+}
+
+// pageIterator streams a range of the tree, one page at a time.
+//
+// iavl only supports iteration with callbacks, so we stop the
+// traversal once a page is full, and start the next page right
+// after the last key we read. Nothing runs in the background,
+// so an iterator holds no resources but its current page,
+// even if it is never closed.
+type pageIterator struct {
+	load       pageLoader
+	start, end []byte
+	ascending  bool
+
+	page []store.Model
+	// last is set once there is nothing after the current page
+	last bool
+}
+
+var _ store.Iterator = (*pageIterator)(nil)
+
+// newPageIterator returns an iterator over the domain,
+// with the first page loaded
+func newPageIterator(load pageLoader, start, end []byte, ascending bool) *pageIterator {
+	i := &pageIterator{
+		load:      load,
+		start:     start,
+		end:       end,
+		ascending: ascending,
+	}
+	i.loadPage()
+	return i
+}
+
+// loadPage reads the next page, and moves the domain
+// past its last key
+func (i *pageIterator) loadPage() {
+	i.page = i.load(i.start, i.end, i.ascending, pageSize)
+	i.last = len(i.page) < pageSize
+	if i.last {
+		return
+	}
+	key := i.page[len(i.page)-1].Key
+	if i.ascending {
+		// the smallest key after this one
+		next := make([]byte, len(key)+1)
+		copy(next, key)
+		i.start = next
+	} else {
+		i.end = key
+	}
+}
+
+// Valid returns whether the current position is valid.
+// Once invalid, an Iterator is forever invalid.
+func (i *pageIterator) Valid() bool {
+	return len(i.page) > 0
+}
+
+// Next moves the iterator to the next sequential key in the database, as
+// defined by order of iteration.
+//
+// If Valid returns false, this method will panic.
+func (i *pageIterator) Next() {
+	i.assertValid()
+	i.page = i.page[1:]
+	if len(i.page) == 0 && !i.last {
+		i.loadPage()
+	}
+}
+
+// Key returns the key of the cursor.
+// If Valid returns false, this method will panic.
+func (i *pageIterator) Key() (key []byte) {
+	i.assertValid()
+	return i.page[0].Key
+}
+
+// Value returns the value of the cursor.
+// If Valid returns false, this method will panic.
+func (i *pageIterator) Value() (value []byte) {
+	i.assertValid()
+	return i.page[0].Value
+}
+
+// Close releases the current page
+func (i *pageIterator) Close() {
+	i.page = nil
+	i.last = true
+}
+
+func (i *pageIterator) assertValid() {
+	if !i.Valid() {
+		panic("Passed end of iterator")
+	}
+}
@@ -0,0 +1,63 @@
+package iavl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPageIterator makes sure we read ranges over several
+// pages, and can stop iterating early
+func TestPageIterator(t *testing.T) {
+	models := sortModels(randModels(3*pageSize+7, 8, 40))
+	reversed := reverse(models)
+
+	commit := MockCommitStore()
+	kv := commit.Adapter()
+	for _, op := range makeSetOps(models...) {
+		op.Apply(kv)
+	}
+	commit.Commit()
+
+	// all pages, in both directions
+	verifyIterator(t, models, kv.Iterator(nil, nil), "ascending")
+	verifyIterator(t, reversed, kv.ReverseIterator(nil, nil), "descending")
+
+	// a domain ending right on a page boundary
+	low, high := models[5].Key, models[5+2*pageSize].Key
+	verifyIterator(t, models[5:5+2*pageSize], kv.Iterator(low, high), "ascending domain")
+	verifyIterator(t, reversed[len(models)-5-2*pageSize:len(models)-5],
+		kv.ReverseIterator(low, high), "descending domain")
+
+	// read a few items, then stop
+	itr := kv.Iterator(nil, nil)
+	for i := 0; i < 3; i++ {
+		require.True(t, itr.Valid())
+		assert.Equal(t, models[i].Key, itr.Key())
+		itr.Next()
+	}
+	itr.Close()
+	assert.False(t, itr.Valid())
+	assert.Panics(t, func() { itr.Next() })
+	// closing twice is fine
+	itr.Close()
+
+	// same for the reverse direction inside a cache
+	cache := commit.CacheWrap()
+	ritr := cache.ReverseIterator(models[10].Key, models[20].Key)
+	require.True(t, ritr.Valid())
+	assert.Equal(t, models[19].Key, ritr.Key())
+	ritr.Close()
+
+	// we can safely write again
+	cache.Set(models[5].Key, []byte("updated"))
+	cache.Write()
+	assert.Equal(t, []byte("updated"), commit.Adapter().Get(models[5].Key))
+
+	// empty ranges are never valid
+	empty := kv.Iterator(models[3].Key, models[3].Key)
+	assert.False(t, empty.Valid())
+	empty.Close()
+	verifyIterator(t, nil, MockCommitStore().Adapter().Iterator(nil, nil), "empty tree")
+}
@@ -0,0 +1,450 @@
+package iavl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tendermint/iavl"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+)
+
+var (
+	errUnproven   = fmt.Errorf("Result not covered by proof")
+	errIncomplete = fmt.Errorf("Result is missing proven keys")
+	errEmptyTree  = fmt.Errorf("Proof has data for an empty tree")
+)
+
+// prover is a read-only view on one version of the tree,
+// which records a merkle proof for every read it serves.
+//
+// Ranges are only proven up to the last key that was read,
+// so a query that stops early doesn't prove the whole domain.
+// If we fail to prove anything, all reads still succeed,
+// but Proof will return the error.
+type prover struct {
+	versionReader
+	proofs ProofSet
+	err    error
+	// iterators are proven once we know how far they were read
+	iterators []*provenIterator
+}
+
+var _ weave.ProvableKVStore = (*prover)(nil)
+
+func newProver(r versionReader) *prover {
+	return &prover{versionReader: r}
+}
+
+// Proof returns the serialized ProofSet for all reads so far
+func (p *prover) Proof() ([]byte, error) {
+	for _, itr := range p.iterators {
+		p.proveRange(itr)
+	}
+	p.iterators = nil
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.proofs.Marshal()
+}
+
+// fail stores the first error we get
+func (p *prover) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// Get returns nil iff key doesn't exist. Panics on nil key.
+// Records an existence or absence proof.
+func (p *prover) Get(key []byte) []byte {
+	value, proof, err := p.tree.GetVersionedWithProof(key, p.version)
+	if errors.Cause(err) == iavl.ErrNilRoot {
+		// nothing is in an empty tree, which has no hash to prove it
+		p.proofs.Keys = append(p.proofs.Keys, &KeyProof{Key: key})
+		return nil
+	}
+	if err != nil {
+		p.fail(err)
+		return p.versionReader.Get(key)
+	}
+	p.proofs.Keys = append(p.proofs.Keys, &KeyProof{
+		Key:   key,
+		Value: value,
+		Proof: proof.Bytes(),
+	})
+	return value
+}
+
+// Has checks if a key exists. Panics on nil key.
+// Records an existence or absence proof.
+func (p *prover) Has(key []byte) bool {
+	return p.Get(key) != nil
+}
+
+// Iterator over a domain of keys in ascending order. End is exclusive.
+// Records a range proof over the part of the domain that was read.
+func (p *prover) Iterator(start, end []byte) store.Iterator {
+	return p.iterate(start, end, true)
+}
+
+// ReverseIterator over a domain of keys in descending order. End is exclusive.
+// Records a range proof over the part of the domain that was read.
+func (p *prover) ReverseIterator(start, end []byte) store.Iterator {
+	return p.iterate(start, end, false)
+}
+
+func (p *prover) iterate(start, end []byte, ascending bool) store.Iterator {
+	itr := &provenIterator{
+		pageIterator: newPageIterator(p.pages, start, end, ascending),
+		start:        start,
+		end:          end,
+		ascending:    ascending,
+	}
+	itr.done = !itr.Valid()
+	p.iterators = append(p.iterators, itr)
+	return itr
+}
+
+// proveRange records a proof for all keys the iterator returned,
+// from the start of its domain until the last key that was read,
+// or over the whole domain if it was read completely
+func (p *prover) proveRange(itr *provenIterator) {
+	if !itr.done && itr.read == nil {
+		return
+	}
+	low, high := itr.start, itr.end
+	if low == nil {
+		low = []byte{}
+	}
+	if high != nil && bytes.Compare(low, high) >= 0 {
+		return
+	}
+	if !itr.done {
+		// descending iterators are read from the top
+		if itr.ascending {
+			high = itr.read
+		} else {
+			low = itr.read
+		}
+	}
+	if high == nil {
+		high = p.lastKey(low)
+		if high == nil {
+			return
+		}
+	}
+
+	// iavl ranges include the end, and are descending
+	// if we swap start and end
+	from, to := low, high
+	if !itr.ascending {
+		from, to = high, low
+	}
+	keys, values, proof, err := p.tree.GetVersionedRangeWithProof(from, to, 0, p.version)
+	if err == iavl.ErrNilRoot {
+		return
+	}
+	if err == nil {
+		var bz []byte
+		bz, err = json.Marshal(proof)
+		p.proofs.Ranges = append(p.proofs.Ranges, &RangeProof{
+			Start:  from,
+			End:    to,
+			Keys:   keys,
+			Values: values,
+			Proof:  bz,
+		})
+	}
+	if err != nil {
+		p.fail(err)
+	}
+}
+
+// provenIterator remembers how far it was read,
+// so we can prove exactly that part of the domain
+type provenIterator struct {
+	*pageIterator
+	start, end []byte
+	ascending  bool
+
+	// read is the last key that was returned
+	read []byte
+	// done is set once the iterator passed the end of the domain
+	done bool
+}
+
+// Key returns the key of the cursor.
+// If Valid returns false, this method will panic.
+func (i *provenIterator) Key() []byte {
+	key := i.pageIterator.Key()
+	i.read = key
+	return key
+}
+
+// Value returns the value of the cursor.
+// If Valid returns false, this method will panic.
+func (i *provenIterator) Value() []byte {
+	i.read = i.pageIterator.Key()
+	return i.pageIterator.Value()
+}
+
+// Next moves the iterator to the next sequential key in the database, as
+// defined by order of iteration.
+//
+// If Valid returns false, this method will panic.
+func (i *provenIterator) Next() {
+	i.pageIterator.Next()
+	i.done = !i.Valid()
+}
+
+//------------------ verification ------------------
+
+// ReadProof parses the Proof returned along with a query
+func ReadProof(data []byte) (*ProofSet, error) {
+	var proofs ProofSet
+	err := proofs.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &proofs, nil
+}
+
+// Verify checks all contained proofs against the root hash.
+//
+// Note that a query at height H returns the state after
+// executing block H, whose root hash is stored as the
+// AppHash in the header of block H+1.
+func (p *ProofSet) Verify(root []byte) error {
+	if len(root) == 0 {
+		return p.verifyEmpty()
+	}
+	for _, kp := range p.Keys {
+		proof, err := iavl.ReadKeyProof(kp.Proof)
+		if err != nil {
+			return err
+		}
+		err = proof.Verify(kp.Key, valueOrNil(kp.Value), root)
+		if err != nil {
+			return err
+		}
+	}
+	for _, rp := range p.Ranges {
+		var proof iavl.KeyRangeProof
+		err := json.Unmarshal(rp.Proof, &proof)
+		if err != nil {
+			return err
+		}
+		err = proof.Verify(rp.Start, rp.End, 0, rp.Keys, rp.Values, root)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyEmpty checks the proofs for an empty tree,
+// which has no root hash, claim that everything is absent
+func (p *ProofSet) verifyEmpty() error {
+	for _, kp := range p.Keys {
+		if len(kp.Value) != 0 || len(kp.Proof) != 0 {
+			return errEmptyTree
+		}
+	}
+	for _, rp := range p.Ranges {
+		if len(rp.Keys) != 0 || len(rp.Proof) != 0 {
+			return errEmptyTree
+		}
+	}
+	return nil
+}
+
+// Proves returns true if the proofs show that the key has the
+// given value, or that the key is absent if value is nil.
+//
+// This is only meaningful after Verify has succeeded.
+func (p *ProofSet) Proves(key, value []byte) bool {
+	value = valueOrNil(value)
+	for _, kp := range p.Keys {
+		if bytes.Equal(kp.Key, key) {
+			return bytes.Equal(valueOrNil(kp.Value), value)
+		}
+	}
+	for _, rp := range p.Ranges {
+		low, high := rp.Start, rp.End
+		if bytes.Compare(low, high) > 0 {
+			low, high = high, low
+		}
+		if bytes.Compare(key, low) < 0 || bytes.Compare(key, high) > 0 {
+			continue
+		}
+		for i, k := range rp.Keys {
+			if bytes.Equal(k, key) {
+				return value != nil && bytes.Equal(rp.Values[i], value)
+			}
+		}
+		// inside the range, but not in the list, so it must be absent
+		return value == nil
+	}
+	return false
+}
+
+// Domain is the part of the store a query returned, so we
+// can check that no model was left out of the results
+type Domain struct {
+	// Key is set for a query of a single key
+	Key []byte
+	// Start and End limit all other queries. End is exclusive,
+	// nil means unbounded on that side.
+	Start, End []byte
+	// Reverse is set if the models are in descending order
+	Reverse bool
+}
+
+// KeyDomain is the domain of a query for a single key
+func KeyDomain(key []byte) Domain {
+	return Domain{Key: key}
+}
+
+// RangeDomain is the domain of a query for all keys
+// from start (inclusive) to end (exclusive).
+//
+// For a paged query, end is the key of the next cursor,
+// as the query only returned the models before it.
+func RangeDomain(start, end []byte) Domain {
+	return Domain{Start: start, End: end}
+}
+
+// contains returns true if key lies inside a range domain
+func (d Domain) contains(key []byte) bool {
+	return bytes.Compare(key, d.Start) >= 0 &&
+		(d.End == nil || bytes.Compare(key, d.End) < 0)
+}
+
+// VerifyResults checks the proof returned from a query against
+// the root hash, and ensures that the models are exactly what
+// is stored in the domain of the query: every model is proven,
+// and every proven key of the domain is in the models, in order.
+// A key query without models must prove that the key is absent.
+//
+// Proofs are only complete up to the last key they hold, so
+// a domain without End is only checked up to there, and can't
+// be verified at all if no key follows Start. Queries on
+// buckets always have an End.
+func VerifyResults(data, root []byte, domain Domain, models []weave.Model) error {
+	proofs, err := ReadProof(data)
+	if err != nil {
+		return err
+	}
+	err = proofs.Verify(root)
+	if err != nil {
+		return err
+	}
+	for _, m := range models {
+		if !proofs.Proves(m.Key, m.Value) {
+			return fmt.Errorf("%s: %X", errUnproven, m.Key)
+		}
+	}
+
+	if domain.Key != nil {
+		if len(models) == 0 && !proofs.Proves(domain.Key, nil) {
+			return fmt.Errorf("%s: %X", errUnproven, domain.Key)
+		}
+		if len(models) > 1 ||
+			len(models) == 1 && !bytes.Equal(models[0].Key, domain.Key) {
+			return fmt.Errorf("%s: %X", errUnproven, domain.Key)
+		}
+		return nil
+	}
+
+	// an empty tree holds nothing
+	if len(root) == 0 {
+		if len(models) > 0 {
+			return errEmptyTree
+		}
+		return nil
+	}
+	if !proofs.covers(domain.Start, domain.End) {
+		return fmt.Errorf("%s: domain not proven", errIncomplete)
+	}
+	want := proofs.keysIn(domain)
+	if domain.Reverse {
+		for i, j := 0, len(want)-1; i < j; i, j = i+1, j-1 {
+			want[i], want[j] = want[j], want[i]
+		}
+	}
+	if len(models) != len(want) {
+		return fmt.Errorf("%s: %d of %d", errIncomplete, len(models), len(want))
+	}
+	for i, m := range models {
+		if !bytes.Equal(m.Key, want[i]) {
+			return fmt.Errorf("%s: %X", errIncomplete, want[i])
+		}
+	}
+	return nil
+}
+
+// keysIn returns all keys the range proofs hold inside
+// the domain, in ascending order and without duplicates
+func (p *ProofSet) keysIn(domain Domain) [][]byte {
+	var res [][]byte
+	for _, rp := range p.Ranges {
+		for _, k := range rp.Keys {
+			if domain.contains(k) {
+				res = append(res, k)
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return bytes.Compare(res[i], res[j]) < 0
+	})
+	uniq := res[:0]
+	for i, k := range res {
+		if i == 0 || !bytes.Equal(k, res[i-1]) {
+			uniq = append(uniq, k)
+		}
+	}
+	return uniq
+}
+
+// covers returns true if the range proofs leave no gap
+// from start to end. Without end, they must cover start,
+// and we check up to the last key they prove.
+func (p *ProofSet) covers(start, end []byte) bool {
+	cur := start
+	if cur == nil {
+		cur = []byte{}
+	}
+	for first := true; end == nil || bytes.Compare(cur, end) < 0; first = false {
+		var high []byte
+		for _, rp := range p.Ranges {
+			low, top := rp.Start, rp.End
+			if bytes.Compare(low, top) > 0 {
+				low, top = top, low
+			}
+			if bytes.Compare(low, cur) <= 0 && bytes.Compare(cur, top) <= 0 &&
+				bytes.Compare(top, high) > 0 {
+				high = top
+			}
+		}
+		if high == nil {
+			return end == nil && !first
+		}
+		// the smallest key after high
+		cur = append(append([]byte{}, high...), 0)
+	}
+	return true
+}
+
+// valueOrNil ensures we pass nil (not empty) values to
+// the iavl absence proofs, as protobuf doesn't distinguish them
+func valueOrNil(value []byte) []byte {
+	if len(value) == 0 {
+		return nil
+	}
+	return value
+}
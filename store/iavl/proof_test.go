@@ -0,0 +1,264 @@
+package iavl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/store"
+)
+
+// TestProofs makes sure all reads on a prover produce
+// proofs that the client can verify
+func TestProofs(t *testing.T) {
+	models := sortModels(randModels(2*pageSize+20, 8, 40))
+	missing := randKeys(1, 12)[0]
+
+	commit := MockCommitStore()
+	cache := commit.CacheWrap()
+	for _, op := range makeSetOps(models...) {
+		op.Apply(cache)
+	}
+	cache.Write()
+	id := commit.Commit()
+
+	cases := map[string]struct {
+		read func(store.ReadOnlyKVStore) []Model
+		// what the results must hold completely
+		domain Domain
+		// absent keys we expect to be proven
+		absent [][]byte
+	}{
+		"get": {
+			domain: KeyDomain(models[4].Key),
+			read: func(db store.ReadOnlyKVStore) []Model {
+				return []Model{store.Pair(models[4].Key, db.Get(models[4].Key))}
+			},
+		},
+		"missing": {
+			read: func(db store.ReadOnlyKVStore) []Model {
+				assert.False(t, db.Has(missing))
+				return nil
+			},
+			domain: KeyDomain(missing),
+			absent: [][]byte{missing},
+		},
+		"range": {
+			read: func(db store.ReadOnlyKVStore) []Model {
+				res := consume(db.Iterator(models[3].Key, models[12].Key))
+				assert.Equal(t, models[3:12], res)
+				return res
+			},
+			domain: RangeDomain(models[3].Key, models[12].Key),
+			absent: [][]byte{append(models[5].Key, 0x17)},
+		},
+		"open start": {
+			read: func(db store.ReadOnlyKVStore) []Model {
+				res := consume(db.Iterator(nil, models[5].Key))
+				assert.Equal(t, models[:5], res)
+				return res
+			},
+			domain: RangeDomain(nil, models[5].Key),
+		},
+		"reverse": {
+			read: func(db store.ReadOnlyKVStore) []Model {
+				res := consume(db.ReverseIterator(models[7].Key, models[10].Key))
+				assert.Equal(t, reverse(models[7:10]), res)
+				return res
+			},
+			domain: Domain{Start: models[7].Key, End: models[10].Key, Reverse: true},
+		},
+		"open end": {
+			read: func(db store.ReadOnlyKVStore) []Model {
+				res := consume(db.Iterator(models[15].Key, nil))
+				assert.Equal(t, models[15:], res)
+				return res
+			},
+			domain: RangeDomain(models[15].Key, nil),
+		},
+		"reverse open end": {
+			read: func(db store.ReadOnlyKVStore) []Model {
+				res := consume(db.ReverseIterator(models[15].Key, nil))
+				assert.Equal(t, reverse(models[15:]), res)
+				return res
+			},
+			domain: Domain{Start: models[15].Key, Reverse: true},
+		},
+		"first page": {
+			read: func(db store.ReadOnlyKVStore) []Model {
+				res := consumeN(db.Iterator(models[2].Key, nil), 5)
+				assert.Equal(t, models[2:7], res)
+				return res
+			},
+			// the page ends right after the last model
+			domain: RangeDomain(models[2].Key, append(models[6].Key, 0)),
+		},
+		"reverse first page": {
+			read: func(db store.ReadOnlyKVStore) []Model {
+				res := consumeN(db.ReverseIterator(nil, models[20].Key), 5)
+				assert.Equal(t, reverse(models[15:20]), res)
+				return res
+			},
+			domain: Domain{Start: models[15].Key, End: models[20].Key, Reverse: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			db, err := commit.GetVersionWithProof(id.Version)
+			require.NoError(t, err)
+			res := tc.read(db)
+			proof, err := db.Proof()
+			require.NoError(t, err)
+
+			err = VerifyResults(proof, id.Hash, tc.domain, res)
+			require.NoError(t, err)
+			proofs, err := ReadProof(proof)
+			require.NoError(t, err)
+			for _, key := range tc.absent {
+				assert.True(t, proofs.Proves(key, nil))
+			}
+
+			// proofs must not match other data or hashes
+			err = VerifyResults(proof, randBytes(len(id.Hash)), tc.domain, res)
+			assert.Error(t, err)
+			// nor can the node leave out any model
+			for i := range res {
+				dropped := append(append([]Model{}, res[:i]...), res[i+1:]...)
+				err = VerifyResults(proof, id.Hash, tc.domain, dropped)
+				assert.Error(t, err, "%d", i)
+			}
+			for _, m := range res {
+				assert.False(t, proofs.Proves(m.Key, randBytes(10)))
+				assert.False(t, proofs.Proves(m.Key, nil))
+			}
+		})
+	}
+
+	// only the page we read is proven
+	db, err := commit.GetVersionWithProof(id.Version)
+	require.NoError(t, err)
+	res := consumeN(db.Iterator(models[2].Key, models[50].Key), 5)
+	proof, err := db.Proof()
+	require.NoError(t, err)
+	proofs, err := ReadProof(proof)
+	require.NoError(t, err)
+	require.Len(t, proofs.Ranges, 1)
+	assert.Equal(t, keys(res), proofs.Ranges[0].Keys)
+}
+
+// TestIncompleteResults makes sure a node cannot leave out
+// results and still pass the verification
+func TestIncompleteResults(t *testing.T) {
+	models := sortModels(randModels(30, 8, 40))
+	commit := MockCommitStore()
+	cache := commit.CacheWrap()
+	for _, op := range makeSetOps(models...) {
+		op.Apply(cache)
+	}
+	cache.Write()
+	id := commit.Commit()
+
+	prove := func(read func(store.ReadOnlyKVStore)) []byte {
+		db, err := commit.GetVersionWithProof(id.Version)
+		require.NoError(t, err)
+		read(db)
+		proof, err := db.Proof()
+		require.NoError(t, err)
+		return proof
+	}
+
+	// absence of one key doesn't prove absence of another
+	missing := append(models[3].Key, 0x17)
+	proof := prove(func(db store.ReadOnlyKVStore) { db.Get(missing) })
+	assert.NoError(t, VerifyResults(proof, id.Hash, KeyDomain(missing), nil))
+	err := VerifyResults(proof, id.Hash, KeyDomain(models[3].Key), nil)
+	assert.Error(t, err)
+
+	// a key query cannot return another key
+	proof = prove(func(db store.ReadOnlyKVStore) {
+		db.Get(models[3].Key)
+		db.Get(models[4].Key)
+	})
+	err = VerifyResults(proof, id.Hash, KeyDomain(models[4].Key), models[3:4])
+	assert.Error(t, err)
+	err = VerifyResults(proof, id.Hash, KeyDomain(models[4].Key), models[3:5])
+	assert.Error(t, err)
+
+	// a range must be proven completely, and in order
+	proof = prove(func(db store.ReadOnlyKVStore) {
+		consume(db.Iterator(models[5].Key, models[10].Key))
+	})
+	domain := RangeDomain(models[5].Key, models[10].Key)
+	assert.NoError(t, VerifyResults(proof, id.Hash, domain, models[5:10]))
+	err = VerifyResults(proof, id.Hash, domain, nil)
+	assert.Error(t, err)
+	err = VerifyResults(proof, id.Hash, domain, reverse(models[5:10]))
+	assert.Error(t, err)
+	err = VerifyResults(proof, id.Hash, RangeDomain(models[5].Key, models[12].Key), models[5:10])
+	assert.Error(t, err)
+	err = VerifyResults(proof, id.Hash, RangeDomain(models[4].Key, models[10].Key), models[5:10])
+	assert.Error(t, err)
+	// a smaller domain is fine
+	err = VerifyResults(proof, id.Hash, RangeDomain(models[6].Key, models[8].Key), models[6:8])
+	assert.NoError(t, err)
+}
+
+// TestEmptyProofs makes sure we prove that keys are
+// absent from an empty tree
+func TestEmptyProofs(t *testing.T) {
+	missing := randKeys(1, 12)[0]
+	commit := MockCommitStore()
+	id := commit.Commit()
+
+	db, err := commit.GetVersionWithProof(id.Version)
+	require.NoError(t, err)
+	assert.Nil(t, db.Get(missing))
+	assert.Empty(t, consume(db.Iterator(nil, nil)))
+	assert.Empty(t, consume(db.ReverseIterator(missing, nil)))
+	proof, err := db.Proof()
+	require.NoError(t, err)
+
+	err = VerifyResults(proof, id.Hash, RangeDomain(nil, nil), nil)
+	require.NoError(t, err)
+	err = VerifyResults(proof, id.Hash, KeyDomain(missing), nil)
+	require.NoError(t, err)
+	proofs, err := ReadProof(proof)
+	require.NoError(t, err)
+	assert.True(t, proofs.Proves(missing, nil))
+
+	// there is nothing to prove in an empty tree
+	fake := []Model{store.Pair(missing, []byte("foo"))}
+	err = VerifyResults(proof, id.Hash, KeyDomain(missing), fake)
+	assert.Error(t, err)
+	err = VerifyResults(proof, id.Hash, RangeDomain(nil, nil), fake)
+	assert.Error(t, err)
+	proofs.Keys[0].Value = []byte("foo")
+	err = proofs.Verify(id.Hash)
+	assert.Error(t, err)
+}
+
+// consume reads all models from the iterator
+func consume(itr store.Iterator) []Model {
+	return consumeN(itr, -1)
+}
+
+// consumeN reads up to n models from the iterator,
+// or all of them if n is negative
+func consumeN(itr store.Iterator, n int) []Model {
+	defer itr.Close()
+	var res []Model
+	for ; itr.Valid() && len(res) != n; itr.Next() {
+		res = append(res, store.Pair(itr.Key(), itr.Value()))
+	}
+	return res
+}
+
+func keys(models []Model) [][]byte {
+	res := make([][]byte, len(models))
+	for i, m := range models {
+		res[i] = m.Key
+	}
+	return res
+}
@@ -0,0 +1,67 @@
+package iavl
+
+import (
+	"fmt"
+)
+
+// Pruning defines which versions of the tree we keep on disk.
+//
+// We always keep the KeepRecent most recent versions, and delete
+// each version once it falls out of that window, unless it is
+// a multiple of KeepEvery. A KeepRecent of 0 keeps everything.
+type Pruning struct {
+	// KeepRecent is the number of recent versions to keep
+	KeepRecent int64 `json:"keep_recent"`
+	// KeepEvery also keeps every Nth version forever, 0 disables it
+	KeepEvery int64 `json:"keep_every"`
+}
+
+// DefaultPruning keeps the last DefaultHistory versions
+var DefaultPruning = KeepRecent(DefaultHistory)
+
+// KeepEverything never deletes a version, as needed for
+// archive nodes
+func KeepEverything() Pruning {
+	return Pruning{}
+}
+
+// KeepRecent only keeps the last n versions
+func KeepRecent(n int64) Pruning {
+	return Pruning{KeepRecent: n}
+}
+
+// KeepEveryAndRecent keeps the last recent versions, as well as
+// every nth version forever, so we have regular checkpoints
+func KeepEveryAndRecent(every, recent int64) Pruning {
+	return Pruning{
+		KeepRecent: recent,
+		KeepEvery:  every,
+	}
+}
+
+// Validate makes sure the values are in range
+func (p Pruning) Validate() error {
+	if p.KeepRecent < 0 {
+		return fmt.Errorf("Invalid KeepRecent: %d", p.KeepRecent)
+	}
+	if p.KeepEvery < 0 {
+		return fmt.Errorf("Invalid KeepEvery: %d", p.KeepEvery)
+	}
+	return nil
+}
+
+// toRelease returns the version that falls out of the history
+// once version was committed, and false if it must be kept.
+func (p Pruning) toRelease(version int64) (int64, bool) {
+	if p.KeepRecent == 0 {
+		return 0, false
+	}
+	old := version - p.KeepRecent
+	if old <= 0 {
+		return 0, false
+	}
+	if p.KeepEvery > 0 && old%p.KeepEvery == 0 {
+		return 0, false
+	}
+	return old, true
+}
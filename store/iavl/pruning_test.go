@@ -0,0 +1,49 @@
+package iavl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPruning(t *testing.T) {
+	cases := [...]struct {
+		pruning Pruning
+		commits int64
+		kept    []int64
+		deleted []int64
+	}{
+		0: {KeepEverything(), 12, []int64{1, 5, 12}, nil},
+		1: {KeepRecent(3), 12, []int64{10, 11, 12}, []int64{1, 5, 9}},
+		2: {KeepEveryAndRecent(4, 3), 12, []int64{4, 8, 10, 11, 12}, []int64{1, 3, 5, 9}},
+		3: {KeepRecent(20), 12, []int64{1, 12}, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			assert.NoError(t, tc.pruning.Validate())
+			commit := MockCommitStore().WithPruning(tc.pruning)
+			for v := int64(0); v < tc.commits; v++ {
+				cache := commit.CacheWrap()
+				cache.Set([]byte("version"), []byte(fmt.Sprint(v+1)))
+				cache.Write()
+				commit.Commit()
+			}
+
+			for _, v := range tc.kept {
+				db, err := commit.GetVersion(v)
+				if assert.NoError(t, err, "%d", v) {
+					assert.Equal(t, []byte(fmt.Sprint(v)), db.Get([]byte("version")))
+				}
+			}
+			for _, v := range tc.deleted {
+				_, err := commit.GetVersion(v)
+				assert.Error(t, err, "%d", v)
+			}
+		})
+	}
+
+	assert.Error(t, KeepRecent(-1).Validate())
+	assert.Error(t, KeepEveryAndRecent(-5, 10).Validate())
+}
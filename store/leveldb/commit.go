@@ -0,0 +1,297 @@
+/*
+Package leveldb provides a CommitKVStore persisted to a plain
+goleveldb database.
+
+Unlike store/iavl, there is no merkle tree, so it cannot return
+proofs and only keeps the latest version. In return, reads and
+writes go straight to leveldb, which makes it a good choice for
+private chains and integration tests that value speed over
+provability.
+
+Every commit increases the version and rolls the hash forward
+over all changes in that block, so nodes that processed the
+same blocks still agree on the AppHash.
+*/
+package leveldb
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+)
+
+var (
+	// all application data is stored under dataPrefix,
+	// so it never overlaps with our own metadata
+	dataPrefix = []byte("d:")
+	versionKey = []byte("m:version")
+	hashKey    = []byte("m:hash")
+
+	errNoProofs = fmt.Errorf("Proofs not supported")
+)
+
+// CommitStore is a CommitKVStore persisted to leveldb
+type CommitStore struct {
+	db      *leveldb.DB
+	version int64
+	hash    []byte
+	// changes since the last commit, and a cache to read them
+	changes *changeSet
+	working store.KVCacheWrap
+	// snap is a snapshot of the latest version, shared by all
+	// readers from GetVersion until the next commit releases it
+	snap *leveldb.Snapshot
+}
+
+var _ store.CommitKVStore = (*CommitStore)(nil)
+
+// NewCommitStore opens (or creates) the database name.db
+// in the directory path, and loads the latest version
+func NewCommitStore(path, name string) *CommitStore {
+	db, err := leveldb.OpenFile(filepath.Join(path, name+".db"), nil)
+	if err != nil {
+		panic(err)
+	}
+	commit := newCommitStore(db)
+	err = commit.LoadLatestVersion()
+	if err != nil {
+		panic(err)
+	}
+	return commit
+}
+
+// MockCommitStore creates a new in-memory store for testing
+func MockCommitStore() *CommitStore {
+	db, err := leveldb.Open(storage.NewMemStorage(), nil)
+	if err != nil {
+		panic(err)
+	}
+	return newCommitStore(db)
+}
+
+func newCommitStore(db *leveldb.DB) *CommitStore {
+	s := &CommitStore{db: db}
+	s.reset()
+	return s
+}
+
+// Close releases the database. The store cannot be used
+// any more afterwards
+func (s *CommitStore) Close() error {
+	s.releaseSnapshot()
+	return s.db.Close()
+}
+
+// reset drops all uncommitted changes
+func (s *CommitStore) reset() {
+	s.changes = newChangeSet()
+	s.working = store.NewBTreeCacheWrap(reader{s.db}, s.changes, nil)
+}
+
+// releaseSnapshot frees the snapshot of the last version, if any.
+// Readers still holding it panic on their next read.
+func (s *CommitStore) releaseSnapshot() {
+	if s.snap != nil {
+		s.snap.Release()
+		s.snap = nil
+	}
+}
+
+// Get returns the value at last committed state
+// returns nil iff key doesn't exist. Panics on nil key.
+func (s *CommitStore) Get(key []byte) []byte {
+	return reader{s.db}.Get(key)
+}
+
+// CacheWrap returns a cache over the working state.
+// Once written, the changes are persisted on the next Commit.
+func (s *CommitStore) CacheWrap() store.KVCacheWrap {
+	return s.working.CacheWrap()
+}
+
+// Commit atomically writes all changes along with the next
+// version and hash to disk, and returns info
+func (s *CommitStore) Commit() store.CommitID {
+	version := s.version + 1
+	hash := s.changes.hash(s.hash, version)
+
+	batch := new(leveldb.Batch)
+	s.changes.apply(batch)
+	batch.Put(versionKey, encodeVersion(version))
+	batch.Put(hashKey, hash)
+	err := s.db.Write(batch, &opt.WriteOptions{Sync: true})
+	if err != nil {
+		panic(err)
+	}
+
+	s.version, s.hash = version, hash
+	s.reset()
+	s.releaseSnapshot()
+	return s.LatestVersion()
+}
+
+// LoadLatestVersion loads the latest persisted version.
+// As every commit is a single atomic write, this is always
+// a stable state.
+func (s *CommitStore) LoadLatestVersion() error {
+	s.releaseSnapshot()
+	bz, err := s.db.Get(versionKey, nil)
+	if err == leveldb.ErrNotFound {
+		s.version, s.hash = 0, nil
+		s.reset()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	hash, err := s.db.Get(hashKey, nil)
+	if err != nil {
+		return err
+	}
+	s.version, s.hash = decodeVersion(bz), hash
+	s.reset()
+	return nil
+}
+
+// LatestVersion returns info on the latest version saved to disk
+func (s *CommitStore) LatestVersion() store.CommitID {
+	return store.CommitID{
+		Version: s.version,
+		Hash:    s.hash,
+	}
+}
+
+// GetVersion returns a read-only snapshot of the latest version.
+// All readers share one snapshot, which is released by the next
+// Commit, so they must not be used after that.
+//
+// We keep no history, so all other versions return an error.
+func (s *CommitStore) GetVersion(version int64) (store.ReadOnlyKVStore, error) {
+	if version == 0 || version != s.version {
+		return nil, errors.ErrUnknownHeight(version)
+	}
+	if s.snap == nil {
+		snap, err := s.db.GetSnapshot()
+		if err != nil {
+			return nil, err
+		}
+		s.snap = snap
+	}
+	return reader{s.snap}, nil
+}
+
+// GetVersionWithProof always returns an error, as we have no
+// merkle tree to prove anything against.
+func (s *CommitStore) GetVersionWithProof(version int64) (store.ProvableKVStore, error) {
+	return nil, errors.WithCode(errNoProofs, errors.CodeUnknownRequest)
+}
+
+//------------------ helpers ------------------
+
+// dataKey returns the key in the database for an application key
+func dataKey(key []byte) []byte {
+	res := make([]byte, len(dataPrefix)+len(key))
+	copy(res, dataPrefix)
+	copy(res[len(dataPrefix):], key)
+	return res
+}
+
+func encodeVersion(version int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(version))
+	return bz
+}
+
+func decodeVersion(bz []byte) int64 {
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+// changeSet is a Batch that collects all writes since the
+// last commit. Only the last write to each key is kept.
+//
+// Write is a noop, as the changes are persisted on Commit.
+type changeSet struct {
+	values  map[string][]byte
+	deleted map[string]bool
+}
+
+var _ store.Batch = (*changeSet)(nil)
+
+func newChangeSet() *changeSet {
+	return &changeSet{
+		values:  make(map[string][]byte),
+		deleted: make(map[string]bool),
+	}
+}
+
+// Set records the new value
+func (c *changeSet) Set(key, value []byte) {
+	c.values[string(key)] = value
+	delete(c.deleted, string(key))
+}
+
+// Delete records the deletion
+func (c *changeSet) Delete(key []byte) {
+	delete(c.values, string(key))
+	c.deleted[string(key)] = true
+}
+
+// Write does nothing, we wait for Commit
+func (c *changeSet) Write() {}
+
+// sortedKeys returns all changed keys in order
+func (c *changeSet) sortedKeys() []string {
+	keys := make([]string, 0, len(c.values)+len(c.deleted))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	for k := range c.deleted {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// apply adds all changes to the leveldb batch
+func (c *changeSet) apply(batch *leveldb.Batch) {
+	for _, k := range c.sortedKeys() {
+		if c.deleted[k] {
+			batch.Delete(dataKey([]byte(k)))
+		} else {
+			batch.Put(dataKey([]byte(k)), c.values[k])
+		}
+	}
+}
+
+// hash returns the hash of the next version:
+//
+//	sha256(prev | version | (len(key) | key | len(value) | value)...)
+//
+// changes are added in key order, and a deletion is encoded as
+// a length of -1, so the result only depends on the final state.
+func (c *changeSet) hash(prev []byte, version int64) []byte {
+	h := sha256.New()
+	h.Write(prev)
+	h.Write(encodeVersion(version))
+	for _, k := range c.sortedKeys() {
+		h.Write(encodeVersion(int64(len(k))))
+		h.Write([]byte(k))
+		if c.deleted[k] {
+			h.Write(encodeVersion(-1))
+			continue
+		}
+		value := c.values[k]
+		h.Write(encodeVersion(int64(len(value))))
+		h.Write(value)
+	}
+	return h.Sum(nil)
+}
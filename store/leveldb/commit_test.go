@@ -0,0 +1,193 @@
+package leveldb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+)
+
+// TestCommitStore checks that changes are only visible
+// once written, and persisted on commit
+func TestCommitStore(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("/tmp", "leveldb-commit-")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmpDir)
+
+	commit := NewCommitStore(tmpDir, "base")
+	id := commit.LatestVersion()
+	assert.Equal(t, int64(0), id.Version)
+	assert.Empty(t, id.Hash)
+
+	k1, v1 := []byte("french"), []byte("fry")
+	k2, v2 := []byte("LA"), []byte("Dodgers")
+	k3, v3 := []byte("Bayern"), []byte("Munich")
+
+	// a discarded cache never hits the store
+	c1 := commit.CacheWrap()
+	c1.Set(k3, v3)
+	c1.Discard()
+
+	cache := commit.CacheWrap()
+	cache.Set(k1, v1)
+	cache.Set(k2, v2)
+	assert.Equal(t, v1, cache.Get(k1))
+	assert.Nil(t, commit.Get(k1))
+	cache.Write()
+
+	// written, but not yet committed
+	side := commit.CacheWrap()
+	assert.Equal(t, v2, side.Get(k2))
+	assert.Nil(t, commit.Get(k2))
+
+	id = commit.Commit()
+	assert.Equal(t, int64(1), id.Version)
+	assert.Len(t, id.Hash, 32)
+	assert.Equal(t, v1, commit.Get(k1))
+	assert.Equal(t, v2, commit.Get(k2))
+	assert.Nil(t, commit.Get(k3))
+
+	// overwrite and delete in the next block
+	cache = commit.CacheWrap()
+	cache.Delete(k1)
+	cache.Set(k2, v3)
+	cache.Write()
+	id2 := commit.Commit()
+	assert.Equal(t, int64(2), id2.Version)
+	assert.NotEqual(t, id.Hash, id2.Hash)
+	assert.Nil(t, commit.Get(k1))
+	assert.Equal(t, v3, commit.Get(k2))
+
+	// only the latest version is readable, and nothing is provable
+	v, err := commit.GetVersion(2)
+	require.NoError(t, err)
+	assert.Equal(t, v3, v.Get(k2))
+	assert.False(t, v.Has(k1))
+	_, err = commit.GetVersion(1)
+	assert.True(t, errors.IsUnknownHeightErr(err))
+	_, err = commit.GetVersionWithProof(2)
+	assert.Error(t, err)
+
+	// readers share a snapshot, which the next commit releases
+	same, err := commit.GetVersion(2)
+	require.NoError(t, err)
+	assert.Equal(t, v, same)
+	cache = commit.CacheWrap()
+	cache.Set(k2, v1)
+	cache.Write()
+	id2 = commit.Commit()
+	assert.Panics(t, func() { v.Get(k2) })
+	v, err = commit.GetVersion(3)
+	require.NoError(t, err)
+	assert.Equal(t, v1, v.Get(k2))
+
+	// pending changes are lost on restart
+	cache = commit.CacheWrap()
+	cache.Set(k3, v3)
+	cache.Write()
+	require.NoError(t, commit.Close())
+
+	reload := NewCommitStore(tmpDir, "base")
+	defer reload.Close()
+	assert.Equal(t, id2, reload.LatestVersion())
+	assert.Equal(t, v1, reload.Get(k2))
+	assert.Nil(t, reload.Get(k1))
+	assert.Nil(t, reload.Get(k3))
+}
+
+// TestCommitHash makes sure the hash only depends on the final
+// state of each block, not the order of writes
+func TestCommitHash(t *testing.T) {
+	a, b := MockCommitStore(), MockCommitStore()
+	k1, k2, v1, v2 := []byte{1}, []byte{2}, []byte("one"), []byte("two")
+
+	ca := a.CacheWrap()
+	ca.Set(k1, v2)
+	ca.Set(k2, v2)
+	ca.Set(k1, v1)
+	ca.Write()
+
+	cb := b.CacheWrap()
+	cb.Set(k2, v2)
+	cb.Set(k1, v1)
+	cb.Write()
+
+	assert.Equal(t, a.Commit(), b.Commit())
+
+	// a different value gives a different hash
+	ca = a.CacheWrap()
+	ca.Set(k1, v2)
+	ca.Write()
+	assert.NotEqual(t, a.Commit().Hash, b.Commit().Hash)
+}
+
+func TestIterators(t *testing.T) {
+	commit := MockCommitStore()
+	models := []weave.Model{
+		store.Pair([]byte("a"), []byte("1")),
+		store.Pair([]byte("b"), []byte("2")),
+		store.Pair([]byte("bz"), []byte("3")),
+		store.Pair([]byte("c"), []byte("4")),
+	}
+	cache := commit.CacheWrap()
+	for _, m := range models {
+		cache.Set(m.Key, m.Value)
+	}
+	cache.Write()
+	commit.Commit()
+	db, err := commit.GetVersion(1)
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		itr      store.Iterator
+		expected []weave.Model
+	}{
+		"all":           {db.Iterator(nil, nil), models},
+		"range":         {db.Iterator([]byte("b"), []byte("c")), models[1:3]},
+		"open end":      {db.Iterator([]byte("bz"), nil), models[2:]},
+		"reverse":       {db.ReverseIterator(nil, []byte("bz")), []weave.Model{models[1], models[0]}},
+		"reverse all":   {db.ReverseIterator(nil, nil), []weave.Model{models[3], models[2], models[1], models[0]}},
+		"empty":         {db.Iterator([]byte("d"), nil), nil},
+		"through cache": {commit.CacheWrap().Iterator([]byte("a"), []byte("b")), models[:1]},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var res []weave.Model
+			for ; tc.itr.Valid(); tc.itr.Next() {
+				res = append(res, store.Pair(tc.itr.Key(), tc.itr.Value()))
+			}
+			tc.itr.Close()
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+// TestStoreApp makes sure we can run an app on this store
+func TestStoreApp(t *testing.T) {
+	commit := MockCommitStore()
+	myApp := app.NewStoreApp("test", commit, weave.NewQueryRouter(), context.Background()).
+		WithInit(app.ChainInitializers())
+	myApp.InitChain(abci.RequestInitChain{ChainId: "test-chain", AppStateBytes: []byte("{}")})
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+
+	key, value := []byte("foo"), []byte("bar")
+	myApp.DeliverStore().Set(key, value)
+	res := myApp.Commit()
+	assert.Len(t, res.Data, 32)
+
+	info := myApp.Info(abci.RequestInfo{})
+	assert.Equal(t, int64(1), info.LastBlockHeight)
+	assert.Equal(t, res.Data, info.LastBlockAppHash)
+	assert.Equal(t, value, commit.Get(key))
+	assert.Equal(t, "test-chain", myApp.GetChainID())
+}
@@ -0,0 +1,152 @@
+package leveldb
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/confio/weave/store"
+)
+
+// source is implemented by both leveldb.DB and leveldb.Snapshot
+type source interface {
+	Get(key []byte, ro *opt.ReadOptions) ([]byte, error)
+	Has(key []byte, ro *opt.ReadOptions) (bool, error)
+	NewIterator(slice *util.Range, ro *opt.ReadOptions) iterator.Iterator
+}
+
+// reader exposes the application data in a leveldb source
+// as a ReadOnlyKVStore
+type reader struct {
+	src source
+}
+
+var _ store.ReadOnlyKVStore = reader{}
+
+// Get returns nil iff key doesn't exist. Panics on nil key.
+func (r reader) Get(key []byte) []byte {
+	value, err := r.src.Get(dataKey(key), nil)
+	if err == leveldb.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// Has checks if a key exists. Panics on nil key.
+func (r reader) Has(key []byte) bool {
+	ok, err := r.src.Has(dataKey(key), nil)
+	if err != nil {
+		panic(err)
+	}
+	return ok
+}
+
+// Iterator over a domain of keys in ascending order. End is exclusive.
+// Start must be less than end, or the Iterator is invalid.
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (r reader) Iterator(start, end []byte) store.Iterator {
+	return newDBIterator(r.src.NewIterator(dataRange(start, end), nil), false)
+}
+
+// ReverseIterator over a domain of keys in descending order. End is exclusive.
+// Start must be less than end, or the Iterator is invalid.
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (r reader) ReverseIterator(start, end []byte) store.Iterator {
+	return newDBIterator(r.src.NewIterator(dataRange(start, end), nil), true)
+}
+
+// dataRange maps the domain to the keys in the database,
+// nil start and end cover all application data
+func dataRange(start, end []byte) *util.Range {
+	rng := util.BytesPrefix(dataPrefix)
+	if start != nil {
+		rng.Start = dataKey(start)
+	}
+	if end != nil {
+		rng.Limit = dataKey(end)
+	}
+	return rng
+}
+
+// dbIterator wraps a leveldb iterator to strip the prefix
+// and match our Iterator interface
+type dbIterator struct {
+	source  iterator.Iterator
+	reverse bool
+	valid   bool
+}
+
+var _ store.Iterator = (*dbIterator)(nil)
+
+func newDBIterator(source iterator.Iterator, reverse bool) *dbIterator {
+	i := &dbIterator{
+		source:  source,
+		reverse: reverse,
+	}
+	if reverse {
+		i.valid = source.Last()
+	} else {
+		i.valid = source.First()
+	}
+	i.checkError()
+	return i
+}
+
+// Valid returns whether the current position is valid.
+// Once invalid, an Iterator is forever invalid.
+func (i *dbIterator) Valid() bool {
+	return i.valid
+}
+
+// Next moves the iterator to the next sequential key in the database, as
+// defined by order of iteration.
+//
+// If Valid returns false, this method will panic.
+func (i *dbIterator) Next() {
+	i.assertValid()
+	if i.reverse {
+		i.valid = i.source.Prev()
+	} else {
+		i.valid = i.source.Next()
+	}
+	i.checkError()
+}
+
+// Key returns the key of the cursor.
+// If Valid returns false, this method will panic.
+func (i *dbIterator) Key() (key []byte) {
+	i.assertValid()
+	// leveldb reuses the buffer, so we must copy
+	return append([]byte(nil), i.source.Key()[len(dataPrefix):]...)
+}
+
+// Value returns the value of the cursor.
+// If Valid returns false, this method will panic.
+func (i *dbIterator) Value() (value []byte) {
+	i.assertValid()
+	return append([]byte(nil), i.source.Value()...)
+}
+
+// Close releases the Iterator.
+func (i *dbIterator) Close() {
+	i.valid = false
+	i.source.Release()
+}
+
+func (i *dbIterator) assertValid() {
+	if !i.valid {
+		panic("Passed end of iterator")
+	}
+}
+
+// checkError panics on any database error, there is no way
+// to recover from that
+func (i *dbIterator) checkError() {
+	if err := i.source.Error(); err != nil {
+		panic(err)
+	}
+}
@@ -29,6 +29,9 @@ type KVCacheWrap = weave.KVCacheWrap
 // CommitKVStore is an alias to interface in root package
 type CommitKVStore = weave.CommitKVStore
 
+// ProvableKVStore is an alias to interface in root package
+type ProvableKVStore = weave.ProvableKVStore
+
 // CommitID is an alias to interface in root package
 type CommitID = weave.CommitID
 
@@ -96,6 +96,19 @@ func TestCondition(t *testing.T) {
 			[]byte("BZZ"),
 			"Foo/B4r/425A5A",
 		},
+		// type too long
+		{
+			NewCondition("sigs", "secp256k1xx", []byte{34}), true, "", "", nil, "",
+		},
+		// longer type is fine
+		{
+			NewCondition("sigs", "secp256k1", []byte{0xCA, 0xFE}),
+			false,
+			"sigs",
+			"secp256k1",
+			[]byte{0xCA, 0xFE},
+			"sigs/secp256k1/CAFE",
+		},
 		// non-ascii data
 		{
 			NewCondition("help", "W1N", []byte{0xCA, 0xFE}),
@@ -0,0 +1,158 @@
+package batch
+
+import (
+	"strings"
+
+	"github.com/confio/weave"
+)
+
+// Decorator unpacks batch messages, and passes all other
+// txs straight through.
+//
+// Each message of the batch is sent to the next handler in turn,
+// and all of them run inside one savepoint, so if one fails,
+// the whole batch is reverted. Checking a batch never writes.
+type Decorator struct{}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator creates a Decorator
+func NewDecorator() Decorator {
+	return Decorator{}
+}
+
+// Check runs Check on every message in the batch, and
+// adds up the gas and payment of all of them.
+//
+// Check doesn't write, so a message could not see what the
+// ones before it do, like a send of coins that were just
+// released. So all but the last message are also delivered,
+// on a cache that is thrown away after the check.
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	msgs, err := unpack(tx)
+	if err != nil {
+		return weave.CheckResult{}, err
+	}
+	if msgs == nil {
+		return next.Check(ctx, store, tx)
+	}
+
+	db, deliver := store, weave.Deliverer(nil)
+	if cstore, ok := store.(weave.CacheableKVStore); ok {
+		cache := cstore.CacheWrap()
+		defer cache.Discard()
+		db = cache
+		// the next step of the chain is always a full handler
+		deliver, _ = next.(weave.Deliverer)
+	}
+
+	var res weave.CheckResult
+	data := make([][]byte, len(msgs))
+	logs := make([]string, len(msgs))
+	for i, msg := range msgs {
+		mtx := Tx{Tx: tx, msg: msg}
+		r, err := next.Check(ctx, db, mtx)
+		if err != nil {
+			return weave.CheckResult{}, err
+		}
+		data[i], logs[i] = r.Data, r.Log
+		res.GasAllocated += r.GasAllocated
+		res.GasPayment += r.GasPayment
+
+		if deliver != nil && i < len(msgs)-1 {
+			_, err = deliver.Deliver(ctx, db, mtx)
+			if err != nil {
+				return weave.CheckResult{}, err
+			}
+		}
+	}
+
+	res.Data, err = (&ByteArrayList{Elements: data}).Marshal()
+	res.Log = joinLogs(logs)
+	return res, err
+}
+
+// Deliver runs Deliver on every message in the batch, and
+// combines the results. Data is a ByteArrayList with the data of
+// each message in order, while logs, tags and validator diffs
+// are concatenated.
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	msgs, err := unpack(tx)
+	if err != nil {
+		return weave.DeliverResult{}, err
+	}
+	if msgs == nil {
+		return next.Deliver(ctx, store, tx)
+	}
+
+	var res weave.DeliverResult
+	data := make([][]byte, len(msgs))
+	logs := make([]string, len(msgs))
+	err = savepoint(store, func(db weave.KVStore) error {
+		for i, msg := range msgs {
+			r, err := next.Deliver(ctx, db, Tx{Tx: tx, msg: msg})
+			if err != nil {
+				return err
+			}
+			data[i], logs[i] = r.Data, r.Log
+			res.Diff = append(res.Diff, r.Diff...)
+			res.Tags = append(res.Tags, r.Tags...)
+			res.GasUsed += r.GasUsed
+		}
+		return nil
+	})
+	if err != nil {
+		return weave.DeliverResult{}, err
+	}
+
+	res.Data, err = (&ByteArrayList{Elements: data}).Marshal()
+	res.Log = joinLogs(logs)
+	return res, err
+}
+
+// unpack returns the messages if tx holds a valid batch,
+// or nil if it is a normal tx
+func unpack(tx weave.Tx) ([]weave.Msg, error) {
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	b, ok := msg.(Msg)
+	if !ok {
+		return nil, nil
+	}
+	return Validate(b)
+}
+
+// savepoint runs fn on a cache of the store, and only writes
+// the changes if it succeeds
+func savepoint(store weave.KVStore, fn func(weave.KVStore) error) error {
+	cstore, ok := store.(weave.CacheableKVStore)
+	if !ok {
+		return fn(store)
+	}
+
+	cache := cstore.CacheWrap()
+	err := fn(cache)
+	if err == nil {
+		cache.Write()
+	} else {
+		cache.Discard()
+	}
+	return err
+}
+
+// joinLogs skips empty logs, so we don't return blank lines
+func joinLogs(logs []string) string {
+	var res []string
+	for _, l := range logs {
+		if l != "" {
+			res = append(res, l)
+		}
+	}
+	return strings.Join(res, "\n")
+}
@@ -0,0 +1,262 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestDecorator(t *testing.T) {
+	var help x.TestHelpers
+
+	a, b := help.MockMsg([]byte("a")), help.MockMsg([]byte("b"))
+	fail := help.MockMsg([]byte("fail"))
+	nested := mockBatch{msgs: []weave.Msg{a}}
+	tooMany := make([]weave.Msg, MaxBatchMessages+1)
+	for i := range tooMany {
+		tooMany[i] = a
+	}
+
+	cases := [...]struct {
+		msg     weave.Msg
+		check   func(error) bool // nil means no error
+		written [][]byte
+		missing [][]byte
+		data    [][]byte
+		log     string
+		tags    int
+		diff    int
+	}{
+		// normal msg passes through
+		0: {a, nil, [][]byte{[]byte("a")}, nil, nil, "a", 1, 1},
+		// normal msg fails as usual, without a savepoint
+		1: {fail, isHandlerErr, [][]byte{[]byte("fail")}, nil, nil, "", 0, 0},
+		// all messages are executed in order
+		2: {
+			mockBatch{msgs: []weave.Msg{a, b}},
+			nil,
+			[][]byte{[]byte("a"), []byte("b")},
+			nil,
+			[][]byte{[]byte("a"), []byte("b")},
+			"a\nb",
+			2,
+			2,
+		},
+		// any failure reverts the whole batch
+		3: {
+			mockBatch{msgs: []weave.Msg{a, b, fail}},
+			isHandlerErr,
+			nil,
+			[][]byte{[]byte("a"), []byte("b"), []byte("fail")},
+			nil, "", 0, 0,
+		},
+		// invalid batches are rejected
+		4: {mockBatch{}, IsInvalidBatchErr, nil, nil, nil, "", 0, 0},
+		5: {mockBatch{msgs: tooMany}, IsInvalidBatchErr, nil, nil, nil, "", 0, 0},
+		6: {mockBatch{msgs: []weave.Msg{a, nested}}, IsInvalidBatchErr,
+			nil, [][]byte{[]byte("a")}, nil, "", 0, 0},
+		7: {mockBatch{err: fmt.Errorf("bad")}, func(err error) bool { return err != nil },
+			nil, nil, nil, "", 0, 0},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			tx := help.MockTx(tc.msg)
+			d := NewDecorator()
+
+			// check and deliver must behave the same,
+			// but a batch checks on a cache that is thrown away
+			written, missing := tc.written, tc.missing
+			if _, ok := tc.msg.(mockBatch); ok {
+				written, missing = nil, append(tc.written, tc.missing...)
+			}
+			kv := store.MemStore()
+			cres, err := d.Check(ctx, kv, tx, echoHandler{})
+			assertResult(t, tc.check, err, kv, written, missing)
+			dkv := store.MemStore()
+			dres, err := d.Deliver(ctx, dkv, tx, echoHandler{})
+			assertResult(t, tc.check, err, dkv, tc.written, tc.missing)
+			if tc.check != nil {
+				return
+			}
+
+			assert.Equal(t, tc.log, cres.Log)
+			assert.Equal(t, tc.log, dres.Log)
+			assert.Equal(t, int64(len(tc.written)), cres.GasAllocated)
+			assert.Equal(t, int64(len(tc.written)), dres.GasUsed)
+			assert.Equal(t, tc.tags, len(dres.Tags))
+			assert.Equal(t, tc.diff, len(dres.Diff))
+			if tc.data != nil {
+				var ddata, cdata ByteArrayList
+				require.NoError(t, ddata.Unmarshal(dres.Data))
+				assert.Equal(t, tc.data, ddata.Elements)
+				require.NoError(t, cdata.Unmarshal(cres.Data))
+				assert.Equal(t, tc.data, cdata.Elements)
+			}
+		})
+	}
+}
+
+func TestDependentMessages(t *testing.T) {
+	var help x.TestHelpers
+	a, b := help.MockMsg([]byte("a")), help.MockMsg([]byte("b"))
+	d := NewDecorator()
+	h := dependHandler{}
+
+	// b needs a to be delivered first
+	tx := help.MockTx(mockBatch{msgs: []weave.Msg{a, b}})
+	kv := store.MemStore()
+	_, err := d.Check(context.Background(), kv, tx, h)
+	require.NoError(t, err)
+	assert.False(t, kv.Has([]byte("a")))
+	_, err = d.Deliver(context.Background(), kv, tx, h)
+	require.NoError(t, err)
+	assert.True(t, kv.Has([]byte("a")))
+	assert.True(t, kv.Has([]byte("b")))
+
+	// but not the other way round
+	tx = help.MockTx(mockBatch{msgs: []weave.Msg{b, a}})
+	_, err = d.Check(context.Background(), store.MemStore(), tx, h)
+	assert.True(t, isHandlerErr(err), "%+v", err)
+}
+
+func assertResult(t *testing.T, check func(error) bool, err error,
+	kv weave.ReadOnlyKVStore, written, missing [][]byte) {
+
+	if check == nil {
+		require.NoError(t, err)
+	} else {
+		require.True(t, check(err), "%+v", err)
+	}
+	for _, k := range written {
+		assert.True(t, kv.Has(k), "%s", k)
+	}
+	for _, k := range missing {
+		assert.False(t, kv.Has(k), "%s", k)
+	}
+}
+
+//------------------ test helpers ------------------
+
+var errHandler = fmt.Errorf("handler failed")
+
+func isHandlerErr(err error) bool {
+	return err == errHandler
+}
+
+// echoHandler writes the message bytes as key, and returns
+// them as data, log and tag. It fails after writing
+// if the message is "fail".
+type echoHandler struct{}
+
+var _ weave.Handler = echoHandler{}
+
+func (echoHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	bz, err := echo(store, tx)
+	if err != nil {
+		return weave.CheckResult{}, err
+	}
+	return weave.CheckResult{Data: bz, Log: string(bz), GasAllocated: 1}, nil
+}
+
+func (echoHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	bz, err := echo(store, tx)
+	if err != nil {
+		return weave.DeliverResult{}, err
+	}
+	return weave.DeliverResult{
+		Data:    bz,
+		Log:     string(bz),
+		Tags:    []common.KVPair{{Key: bz, Value: []byte("s")}},
+		Diff:    []abci.Validator{{Address: bz, Power: 1}},
+		GasUsed: 1,
+	}, nil
+}
+
+func echo(store weave.KVStore, tx weave.Tx) ([]byte, error) {
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	bz, err := msg.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	store.Set(bz, bz)
+	if string(bz) == "fail" {
+		return nil, errHandler
+	}
+	return bz, nil
+}
+
+// dependHandler only writes the message bytes on Deliver,
+// and rejects "b" until "a" is written
+type dependHandler struct{}
+
+var _ weave.Handler = dependHandler{}
+
+func (dependHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return weave.CheckResult{}, err
+	}
+	bz, err := msg.Marshal()
+	if err != nil {
+		return weave.CheckResult{}, err
+	}
+	if string(bz) == "b" && !store.Has([]byte("a")) {
+		return weave.CheckResult{}, errHandler
+	}
+	return weave.CheckResult{}, nil
+}
+
+func (h dependHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	_, err := h.Check(ctx, store, tx)
+	if err != nil {
+		return weave.DeliverResult{}, err
+	}
+	_, err = echo(store, tx)
+	return weave.DeliverResult{}, err
+}
+
+// mockBatch is a batch of the given messages
+type mockBatch struct {
+	msgs []weave.Msg
+	err  error
+}
+
+var _ Msg = mockBatch{}
+
+func (m mockBatch) Marshal() ([]byte, error) {
+	return nil, nil
+}
+
+func (m mockBatch) Unmarshal([]byte) error {
+	return nil
+}
+
+func (m mockBatch) Path() string {
+	return PathExecuteBatchMsg
+}
+
+func (m mockBatch) MsgList() ([]weave.Msg, error) {
+	return m.msgs, m.err
+}
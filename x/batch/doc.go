@@ -0,0 +1,16 @@
+/*
+Package batch allows a single tx to carry an ordered list of
+messages, which are executed atomically.
+
+The tx is authenticated and pays its fee once, as usual. The
+Decorator then unpacks the batch and passes each message on to
+the next handler (usually the Router), as if it was sent in a
+tx on its own. If any message fails, none of the changes are
+written. The results of all messages are combined into one.
+Each message sees the changes of the ones before it, both when
+the tx is checked and delivered.
+
+Applications define their own batch message type over the
+messages they support, and make it fulfill Msg.
+*/
+package batch
@@ -0,0 +1,35 @@
+package batch
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/batch reserves 50 ~ 59.
+const (
+	CodeInvalidBatch uint32 = 50
+)
+
+var (
+	errEmptyBatch      = fmt.Errorf("Batch contains no messages")
+	errTooManyMessages = fmt.Errorf("Too many messages in batch")
+	errNestedBatch     = fmt.Errorf("Batch may not contain a batch")
+)
+
+func ErrEmptyBatch() error {
+	return errors.WithCode(errEmptyBatch, CodeInvalidBatch)
+}
+func ErrTooManyMessages(count int) error {
+	msg := fmt.Sprintf("%d > %d", count, MaxBatchMessages)
+	return errors.WithLog(msg, errTooManyMessages, CodeInvalidBatch)
+}
+func ErrNestedBatch() error {
+	return errors.WithCode(errNestedBatch, CodeInvalidBatch)
+}
+
+// IsInvalidBatchErr matches all errors from a malformed batch
+func IsInvalidBatchErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidBatch)
+}
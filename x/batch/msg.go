@@ -0,0 +1,61 @@
+package batch
+
+import (
+	"github.com/confio/weave"
+)
+
+const (
+	// PathExecuteBatchMsg is the path to use for batch messages.
+	// They are handled by the Decorator, so nothing is
+	// registered on the Router for it.
+	PathExecuteBatchMsg = "batch/execute"
+
+	// MaxBatchMessages limits the work a single tx can request
+	MaxBatchMessages = 10
+)
+
+// Msg is implemented by the batch message type of an application,
+// which is usually a list over a union of all supported messages
+type Msg interface {
+	weave.Msg
+
+	// MsgList returns the messages to execute, in order
+	MsgList() ([]weave.Msg, error)
+}
+
+// Validate makes sure the batch is non-empty, not too large,
+// and does not contain another batch
+func Validate(msg Msg) ([]weave.Msg, error) {
+	msgs, err := msg.MsgList()
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, ErrEmptyBatch()
+	}
+	if len(msgs) > MaxBatchMessages {
+		return nil, ErrTooManyMessages(len(msgs))
+	}
+	for _, m := range msgs {
+		if _, ok := m.(Msg); ok {
+			return nil, ErrNestedBatch()
+		}
+	}
+	return msgs, nil
+}
+
+// Tx wraps the original tx to return one message of the batch.
+//
+// Only weave.Tx is exposed, so signatures and fees must be
+// handled by decorators before the batch is unpacked.
+type Tx struct {
+	weave.Tx
+	msg weave.Msg
+}
+
+var _ weave.Tx = Tx{}
+
+// GetMsg returns the current message of the batch
+func (t Tx) GetMsg() (weave.Msg, error) {
+	return t.msg, nil
+}
@@ -70,6 +70,20 @@ func (c BaseController) MoveCoins(store weave.KVStore,
 	return c.bucket.Save(store, recipient)
 }
 
+// MoveAll moves every coin in amount from src to dest,
+// with the MoveCoins of the controller
+func MoveAll(store weave.KVStore, control Controller,
+	src weave.Address, dest weave.Address, amount x.Coins) error {
+
+	for _, c := range amount {
+		err := control.MoveCoins(store, src, dest, *c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // IssueCoins attempts to add the given amount of coins to
 // the destination address. Fails if it overflows the wallet.
 //
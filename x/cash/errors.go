@@ -16,6 +16,7 @@ const (
 	CodeInvalidAmount            = 34
 	CodeInvalidMemo              = 35
 	CodeEmptyAccount             = 36
+	CodeBrokenState              = 37
 )
 
 var (
@@ -24,6 +25,8 @@ var (
 	errInvalidAmount     = fmt.Errorf("Invalid amount")
 	errInvalidMemo       = fmt.Errorf("Invalid memo")
 	errEmptyAccount      = fmt.Errorf("Account empty")
+	errSupplyMismatch    = fmt.Errorf("Wallets don't add up to the supply")
+	errInvalidWallets    = fmt.Errorf("Invalid wallets")
 )
 
 func ErrInsufficientFees(coin x.Coin) error {
@@ -61,3 +64,17 @@ func ErrEmptyAccount(addr weave.Address) error {
 func IsEmptyAccountErr(err error) bool {
 	return errors.IsSameError(errEmptyAccount, err)
 }
+
+func ErrSupplyMismatch(reason string) error {
+	return errors.WithLog(reason, errSupplyMismatch, CodeBrokenState)
+}
+func IsSupplyMismatchErr(err error) bool {
+	return errors.IsSameError(errSupplyMismatch, err)
+}
+
+func ErrInvalidWallets(reason string) error {
+	return errors.WithLog(reason, errInvalidWallets, CodeBrokenState)
+}
+func IsInvalidWalletsErr(err error) bool {
+	return errors.IsSameError(errInvalidWallets, err)
+}
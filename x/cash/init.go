@@ -14,10 +14,11 @@ type GenesisAccount struct {
 }
 
 // Initializer fulfils the InitStater interface to load data from
-// the genesis file
+// the genesis file, and can export the state in the same format
 type Initializer struct{}
 
 var _ weave.Initializer = Initializer{}
+var _ weave.Exporter = Initializer{}
 
 // FromGenesis will parse initial account info from genesis
 // and save it to the database
@@ -43,3 +44,20 @@ func (Initializer) FromGenesis(opts weave.Options, kv weave.KVStore) error {
 	}
 	return nil
 }
+
+// ToGenesis writes all wallets to the options,
+// so FromGenesis can load them again
+func (Initializer) ToGenesis(opts weave.Options, kv weave.ReadOnlyKVStore) error {
+	wallets, err := NewBucket().All(kv)
+	if err != nil {
+		return err
+	}
+	accts := make([]GenesisAccount, 0, len(wallets))
+	for _, w := range wallets {
+		accts = append(accts, GenesisAccount{
+			Address: w.Key(),
+			Set:     Set{Coins: AsCoinage(w).GetCoins()},
+		})
+	}
+	return opts.WriteOptions(optKey, accts)
+}
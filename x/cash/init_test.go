@@ -72,6 +72,42 @@ func TestInitState(t *testing.T) {
 	}
 }
 
+func TestExportState(t *testing.T) {
+	addr := weave.NewAddress([]byte("foo"))
+	addr2 := weave.NewAddress([]byte("bar"))
+	coins := Set{mustCombineCoins(x.NewCoin(100, 5, "ATM"), x.NewCoin(50, 0, "ETH"))}
+	coins2 := Set{mustCombineCoins(x.NewCoin(7, 0, "FOO"))}
+	accts := []GenesisAccount{{Address: addr, Set: coins}, {Address: addr2, Set: coins2}}
+	bz, err := json.Marshal(accts)
+	require.NoError(t, err)
+
+	init := Initializer{}
+	kv := store.MemStore()
+	err = init.FromGenesis(weave.Options{optKey: bz}, kv)
+	require.NoError(t, err)
+
+	// export and load into a fresh store
+	opts := weave.Options{}
+	err = init.ToGenesis(opts, kv)
+	require.NoError(t, err)
+	var exported []GenesisAccount
+	err = opts.ReadOptions(optKey, &exported)
+	require.NoError(t, err)
+	assert.Len(t, exported, 2)
+
+	kv2 := store.MemStore()
+	err = init.FromGenesis(opts, kv2)
+	require.NoError(t, err)
+	bucket := NewBucket()
+	for _, acct := range accts {
+		wallet, err := bucket.Get(kv2, acct.Address)
+		require.NoError(t, err)
+		if assert.NotNil(t, wallet) {
+			assert.EqualValues(t, acct.Coins, AsCoins(wallet))
+		}
+	}
+}
+
 // mustCombineCoins has one return value for tests...
 func mustCombineCoins(cs ...x.Coin) x.Coins {
 	s, err := x.CombineCoins(cs...)
@@ -0,0 +1,112 @@
+package cash
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/invariant"
+)
+
+// RegisterInvariants checks that all wallets are valid.
+//
+// cash cannot know what was issued, so the module that
+// issues coins should register a SupplyCheck as well
+func RegisterInvariants(r invariant.Registry) {
+	r.Register("cash/wallets", NewBucket().CheckWallets)
+}
+
+// CheckWallets requires all wallets to hold only positive,
+// sorted coins, and returns an error with the address of
+// every wallet that doesn't
+func (b Bucket) CheckWallets(db weave.ReadOnlyKVStore) error {
+	objs, err := b.All(db)
+	if err != nil {
+		return err
+	}
+	var bad []string
+	for _, obj := range objs {
+		coins := AsCoins(obj)
+		if coins.Validate() != nil || !coins.IsNonNegative() {
+			bad = append(bad, weave.Address(obj.Key()).String())
+		}
+	}
+	if len(bad) > 0 {
+		return ErrInvalidWallets(strings.Join(bad, ", "))
+	}
+	return nil
+}
+
+// TotalSupply adds up the coins in all wallets
+func (b Bucket) TotalSupply(db weave.ReadOnlyKVStore) (x.Coins, error) {
+	objs, err := b.All(db)
+	if err != nil {
+		return nil, err
+	}
+	var total x.Coins
+	for _, obj := range objs {
+		total, err = total.Combine(AsCoins(obj))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return total, nil
+}
+
+// SupplyCheck verifies that all wallets together hold exactly
+// the coins that were issued, as returned by issued. The error
+// names every currency that doesn't add up
+func SupplyCheck(wallets Bucket,
+	issued func(weave.ReadOnlyKVStore) (x.Coins, error)) invariant.Check {
+
+	return func(db weave.ReadOnlyKVStore) error {
+		want, err := issued(db)
+		if err != nil {
+			return err
+		}
+		have, err := wallets.TotalSupply(db)
+		if err != nil {
+			return err
+		}
+		bad := diffCoins(want, have)
+		if len(bad) > 0 {
+			return ErrSupplyMismatch(strings.Join(bad, ", "))
+		}
+		return nil
+	}
+}
+
+// diffCoins describes each currency that is not the
+// same in issued and held
+func diffCoins(issued, held x.Coins) []string {
+	byID := func(coins x.Coins) map[string]*x.Coin {
+		res := make(map[string]*x.Coin, len(coins))
+		for _, c := range coins {
+			res[c.ID()] = c
+		}
+		return res
+	}
+	want, have := byID(issued), byID(held)
+
+	ids := make([]string, 0, len(want)+len(have))
+	for id := range want {
+		ids = append(ids, id)
+	}
+	for id := range have {
+		if _, ok := want[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var res []string
+	for _, id := range ids {
+		w, h := want[id], have[id]
+		if w == nil || h == nil || !w.Equals(*h) {
+			res = append(res, fmt.Sprintf("%s issued {%v} held {%v}", id, w, h))
+		}
+	}
+	return res
+}
@@ -0,0 +1,72 @@
+package cash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestSupplyCheck(t *testing.T) {
+	coin := func(whole int64, ticker string) *x.Coin {
+		c := x.NewCoin(whole, 0, ticker)
+		return &c
+	}
+	a, b := weave.NewAddress([]byte("a")), weave.NewAddress([]byte("b"))
+
+	db := store.MemStore()
+	bucket := NewBucket()
+	for addr, coins := range map[string]x.Coins{
+		string(a): {coin(5, "BAR"), coin(10, "FOO")},
+		string(b): {coin(7, "FOO")},
+	} {
+		obj, err := WalletWith(weave.Address(addr), coins...)
+		require.NoError(t, err)
+		require.NoError(t, bucket.Save(db, obj))
+	}
+	assert.NoError(t, bucket.CheckWallets(db))
+
+	total, err := bucket.TotalSupply(db)
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{coin(5, "BAR"), coin(17, "FOO")}, total)
+
+	cases := map[string]struct {
+		issued x.Coins
+		bad    []string
+	}{
+		"all there":    {x.Coins{coin(5, "BAR"), coin(17, "FOO")}, nil},
+		"too much":     {x.Coins{coin(5, "BAR"), coin(16, "FOO")}, []string{"FOO"}},
+		"not issued":   {x.Coins{coin(17, "FOO")}, []string{"BAR"}},
+		"nobody holds": {x.Coins{coin(5, "BAR"), coin(1, "BAZ"), coin(17, "FOO")}, []string{"BAZ"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			issued := func(weave.ReadOnlyKVStore) (x.Coins, error) {
+				return tc.issued, nil
+			}
+			err := SupplyCheck(bucket, issued)(db)
+			if tc.bad == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.True(t, IsSupplyMismatchErr(err), "%+v", err)
+			for _, id := range tc.bad {
+				assert.Contains(t, err.Error(), id+" issued")
+			}
+		})
+	}
+
+	// a wallet with negative coins, written without validation
+	neg := &Set{Coins: x.Coins{coin(-1, "FOO")}}
+	bz, err := neg.Marshal()
+	require.NoError(t, err)
+	db.Set(bucket.DBKey(b), bz)
+	err = bucket.CheckWallets(db)
+	require.True(t, IsInvalidWalletsErr(err), "%+v", err)
+	assert.Contains(t, err.Error(), b.String())
+	assert.NotContains(t, err.Error(), a.String())
+}
@@ -0,0 +1,73 @@
+package currency
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+// Controller is a cash.Controller that only accepts coins of
+// registered currencies, and tracks their supply. Pass it to
+// all extensions that move coins, so nobody can send a
+// currency that was never registered.
+type Controller struct {
+	cash.Controller
+	wallets    cash.WalletBucket
+	currencies Bucket
+}
+
+var _ cash.Controller = Controller{}
+
+// NewController wraps a cash.BaseController on the wallets
+func NewController(wallets cash.WalletBucket) Controller {
+	return Controller{
+		Controller: cash.NewController(wallets),
+		wallets:    wallets,
+		currencies: NewBucket(),
+	}
+}
+
+// MoveCoins moves the given amount from src to dest,
+// if the currency is registered
+func (c Controller) MoveCoins(store weave.KVStore,
+	src weave.Address, dest weave.Address, amount x.Coin) error {
+
+	_, err := c.currencies.GetCurrency(store, amount.ID())
+	if err != nil {
+		return err
+	}
+	return c.Controller.MoveCoins(store, src, dest, amount)
+}
+
+// IssueCoins adds the given amount to dest, and to the supply
+// of the currency. It fails if the currency is not registered
+func (c Controller) IssueCoins(store weave.KVStore,
+	dest weave.Address, amount x.Coin) error {
+
+	err := c.currencies.AddSupply(store, amount)
+	if err != nil {
+		return err
+	}
+	return c.Controller.IssueCoins(store, dest, amount)
+}
+
+// BurnCoins removes the given amount from src, and from the
+// supply of the currency. It fails if src doesn't have enough
+func (c Controller) BurnCoins(store weave.KVStore,
+	src weave.Address, amount x.Coin) error {
+
+	if !amount.IsPositive() {
+		return cash.ErrInvalidAmount("Non-positive burn")
+	}
+	wallet, err := c.wallets.Get(store, src)
+	if err != nil {
+		return err
+	}
+	if wallet == nil {
+		return cash.ErrEmptyAccount(src)
+	}
+	if !cash.AsCoins(wallet).Contains(amount) {
+		return cash.ErrInsufficientFunds()
+	}
+	return c.IssueCoins(store, src, amount.Negative())
+}
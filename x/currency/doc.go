@@ -0,0 +1,26 @@
+/*
+Package currency keeps a registry of all currencies on the chain.
+
+Each ticker is registered once, with a name, the number of
+decimals to show, and the address of the issuer, which is the
+only one that can mint new coins and burn the coins it holds.
+The supply of every currency is tracked as coins are minted
+and burnt. A currency without an issuer has a fixed supply, and
+can only be set up in genesis.
+
+The Controller wraps the cash.Controller, and rejects moving
+any coin that is not registered. Pass it to all extensions
+instead of the plain one, so every send, fee and escrow only
+uses registered currencies.
+
+Currencies can also be set in the genesis file, after the
+cash wallets that hold them:
+
+	"currencies": [{
+	  "ticker": "MYC",
+	  "name": "My Coin",
+	  "decimals": 9,
+	  "issuer": "1234567890ABCDEF1234567890ABCDEF12345678"
+	}]
+*/
+package currency
@@ -0,0 +1,64 @@
+package currency
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/currency reserves 120 ~ 129.
+const (
+	CodeInvalidMsg        uint32 = 120
+	CodeNoSuchCurrency           = 121
+	CodeDuplicateCurrency        = 122
+	CodeNoIssuer                 = 123
+)
+
+var (
+	errNoSuchCurrency    = fmt.Errorf("Currency not registered")
+	errDuplicateCurrency = fmt.Errorf("Currency already registered")
+	errNoIssuer          = fmt.Errorf("Currency has a fixed supply")
+
+	errInvalidTicker   = fmt.Errorf("Invalid ticker")
+	errInvalidName     = fmt.Errorf("Invalid currency name")
+	errInvalidDecimals = fmt.Errorf("Invalid decimals")
+)
+
+func ErrNoSuchCurrency(ticker string) error {
+	return errors.WithLog(ticker, errNoSuchCurrency, CodeNoSuchCurrency)
+}
+func IsNoSuchCurrencyErr(err error) bool {
+	return errors.IsSameError(errNoSuchCurrency, err)
+}
+
+func ErrDuplicateCurrency(ticker string) error {
+	return errors.WithLog(ticker, errDuplicateCurrency, CodeDuplicateCurrency)
+}
+func IsDuplicateCurrencyErr(err error) bool {
+	return errors.IsSameError(errDuplicateCurrency, err)
+}
+
+func ErrNoIssuer(ticker string) error {
+	return errors.WithLog(ticker, errNoIssuer, CodeNoIssuer)
+}
+func IsNoIssuerErr(err error) bool {
+	return errors.IsSameError(errNoIssuer, err)
+}
+
+//------ invalid messages ----
+// all will match IsInvalidMsgErr
+
+func ErrInvalidTicker(ticker string) error {
+	return errors.WithLog(ticker, errInvalidTicker, CodeInvalidMsg)
+}
+func ErrInvalidName(name string) error {
+	return errors.WithLog(name, errInvalidName, CodeInvalidMsg)
+}
+func ErrInvalidDecimals(decimals int32) error {
+	msg := fmt.Sprintf("%d", decimals)
+	return errors.WithLog(msg, errInvalidDecimals, CodeInvalidMsg)
+}
+func IsInvalidMsgErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidMsg)
+}
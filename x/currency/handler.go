@@ -0,0 +1,245 @@
+package currency
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth x.Authenticator,
+	control Controller) {
+
+	bucket := NewBucket()
+	r.Handle(pathRegisterCurrencyMsg, RegisterCurrencyHandler{auth, bucket})
+	r.Handle(pathMintMsg, MintHandler{auth, bucket, control})
+	r.Handle(pathBurnMsg, BurnHandler{auth, bucket, control})
+}
+
+// RegisterQuery will register this bucket as "/currencies",
+// and the index as "/currencies/issuer"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("currencies", qr)
+}
+
+//---- register
+
+// RegisterCurrencyHandler adds a new ticker, whoever
+// comes first gets it
+type RegisterCurrencyHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = RegisterCurrencyHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h RegisterCurrencyHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += registerCurrencyCost
+	return res, nil
+}
+
+// Deliver stores the currency, with no supply
+func (h RegisterCurrencyHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, issuer, err := h.validate(ctx, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.bucket.Create(db, msg.Ticker, &Currency{
+		Name:     msg.Name,
+		Decimals: msg.Decimals,
+		Issuer:   issuer,
+	})
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the issuer
+func (h RegisterCurrencyHandler) validate(ctx weave.Context,
+	tx weave.Tx) (*RegisterCurrencyMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*RegisterCurrencyMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the issuer defaults to the main signer, and must have signed.
+	// currencies with a fixed supply can only come from genesis
+	issuer := weave.Address(msg.Issuer)
+	if len(issuer) == 0 {
+		signer := x.MainSigner(ctx, h.auth)
+		if signer == nil {
+			return nil, nil, errors.ErrUnauthorized()
+		}
+		issuer = signer.Address()
+	}
+	if !h.auth.HasAddress(ctx, issuer) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	return msg, issuer, nil
+}
+
+//---- mint
+
+// MintHandler lets the issuer create new coins
+type MintHandler struct {
+	auth    x.Authenticator
+	bucket  Bucket
+	control Controller
+}
+
+var _ weave.Handler = MintHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h MintHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += mintCost
+	return res, nil
+}
+
+// Deliver adds the coins to the recipient and the supply
+func (h MintHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	err = h.control.IssueCoins(db, msg.Recipient, *msg.Amount)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h MintHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*MintMsg, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*MintMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	_, err = checkIssuer(ctx, db, h.auth, h.bucket, msg.Amount.ID())
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+//---- burn
+
+// BurnHandler lets the issuer destroy coins it holds
+type BurnHandler struct {
+	auth    x.Authenticator
+	bucket  Bucket
+	control Controller
+}
+
+var _ weave.Handler = BurnHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h BurnHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += burnCost
+	return res, nil
+}
+
+// Deliver removes the coins from the issuer and the supply
+func (h BurnHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, issuer, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	err = h.control.BurnCoins(db, issuer, *msg.Amount)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the issuer
+func (h BurnHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*BurnMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*BurnMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	issuer, err := checkIssuer(ctx, db, h.auth, h.bucket, msg.Amount.ID())
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, issuer, nil
+}
+
+// checkIssuer makes sure the currency exists, has an issuer,
+// and the issuer signed the tx
+func checkIssuer(ctx weave.Context, db weave.KVStore, auth x.Authenticator,
+	bucket Bucket, id string) (weave.Address, error) {
+
+	c, err := bucket.GetCurrency(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.Issuer) == 0 {
+		return nil, ErrNoIssuer(id)
+	}
+	if !auth.HasAddress(ctx, c.Issuer) {
+		return nil, errors.ErrUnauthorized()
+	}
+	return c.Issuer, nil
+}
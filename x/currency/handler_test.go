@@ -0,0 +1,139 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/invariant"
+	"github.com/confio/weave/x/testhelpers"
+)
+
+// currencyTest runs the currency handlers on a new store
+type currencyTest struct {
+	*testhelpers.HandlerTest
+	t       *testing.T
+	control Controller
+}
+
+func newCurrencyTest(t *testing.T) *currencyTest {
+	s := &currencyTest{
+		HandlerTest: testhelpers.NewHandlerTest(),
+		t:           t,
+		control:     NewController(cash.NewBucket()),
+	}
+	RegisterRoutes(s, s.Auth, s.control)
+	return s
+}
+
+// run checks and delivers the msg, signed by the signers
+func (s *currencyTest) run(msg weave.Msg, signers ...weave.Condition) error {
+	_, err := s.Run(1, msg, signers...)
+	return err
+}
+
+func (s *currencyTest) supply(ticker string) *x.Coin {
+	c, err := NewBucket().GetCurrency(s.DB, ticker)
+	require.NoError(s.t, err)
+	return c.Supply
+}
+
+func TestRegisterCurrency(t *testing.T) {
+	s := newCurrencyTest(t)
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	b := weave.NewCondition("sig", "ed25519", []byte{4, 5, 6})
+
+	cases := []struct {
+		msg     *RegisterCurrencyMsg
+		signers []weave.Condition
+		check   func(error) bool
+	}{
+		{&RegisterCurrencyMsg{Ticker: "foo", Name: "Foo"}, nil, IsInvalidMsgErr},
+		{&RegisterCurrencyMsg{Ticker: "FOO"}, nil, IsInvalidMsgErr},
+		{&RegisterCurrencyMsg{Ticker: "FOO", Name: "Foo", Decimals: 10}, nil, IsInvalidMsgErr},
+		// issuer must sign
+		{&RegisterCurrencyMsg{Ticker: "FOO", Name: "Foo", Issuer: b.Address()},
+			[]weave.Condition{a}, errors.IsUnauthorizedErr},
+		// defaults to the signer
+		{&RegisterCurrencyMsg{Ticker: "FOO", Name: "Foo", Decimals: 6},
+			[]weave.Condition{a}, nil},
+		{&RegisterCurrencyMsg{Ticker: "FOO", Name: "Foo"},
+			[]weave.Condition{b}, IsDuplicateCurrencyErr},
+		// someone must issue it
+		{&RegisterCurrencyMsg{Ticker: "BAR", Name: "Bar"}, nil, errors.IsUnauthorizedErr},
+	}
+	for i, tc := range cases {
+		err := s.run(tc.msg, tc.signers...)
+		if tc.check == nil {
+			assert.NoError(t, err, "%d", i)
+		} else {
+			assert.True(t, tc.check(err), "%d: %+v", i, err)
+		}
+	}
+
+	foo, err := NewBucket().GetCurrency(s.DB, "FOO")
+	require.NoError(t, err)
+	assert.Equal(t, a.Address(), weave.Address(foo.Issuer))
+	assert.EqualValues(t, 6, foo.Decimals)
+	zero := x.NewCoin(0, 0, "FOO")
+	assert.Equal(t, &zero, foo.Supply)
+}
+
+func TestMintAndBurn(t *testing.T) {
+	s := newCurrencyTest(t)
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	b := weave.NewCondition("sig", "ed25519", []byte{4, 5, 6})
+	issuer, holder := a.Address(), b.Address()
+	coin := func(whole int64, ticker string) *x.Coin {
+		c := x.NewCoin(whole, 0, ticker)
+		return &c
+	}
+
+	require.NoError(t, s.run(&RegisterCurrencyMsg{Ticker: "FOO", Name: "Foo"}, a))
+	// a fixed supply, like from genesis
+	require.NoError(t, NewBucket().Create(s.DB, "BAR", &Currency{Name: "Bar"}))
+
+	// only the issuer can mint registered currencies
+	err := s.run(&MintMsg{Recipient: holder, Amount: coin(10, "FOO")}, b)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	err = s.run(&MintMsg{Recipient: holder, Amount: coin(10, "BAR")}, a)
+	assert.True(t, IsNoIssuerErr(err), "%+v", err)
+	err = s.run(&MintMsg{Recipient: holder, Amount: coin(10, "ETH")}, a)
+	assert.True(t, IsNoSuchCurrencyErr(err), "%+v", err)
+	err = s.run(&MintMsg{Recipient: holder, Amount: coin(-10, "FOO")}, a)
+	assert.True(t, cash.IsInvalidAmountErr(err), "%+v", err)
+
+	require.NoError(t, s.run(&MintMsg{Recipient: holder, Amount: coin(10, "FOO")}, a))
+	require.NoError(t, s.run(&MintMsg{Recipient: issuer, Amount: coin(5, "FOO")}, a))
+	assert.Equal(t, x.Coins{coin(10, "FOO")}, testhelpers.Balance(s.DB, holder))
+	assert.Equal(t, coin(15, "FOO"), s.supply("FOO"))
+
+	// only registered currencies can move
+	err = s.control.MoveCoins(s.DB, holder, issuer, *coin(1, "ETH"))
+	assert.True(t, IsNoSuchCurrencyErr(err), "%+v", err)
+	require.NoError(t, s.control.MoveCoins(s.DB, holder, issuer, *coin(1, "FOO")))
+	assert.Equal(t, coin(15, "FOO"), s.supply("FOO"))
+
+	// the issuer can only burn what it holds
+	err = s.run(&BurnMsg{Amount: coin(7, "FOO")}, a)
+	assert.True(t, cash.IsInsufficientFundsErr(err), "%+v", err)
+	err = s.run(&BurnMsg{Amount: coin(6, "FOO")}, b)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	require.NoError(t, s.run(&BurnMsg{Amount: coin(6, "FOO")}, a))
+	assert.Nil(t, testhelpers.Balance(s.DB, issuer))
+	assert.Equal(t, coin(9, "FOO"), s.supply("FOO"))
+
+	// the supply adds up, unless someone bypasses it
+	checker := invariant.NewChecker().RegisterAll(RegisterInvariants)
+	assert.NoError(t, checker.CheckInvariants(s.DB))
+	err = cash.NewController(cash.NewBucket()).IssueCoins(s.DB, holder, *coin(1, "FOO"))
+	require.NoError(t, err)
+	err = checker.CheckInvariants(s.DB)
+	assert.True(t, invariant.IsViolationErr(err), "%+v", err)
+	assert.Contains(t, err.Error(), "currency/supply")
+}
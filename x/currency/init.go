@@ -0,0 +1,93 @@
+package currency
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+const optKey = "currencies"
+
+// GenesisCurrency is used to parse the json from genesis file
+// use weave.Address, so the issuer is in hex, not base64
+type GenesisCurrency struct {
+	Ticker   string        `json:"ticker"`
+	Name     string        `json:"name"`
+	Decimals int32         `json:"decimals"`
+	Issuer   weave.Address `json:"issuer,omitempty"`
+}
+
+// Initializer fulfils the InitStater interface to load data from
+// the genesis file, and can export the state in the same format
+//
+// The supply is the sum of all wallets from genesis, so this must
+// run after cash.Initializer.
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+var _ weave.Exporter = Initializer{}
+
+// FromGenesis registers all currencies from genesis, with
+// the coins already in the wallets as supply
+func (Initializer) FromGenesis(opts weave.Options, kv weave.KVStore) error {
+	var currencies []GenesisCurrency
+	err := opts.ReadOptions(optKey, &currencies)
+	if err != nil || len(currencies) == 0 {
+		return err
+	}
+
+	supply, err := sumWallets(kv)
+	if err != nil {
+		return err
+	}
+	bucket := NewBucket()
+	for _, c := range currencies {
+		total := x.NewCoin(0, 0, c.Ticker)
+		if s, ok := supply[c.Ticker]; ok {
+			total = s
+		}
+		err := bucket.Create(kv, c.Ticker, &Currency{
+			Name:     c.Name,
+			Decimals: c.Decimals,
+			Issuer:   c.Issuer,
+			Supply:   &total,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sumWallets adds up the coins of all wallets by ticker
+func sumWallets(kv weave.KVStore) (map[string]x.Coin, error) {
+	total, err := cash.NewBucket().TotalSupply(kv)
+	if err != nil {
+		return nil, err
+	}
+	supply := make(map[string]x.Coin, len(total))
+	for _, c := range total {
+		supply[c.ID()] = *c
+	}
+	return supply, nil
+}
+
+// ToGenesis writes all currencies to the options.
+// The supply is not needed, as it is in the wallets
+func (Initializer) ToGenesis(opts weave.Options, kv weave.ReadOnlyKVStore) error {
+	objs, err := NewBucket().All(kv)
+	if err != nil || len(objs) == 0 {
+		return err
+	}
+	currencies := make([]GenesisCurrency, len(objs))
+	for i, obj := range objs {
+		c := AsCurrency(obj)
+		currencies[i] = GenesisCurrency{
+			Ticker:   string(obj.Key()),
+			Name:     c.Name,
+			Decimals: c.Decimals,
+			Issuer:   c.Issuer,
+		}
+	}
+	return opts.WriteOptions(optKey, currencies)
+}
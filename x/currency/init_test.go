@@ -0,0 +1,59 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+func TestGenesis(t *testing.T) {
+	var init Initializer
+	db := store.MemStore()
+
+	// nothing to load or export
+	require.NoError(t, init.FromGenesis(weave.Options{}, db))
+	opts := weave.Options{}
+	require.NoError(t, init.ToGenesis(opts, db))
+	assert.Empty(t, opts)
+
+	// the supply is what the wallets hold
+	wallets := cash.NewBucket()
+	one, two := x.NewCoin(1, 5, "FOO"), x.NewCoin(2, 0, "FOO")
+	for i, c := range []x.Coin{one, two} {
+		obj, err := cash.WalletWith(weave.NewAddress([]byte{byte(i)}), &c)
+		require.NoError(t, err)
+		require.NoError(t, wallets.Save(db, obj))
+	}
+
+	issuer := weave.NewAddress([]byte("issuer"))
+	currencies := []byte(`[{"ticker":"FOO","name":"Foo","decimals":2,"issuer":"` +
+		issuer.String() + `"},{"ticker":"BAR","name":"Bar","decimals":0}]`)
+	require.NoError(t, init.FromGenesis(weave.Options{optKey: currencies}, db))
+
+	foo, err := NewBucket().GetCurrency(db, "FOO")
+	require.NoError(t, err)
+	supply := x.NewCoin(3, 5, "FOO")
+	assert.Equal(t, &Currency{Name: "Foo", Decimals: 2, Issuer: issuer, Supply: &supply}, foo)
+	bar, err := NewBucket().GetCurrency(db, "BAR")
+	require.NoError(t, err)
+	assert.True(t, bar.Supply.IsZero())
+
+	// no duplicates
+	err = init.FromGenesis(weave.Options{optKey: currencies}, db)
+	assert.True(t, IsDuplicateCurrencyErr(err), "%+v", err)
+
+	// export what we loaded, in order of ticker
+	require.NoError(t, init.ToGenesis(opts, db))
+	var exported []GenesisCurrency
+	require.NoError(t, opts.ReadOptions(optKey, &exported))
+	assert.Equal(t, []GenesisCurrency{
+		{Ticker: "BAR", Name: "Bar"},
+		{Ticker: "FOO", Name: "Foo", Decimals: 2, Issuer: issuer},
+	}, exported)
+}
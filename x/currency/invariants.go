@@ -0,0 +1,14 @@
+package currency
+
+import (
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/invariant"
+)
+
+// RegisterInvariants checks that the wallets hold exactly the
+// supply of all currencies, and nothing unregistered
+func RegisterInvariants(r invariant.Registry) {
+	bucket := NewBucket()
+	r.Register("currency/supply", cash.SupplyCheck(cash.NewBucket(), bucket.Supply))
+	r.Register("currency/indexes", bucket.CheckIndexes)
+}
@@ -0,0 +1,189 @@
+package currency
+
+import (
+	"errors"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+const (
+	// BucketName is where we store the currencies
+	BucketName = "currency"
+	// IssuerIndex is the index of currencies by issuer
+	IssuerIndex = "issuer"
+
+	maxNameLength = 32
+	// maxDecimals is the precision of the fractional part of a coin
+	maxDecimals = 9
+)
+
+//---- Currency
+
+var _ orm.CloneableData = (*Currency)(nil)
+
+// Validate requires a name, valid decimals, and a supply
+// that is not negative
+func (c *Currency) Validate() error {
+	if err := validateInfo(c.Name, c.Decimals, c.Issuer); err != nil {
+		return err
+	}
+	if c.Supply == nil {
+		return cash.ErrInvalidAmount("Missing supply")
+	}
+	if err := c.Supply.Validate(); err != nil {
+		return err
+	}
+	if !c.Supply.IsNonNegative() {
+		return x.ErrOutOfRange(*c.Supply)
+	}
+	return nil
+}
+
+// Copy makes a new Currency with the same data
+func (c *Currency) Copy() orm.CloneableData {
+	res := &Currency{
+		Name:     c.Name,
+		Decimals: c.Decimals,
+		Issuer:   c.Issuer,
+	}
+	if c.Supply != nil {
+		res.Supply = c.Supply.Clone()
+	}
+	return res
+}
+
+// validateInfo checks the metadata shared by
+// Currency and RegisterCurrencyMsg
+func validateInfo(name string, decimals int32, issuer []byte) error {
+	if name == "" || len(name) > maxNameLength {
+		return ErrInvalidName(name)
+	}
+	if decimals < 0 || decimals > maxDecimals {
+		return ErrInvalidDecimals(decimals)
+	}
+	if len(issuer) > 0 {
+		return weave.Address(issuer).Validate()
+	}
+	return nil
+}
+
+//-------------------- Object Wrapper -------
+
+// AsCurrency will safely type-cast any value from Bucket
+func AsCurrency(obj orm.Object) *Currency {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Currency)
+}
+
+//--- Bucket - type-safe bucket
+
+// Bucket stores the currencies by ticker
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name,
+// and all indexes
+func NewBucket() Bucket {
+	proto := orm.NewSimpleObj(nil, new(Currency))
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName, proto).
+			WithIndex(IssuerIndex, idxIssuer, false),
+	}
+}
+
+// Create saves a new currency with no supply, or returns
+// an error if the ticker is taken
+func (b Bucket) Create(db weave.KVStore, ticker string,
+	c *Currency) error {
+
+	if !x.IsCC(ticker) {
+		return ErrInvalidTicker(ticker)
+	}
+	obj, err := b.Get(db, []byte(ticker))
+	if err != nil {
+		return err
+	}
+	if obj != nil {
+		return ErrDuplicateCurrency(ticker)
+	}
+	if c.Supply == nil {
+		supply := x.NewCoin(0, 0, ticker)
+		c.Supply = &supply
+	}
+	return b.Save(db, orm.NewSimpleObj([]byte(ticker), c))
+}
+
+// GetCurrency loads the currency with the ID of a coin,
+// or returns an error if it is not registered
+func (b Bucket) GetCurrency(db weave.ReadOnlyKVStore, id string) (*Currency, error) {
+	obj, err := b.Get(db, []byte(id))
+	if err != nil {
+		return nil, err
+	}
+	c := AsCurrency(obj)
+	if c == nil {
+		return nil, ErrNoSuchCurrency(id)
+	}
+	return c, nil
+}
+
+// AddSupply changes the supply of the currency of amount,
+// which may be negative
+func (b Bucket) AddSupply(db weave.KVStore, amount x.Coin) error {
+	c, err := b.GetCurrency(db, amount.ID())
+	if err != nil {
+		return err
+	}
+	supply, err := c.Supply.Add(amount)
+	if err != nil {
+		return err
+	}
+	c.Supply = &supply
+	return b.Save(db, orm.NewSimpleObj([]byte(amount.ID()), c))
+}
+
+// Supply returns the supply of all currencies
+func (b Bucket) Supply(db weave.ReadOnlyKVStore) (x.Coins, error) {
+	objs, err := b.All(db)
+	if err != nil {
+		return nil, err
+	}
+	var total x.Coins
+	for _, obj := range objs {
+		supply := AsCurrency(obj).Supply
+		// Coins.Add drops everything on zero
+		if x.IsEmpty(supply) {
+			continue
+		}
+		total, err = total.Add(*supply)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return total, nil
+}
+
+// Save enforces the proper type
+func (b Bucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Currency); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+func idxIssuer(obj orm.Object) ([]byte, error) {
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	c, ok := obj.Value().(*Currency)
+	if !ok {
+		return nil, errors.New("Can only take index of Currency")
+	}
+	return c.Issuer, nil
+}
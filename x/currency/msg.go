@@ -0,0 +1,66 @@
+package currency
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+// Ensure we implement the Msg interface
+var _ weave.Msg = (*RegisterCurrencyMsg)(nil)
+var _ weave.Msg = (*MintMsg)(nil)
+var _ weave.Msg = (*BurnMsg)(nil)
+
+const (
+	pathRegisterCurrencyMsg = "currency/register"
+	pathMintMsg             = "currency/mint"
+	pathBurnMsg             = "currency/burn"
+
+	registerCurrencyCost int64 = 500
+	mintCost             int64 = 100
+	burnCost             int64 = 100
+)
+
+// Path returns the routing path for this message
+func (RegisterCurrencyMsg) Path() string {
+	return pathRegisterCurrencyMsg
+}
+
+// Validate makes sure that this is sensible.
+// issuer is optional, as it defaults to the signer
+func (m *RegisterCurrencyMsg) Validate() error {
+	if !x.IsCC(m.Ticker) {
+		return ErrInvalidTicker(m.Ticker)
+	}
+	return validateInfo(m.Name, m.Decimals, m.Issuer)
+}
+
+// Path returns the routing path for this message
+func (MintMsg) Path() string {
+	return pathMintMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *MintMsg) Validate() error {
+	if err := weave.Address(m.Recipient).Validate(); err != nil {
+		return err
+	}
+	return validateAmount(m.Amount)
+}
+
+// Path returns the routing path for this message
+func (BurnMsg) Path() string {
+	return pathBurnMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *BurnMsg) Validate() error {
+	return validateAmount(m.Amount)
+}
+
+func validateAmount(amount *x.Coin) error {
+	if amount == nil || !amount.IsPositive() {
+		return cash.ErrInvalidAmount("Non-positive amount")
+	}
+	return amount.Validate()
+}
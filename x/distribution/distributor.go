@@ -0,0 +1,238 @@
+package distribution
+
+import (
+	"fmt"
+	"math/big"
+
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/crypto"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+// Distributor pays out all fees collected in a block at the
+// end of it. The proposer first gets its bonus, the rest is
+// split by power among the validators that signed the last
+// block. Any remainder from rounding goes to the proposer
+// as well.
+//
+// The signers are those BaseApp got in BeginBlock, with the
+// power they had in that block. Without any signer or
+// proposer, the fees wait for the next block.
+type Distributor struct {
+	wallets cash.Bucket
+	control cash.Controller
+	records Bucket
+	// percent of the fees the proposer gets on top of its share
+	proposerBonus int64
+	// number of blocks we keep the records
+	keepRecords int64
+}
+
+var _ weave.EndBlocker = Distributor{}
+
+// DefaultKeepRecords is the number of blocks we keep the
+// Distribution records
+const DefaultKeepRecords = 1000
+
+// NewDistributor pays out the fees held in the wallets,
+// with proposerBonus percent going to the block proposer.
+// panics if proposerBonus is not a percent
+func NewDistributor(wallets cash.Bucket, proposerBonus int64) Distributor {
+	if proposerBonus < 0 || proposerBonus > 100 {
+		panic(fmt.Sprintf("Invalid proposer bonus: %d", proposerBonus))
+	}
+	return Distributor{
+		wallets:       wallets,
+		control:       cash.NewController(wallets),
+		records:       NewBucket(),
+		proposerBonus: proposerBonus,
+		keepRecords:   DefaultKeepRecords,
+	}
+}
+
+// WithHistory returns a copy of the Distributor, that deletes
+// the records once they are older than blocks.
+// 0 keeps them forever.
+func (d Distributor) WithHistory(blocks int64) Distributor {
+	if blocks < 0 {
+		panic(fmt.Sprintf("Invalid history: %d", blocks))
+	}
+	d.keepRecords = blocks
+	return d
+}
+
+// EndBlock moves all fees from the collector to the validators,
+// and records how they were split. It deletes the record
+// that just left the history.
+func (d Distributor) EndBlock(ctx weave.Context, db weave.KVStore) (weave.EndBlockResult, error) {
+	var res weave.EndBlockResult
+	height, _ := weave.GetHeight(ctx)
+	if d.keepRecords > 0 && height > d.keepRecords {
+		// we run every block, so this never keeps
+		// more than the history
+		err := d.records.Delete(db, heightKey(height-d.keepRecords))
+		if err != nil {
+			return res, err
+		}
+	}
+
+	obj, err := d.wallets.Get(db, CollectorAddress)
+	if err != nil {
+		return res, err
+	}
+	fees := cash.AsCoins(obj)
+	if fees.IsEmpty() {
+		return res, nil
+	}
+
+	vals := signers(ctx)
+	var proposer weave.Address
+	header, _ := weave.GetHeader(ctx)
+	if len(header.Proposer.PubKey.Data) > 0 {
+		proposer = ValidatorAddress(header.Proposer.PubKey)
+	}
+	if len(vals) == 0 && proposer == nil {
+		return res, nil
+	}
+
+	payouts := newPayouts(proposer)
+	for _, fee := range fees {
+		payouts.split(*fee, vals, d.proposerBonus)
+	}
+	for _, p := range payouts.list {
+		err := cash.MoveAll(db, d.control, CollectorAddress, p.Recipient, p.Amount)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	record := &Distribution{
+		Height:   height,
+		Proposer: proposer,
+		Fees:     fees.Clone(),
+		Payouts:  payouts.list,
+	}
+	err = d.records.Save(db, orm.NewSimpleObj(heightKey(height), record))
+	return res, err
+}
+
+// signers returns the validators that signed the last
+// block, with the power they had then
+func signers(ctx weave.Context) []abci.Validator {
+	commit, _ := weave.GetLastCommit(ctx)
+	var vals []abci.Validator
+	for _, v := range commit {
+		if v.SignedLastBlock && len(v.Validator.PubKey.Data) > 0 {
+			vals = append(vals, v.Validator)
+		}
+	}
+	return vals
+}
+
+// ValidatorAddress is where a validator gets paid, the same
+// address a signature with the validator key has
+func ValidatorAddress(pubkey abci.PubKey) weave.Address {
+	return weave.NewCondition(crypto.ExtensionName, pubkey.Type, pubkey.Data).Address()
+}
+
+// payouts adds up what each recipient gets,
+// in the order they were first paid
+type payouts struct {
+	proposer weave.Address
+	list     []*Payout
+	index    map[string]*Payout
+}
+
+func newPayouts(proposer weave.Address) *payouts {
+	return &payouts{
+		proposer: proposer,
+		index:    make(map[string]*Payout),
+	}
+}
+
+// split divides one fee among the proposer and validators
+func (p *payouts) split(fee x.Coin, vals []abci.Validator, bonus int64) {
+	total := toUnits(fee)
+	left := new(big.Int).Set(total)
+
+	// the proposer gets everything if we don't know
+	// the validators
+	if p.proposer != nil {
+		cut := new(big.Int).Mul(total, big.NewInt(bonus))
+		cut.Quo(cut, big.NewInt(100))
+		if len(vals) == 0 {
+			cut.Set(total)
+		}
+		p.add(p.proposer, fee, cut)
+		left.Sub(left, cut)
+	}
+
+	var power int64
+	for _, v := range vals {
+		power += v.Power
+	}
+	if power > 0 {
+		rest := new(big.Int).Set(left)
+		for _, v := range vals {
+			share := new(big.Int).Mul(rest, big.NewInt(v.Power))
+			share.Quo(share, big.NewInt(power))
+			p.add(ValidatorAddress(v.PubKey), fee, share)
+			left.Sub(left, share)
+		}
+	}
+
+	// rounding errors, they wait for the next block
+	// if there is no proposer
+	if p.proposer != nil {
+		p.add(p.proposer, fee, left)
+	}
+}
+
+// add pays units of the currency of fee to addr
+func (p *payouts) add(addr weave.Address, fee x.Coin, units *big.Int) {
+	if units.Sign() <= 0 {
+		return
+	}
+	payout, ok := p.index[string(addr)]
+	if !ok {
+		payout = &Payout{Recipient: addr}
+		p.index[string(addr)] = payout
+		p.list = append(p.list, payout)
+	}
+	coins, err := x.Coins(payout.Amount).Add(fromUnits(units, fee))
+	if err != nil {
+		// we only add parts of valid coins
+		panic(err)
+	}
+	payout.Amount = coins
+}
+
+var fracUnit = big.NewInt(x.FracUnit)
+
+// toUnits returns the value of the coin in fractional units,
+// which may not fit into an int64
+func toUnits(c x.Coin) *big.Int {
+	units := new(big.Int).Mul(big.NewInt(c.Whole), fracUnit)
+	return units.Add(units, big.NewInt(c.Fractional))
+}
+
+// fromUnits is the inverse of toUnits, with the currency of c
+func fromUnits(units *big.Int, c x.Coin) x.Coin {
+	whole, frac := new(big.Int).QuoRem(units, fracUnit, new(big.Int))
+	return x.Coin{
+		Ticker:     c.Ticker,
+		Issuer:     c.Issuer,
+		Whole:      whole.Int64(),
+		Fractional: frac.Int64(),
+	}
+}
+
+// RegisterQuery will register the records as "/distributions",
+// keyed by the height of the block
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("distributions", qr)
+}
@@ -0,0 +1,173 @@
+package distribution
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+func TestDistributor(t *testing.T) {
+	val := func(key string, power int64) abci.Validator {
+		return abci.Validator{
+			PubKey: abci.PubKey{Type: "ed25519", Data: []byte(key)},
+			Power:  power,
+		}
+	}
+	coin := func(whole, frac int64, ticker string) *x.Coin {
+		c := x.NewCoin(whole, frac, ticker)
+		return &c
+	}
+	a, b := val("aaa", 3), val("bbb", 1)
+	addrA, addrB := ValidatorAddress(a.PubKey), ValidatorAddress(b.PubKey)
+
+	cases := map[string]struct {
+		fees     []*x.Coin
+		vals     []abci.Validator
+		proposer *abci.Validator
+		// did not sign the last block
+		offline *abci.Validator
+		// expected balances after the block, nil if none
+		collector x.Coins
+		balanceA  x.Coins
+		balanceB  x.Coins
+	}{
+		"no fees": {
+			vals:     []abci.Validator{a, b},
+			proposer: &a,
+		},
+		"nobody to pay": {
+			fees:      []*x.Coin{coin(10, 0, "FOO")},
+			collector: x.Coins{coin(10, 0, "FOO")},
+		},
+		"proposer gets bonus and remainder": {
+			fees:     []*x.Coin{coin(0, 1, "BAR"), coin(100, 0, "FOO")},
+			vals:     []abci.Validator{a, b},
+			proposer: &a,
+			balanceA: x.Coins{coin(0, 1, "BAR"), coin(80, 0, "FOO")},
+			balanceB: x.Coins{coin(20, 0, "FOO")},
+		},
+		"only proposer known": {
+			fees:     []*x.Coin{coin(7, 5, "FOO")},
+			proposer: &b,
+			balanceB: x.Coins{coin(7, 5, "FOO")},
+		},
+		"only signers are paid": {
+			fees:     []*x.Coin{coin(10, 0, "FOO")},
+			vals:     []abci.Validator{a, b},
+			offline:  &b,
+			proposer: &b,
+			balanceA: x.Coins{coin(8, 0, "FOO")},
+			balanceB: x.Coins{coin(2, 0, "FOO")},
+		},
+		"remainder waits without proposer": {
+			fees:      []*x.Coin{coin(1, 0, "FOO")},
+			vals:      []abci.Validator{a, val("bbb", 6)},
+			collector: x.Coins{coin(0, 1, "FOO")},
+			balanceA:  x.Coins{coin(0, 333333333, "FOO")},
+			balanceB:  x.Coins{coin(0, 666666666, "FOO")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := store.MemStore()
+			wallets := cash.NewBucket()
+			if len(tc.fees) > 0 {
+				obj, err := cash.WalletWith(CollectorAddress, tc.fees...)
+				require.NoError(t, err)
+				require.NoError(t, wallets.Save(db, obj))
+			}
+			var commit []abci.SigningValidator
+			for _, v := range tc.vals {
+				signed := tc.offline == nil ||
+					!bytes.Equal(v.PubKey.Data, tc.offline.PubKey.Data)
+				commit = append(commit, abci.SigningValidator{
+					Validator:       v,
+					SignedLastBlock: signed,
+				})
+			}
+
+			var header abci.Header
+			if tc.proposer != nil {
+				header.Proposer = *tc.proposer
+			}
+			ctx := weave.WithHeader(context.Background(), header)
+			ctx = weave.WithHeight(ctx, 12)
+			ctx = weave.WithLastCommit(ctx, commit)
+
+			res, err := NewDistributor(wallets, 20).EndBlock(ctx, db)
+			require.NoError(t, err)
+			assert.Empty(t, res.Diff)
+
+			balance := func(addr weave.Address) x.Coins {
+				obj, err := wallets.Get(db, addr)
+				require.NoError(t, err)
+				return cash.AsCoins(obj)
+			}
+			assert.Equal(t, tc.collector, balance(CollectorAddress))
+			assert.Equal(t, tc.balanceA, balance(addrA))
+			assert.Equal(t, tc.balanceB, balance(addrB))
+
+			// we have a record of everything paid out
+			record, err := NewBucket().GetDistribution(db, 12)
+			require.NoError(t, err)
+			if tc.balanceA == nil && tc.balanceB == nil {
+				assert.Nil(t, record)
+				return
+			}
+			require.NotNil(t, record)
+			assert.Equal(t, x.Coins(tc.fees), x.Coins(record.Fees))
+			paid := make(map[string]x.Coins)
+			for _, p := range record.Payouts {
+				paid[string(p.Recipient)] = p.Amount
+			}
+			if tc.balanceA != nil {
+				assert.Equal(t, tc.balanceA, paid[string(addrA)])
+			}
+			if tc.balanceB != nil {
+				assert.Equal(t, tc.balanceB, paid[string(addrB)])
+			}
+		})
+	}
+}
+
+func TestPruneRecords(t *testing.T) {
+	db := store.MemStore()
+	wallets := cash.NewBucket()
+	val := abci.Validator{
+		PubKey: abci.PubKey{Type: "ed25519", Data: []byte("aaa")},
+		Power:  1,
+	}
+	dist := NewDistributor(wallets, 0).WithHistory(3)
+	assert.Panics(t, func() { dist.WithHistory(-1) })
+
+	for h := int64(1); h <= 5; h++ {
+		fee := x.NewCoin(1, 0, "FOO")
+		obj, err := cash.WalletWith(CollectorAddress, &fee)
+		require.NoError(t, err)
+		require.NoError(t, wallets.Save(db, obj))
+
+		ctx := weave.WithHeight(context.Background(), h)
+		ctx = weave.WithLastCommit(ctx, []abci.SigningValidator{
+			{Validator: val, SignedLastBlock: true},
+		})
+		_, err = dist.EndBlock(ctx, db)
+		require.NoError(t, err)
+	}
+
+	// only the last 3 blocks are kept
+	for h := int64(1); h <= 5; h++ {
+		record, err := NewBucket().GetDistribution(db, h)
+		require.NoError(t, err)
+		assert.Equal(t, h > 2, record != nil, "%d", h)
+	}
+}
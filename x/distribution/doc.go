@@ -0,0 +1,19 @@
+/*
+Package distribution pays the fees of each block to the
+validators that produced it.
+
+The cash.FeeDecorator sends all fees to the CollectorAddress.
+At the end of every block, the Distributor pays the proposer
+of the block (from the abci.Header) a bonus, and splits the
+rest among the validators that signed the last block, pro
+rata by the power they had. Validators that were offline
+get nothing. Each validator is paid to the address of its
+validator key, so it can spend the fees by signing with
+that key.
+
+Every payout is recorded in a Distribution under the height
+of the block, so anyone can audit where the fees went.
+Records are deleted after DefaultKeepRecords blocks (see
+Distributor.WithHistory), so they don't grow forever.
+*/
+package distribution
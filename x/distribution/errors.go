@@ -0,0 +1,24 @@
+package distribution
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/distribution reserves 110 ~ 119.
+const (
+	CodeInvalidRecord uint32 = 110
+)
+
+var (
+	errInvalidRecord = fmt.Errorf("Invalid distribution record")
+)
+
+func ErrInvalidRecord(reason string) error {
+	return errors.WithLog(reason, errInvalidRecord, CodeInvalidRecord)
+}
+func IsInvalidRecordErr(err error) bool {
+	return errors.IsSameError(errInvalidRecord, err)
+}
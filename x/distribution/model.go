@@ -0,0 +1,131 @@
+package distribution
+
+import (
+	"encoding/binary"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+)
+
+const (
+	// BucketName is where we store the distribution of each block
+	BucketName = "dist"
+)
+
+// CollectorCondition collects the fees of the current block,
+// pass its address to cash.FeeDecorator.WithCollector
+var CollectorCondition = weave.NewCondition("dist", "fees", []byte("collector"))
+
+// CollectorAddress is the address of CollectorCondition
+var CollectorAddress = CollectorCondition.Address()
+
+//---- Distribution
+
+var _ orm.CloneableData = (*Distribution)(nil)
+var _ orm.Rebaser = (*Distribution)(nil)
+
+// Validate requires a height, some fees and valid recipients
+func (d *Distribution) Validate() error {
+	if d.Height <= 0 {
+		return ErrInvalidRecord("Missing height")
+	}
+	if d.Proposer != nil {
+		if err := weave.Address(d.Proposer).Validate(); err != nil {
+			return err
+		}
+	}
+	fees := x.Coins(d.Fees)
+	if !fees.IsPositive() {
+		return ErrInvalidRecord("No fees")
+	}
+	if err := fees.Validate(); err != nil {
+		return err
+	}
+	for _, p := range d.Payouts {
+		if err := weave.Address(p.Recipient).Validate(); err != nil {
+			return err
+		}
+		if err := x.Coins(p.Amount).Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy makes a new Distribution with the same data
+func (d *Distribution) Copy() orm.CloneableData {
+	payouts := make([]*Payout, len(d.Payouts))
+	for i, p := range d.Payouts {
+		payouts[i] = &Payout{
+			Recipient: p.Recipient,
+			Amount:    x.Coins(p.Amount).Clone(),
+		}
+	}
+	return &Distribution{
+		Height:   d.Height,
+		Proposer: d.Proposer,
+		Fees:     x.Coins(d.Fees).Clone(),
+		Payouts:  payouts,
+	}
+}
+
+// Rebase drops the records on a chain restarted from an
+// export, as their blocks are not part of it
+func (d *Distribution) Rebase(exportHeight int64) bool {
+	return false
+}
+
+// heightKey encodes the height, so the records are
+// sorted in the same order as the blocks
+func heightKey(height int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+	return bz
+}
+
+//-------------------- Object Wrapper -------
+
+// AsDistribution will safely type-cast any value from Bucket
+func AsDistribution(obj orm.Object) *Distribution {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Distribution)
+}
+
+//--- Bucket - type-safe bucket
+
+// Bucket stores one Distribution per block that paid out
+// any fees, keyed by height
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	proto := orm.NewSimpleObj(nil, new(Distribution))
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName, proto),
+	}
+}
+
+// GetDistribution returns the record for the block at
+// height, or nil if it paid out nothing
+func (b Bucket) GetDistribution(db weave.ReadOnlyKVStore,
+	height int64) (*Distribution, error) {
+
+	obj, err := b.Get(db, heightKey(height))
+	if err != nil {
+		return nil, err
+	}
+	return AsDistribution(obj), nil
+}
+
+// Save enforces the proper type
+func (b Bucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Distribution); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
@@ -0,0 +1,13 @@
+/*
+Package escrow locks up coins until a third party decides
+where they go.
+
+The sender moves the coins to an address derived from the
+escrow id, which no key controls. Only the arbiter can release
+them to the recipient, and either the arbiter or the timeout
+returns them to the sender. Each party can hand over its role
+to another address before the timeout.
+
+All escrows can be queried by sender, recipient and arbiter.
+*/
+package escrow
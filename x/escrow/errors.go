@@ -0,0 +1,67 @@
+package escrow
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/escrow reserves 70 ~ 79.
+const (
+	CodeNoSuchEscrow     uint32 = 70
+	CodeEscrowExpired           = 71
+	CodeEscrowNotExpired        = 72
+	CodeInvalidMsg              = 73
+)
+
+var (
+	errNoSuchEscrow     = fmt.Errorf("No escrow with this id")
+	errEscrowExpired    = fmt.Errorf("Escrow expired")
+	errEscrowNotExpired = fmt.Errorf("Escrow not expired")
+
+	errMissingEscrowID = fmt.Errorf("Missing escrow id")
+	errInvalidTimeout  = fmt.Errorf("Invalid timeout")
+	errNoUpdate        = fmt.Errorf("No parties to update")
+)
+
+func ErrNoSuchEscrow(id []byte) error {
+	msg := fmt.Sprintf("%X", id)
+	return errors.WithLog(msg, errNoSuchEscrow, CodeNoSuchEscrow)
+}
+func IsNoSuchEscrowErr(err error) bool {
+	return errors.IsSameError(errNoSuchEscrow, err)
+}
+
+func ErrEscrowExpired(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errEscrowExpired, CodeEscrowExpired)
+}
+func IsEscrowExpiredErr(err error) bool {
+	return errors.IsSameError(errEscrowExpired, err)
+}
+
+func ErrEscrowNotExpired(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errEscrowNotExpired, CodeEscrowNotExpired)
+}
+func IsEscrowNotExpiredErr(err error) bool {
+	return errors.IsSameError(errEscrowNotExpired, err)
+}
+
+//------ invalid messages ----
+// all will match IsInvalidMsgErr
+
+func ErrMissingEscrowID() error {
+	return errors.WithCode(errMissingEscrowID, CodeInvalidMsg)
+}
+func ErrInvalidTimeout(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errInvalidTimeout, CodeInvalidMsg)
+}
+func ErrNoUpdate() error {
+	return errors.WithCode(errNoUpdate, CodeInvalidMsg)
+}
+func IsInvalidMsgErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidMsg)
+}
@@ -0,0 +1,404 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/invariant"
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth x.Authenticator,
+	control cash.Controller) {
+
+	bucket := NewBucket()
+	r.Handle(pathCreateEscrowMsg, CreateEscrowHandler{auth, bucket, control})
+	r.Handle(pathReleaseEscrowMsg, ReleaseEscrowHandler{auth, bucket, control})
+	r.Handle(pathReturnEscrowMsg, ReturnEscrowHandler{auth, bucket, control})
+	r.Handle(pathUpdateEscrowPartiesMsg, UpdateEscrowHandler{auth, bucket})
+}
+
+// RegisterQuery will register this bucket as "/escrows",
+// and the indexes as "/escrows/sender", "/escrows/arbiter"
+// and "/escrows/recipient"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("escrows", qr)
+}
+
+// RegisterInvariants checks that all indexes of the escrows
+// match the data
+func RegisterInvariants(r invariant.Registry) {
+	r.Register("escrow/indexes", NewBucket().CheckIndexes)
+}
+
+//---- create
+
+// CreateEscrowHandler locks up the coins of the sender
+type CreateEscrowHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+	cash   cash.Controller
+}
+
+var _ weave.Handler = CreateEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h CreateEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += createEscrowCost
+	return res, nil
+}
+
+// Deliver stores the escrow and moves the coins to it.
+// The escrow id is returned as Data
+func (h CreateEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, sender, err := h.validate(ctx, tx)
+	if err != nil {
+		return res, err
+	}
+
+	escrow := &Escrow{
+		Sender:    sender,
+		Arbiter:   msg.Arbiter,
+		Recipient: msg.Recipient,
+		Amount:    msg.Amount,
+		Timeout:   msg.Timeout,
+		Memo:      msg.Memo,
+	}
+	obj, err := h.bucket.Create(db, escrow)
+	if err != nil {
+		return res, err
+	}
+
+	dest := EscrowCondition(obj.Key()).Address()
+	err = cash.MoveAll(db, h.cash, sender, dest, escrow.Amount)
+	if err != nil {
+		return res, err
+	}
+
+	res.Data = obj.Key()
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the sender
+func (h CreateEscrowHandler) validate(ctx weave.Context,
+	tx weave.Tx) (*CreateEscrowMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*CreateEscrowMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	if msg.Timeout <= height {
+		return nil, nil, ErrInvalidTimeout(msg.Timeout)
+	}
+
+	// the sender defaults to the main signer, and must have signed
+	sender := weave.Address(msg.Src)
+	if sender == nil {
+		sender = x.MainSigner(ctx, h.auth).Address()
+	}
+	if !h.auth.HasAddress(ctx, sender) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	return msg, sender, nil
+}
+
+//---- release
+
+// ReleaseEscrowHandler lets the arbiter pay the recipient
+type ReleaseEscrowHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+	cash   cash.Controller
+}
+
+var _ weave.Handler = ReleaseEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h ReleaseEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += releaseEscrowCost
+	return res, nil
+}
+
+// Deliver moves the coins to the recipient, and deletes the
+// escrow once it is empty
+func (h ReleaseEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+
+	amount := x.Coins(msg.Amount)
+	if amount.IsEmpty() {
+		amount = escrow.Amount
+	}
+
+	// we can never release more than the escrow holds,
+	// even if someone else sent coins to its address
+	remain := x.Coins(escrow.Amount).Clone()
+	for _, c := range amount {
+		remain, err = remain.Subtract(*c)
+		if err != nil {
+			return res, err
+		}
+	}
+	if !remain.IsNonNegative() {
+		return res, cash.ErrInsufficientFunds()
+	}
+
+	src := EscrowCondition(obj.Key()).Address()
+	err = cash.MoveAll(db, h.cash, src, escrow.Recipient, amount)
+	if err != nil {
+		return res, err
+	}
+
+	if remain.IsEmpty() {
+		err = h.bucket.Delete(db, obj.Key())
+		return res, err
+	}
+	escrow.Amount = remain
+	err = h.bucket.Save(db, obj)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the escrow
+func (h ReleaseEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*ReleaseEscrowMsg, orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*ReleaseEscrowMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := h.bucket.GetEscrow(db, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	escrow := AsEscrow(obj)
+
+	if !h.auth.HasAddress(ctx, escrow.Arbiter) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	height, _ := weave.GetHeight(ctx)
+	if escrow.IsExpired(height) {
+		return nil, nil, ErrEscrowExpired(escrow.Timeout)
+	}
+	return msg, obj, nil
+}
+
+//---- return
+
+// ReturnEscrowHandler sends the coins back to the sender
+type ReturnEscrowHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+	cash   cash.Controller
+}
+
+var _ weave.Handler = ReturnEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h ReturnEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += returnEscrowCost
+	return res, nil
+}
+
+// Deliver moves all coins back to the sender, and deletes
+// the escrow
+func (h ReturnEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+
+	src := EscrowCondition(obj.Key()).Address()
+	err = cash.MoveAll(db, h.cash, src, escrow.Sender, escrow.Amount)
+	if err != nil {
+		return res, err
+	}
+	err = h.bucket.Delete(db, obj.Key())
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the escrow
+func (h ReturnEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*ReturnEscrowMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := h.bucket.GetEscrow(db, msg.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+	escrow := AsEscrow(obj)
+
+	// the arbiter can return any time, everyone else must wait
+	height, _ := weave.GetHeight(ctx)
+	if !h.auth.HasAddress(ctx, escrow.Arbiter) && !escrow.IsExpired(height) {
+		return nil, ErrEscrowNotExpired(escrow.Timeout)
+	}
+	return obj, nil
+}
+
+//---- update
+
+// UpdateEscrowHandler lets each party hand over its role
+type UpdateEscrowHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = UpdateEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h UpdateEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += updateEscrowCost
+	return res, nil
+}
+
+// Deliver saves the escrow with the new parties
+func (h UpdateEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+
+	if msg.Sender != nil {
+		escrow.Sender = msg.Sender
+	}
+	if msg.Arbiter != nil {
+		escrow.Arbiter = msg.Arbiter
+	}
+	if msg.Recipient != nil {
+		escrow.Recipient = msg.Recipient
+	}
+	err = h.bucket.Save(db, obj)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the escrow
+func (h UpdateEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*UpdateEscrowPartiesMsg, orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*UpdateEscrowPartiesMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := h.bucket.GetEscrow(db, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	escrow := AsEscrow(obj)
+
+	height, _ := weave.GetHeight(ctx)
+	if escrow.IsExpired(height) {
+		return nil, nil, ErrEscrowExpired(escrow.Timeout)
+	}
+
+	// every party can only replace itself
+	if msg.Sender != nil && !h.auth.HasAddress(ctx, escrow.Sender) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	if msg.Arbiter != nil && !h.auth.HasAddress(ctx, escrow.Arbiter) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	if msg.Recipient != nil && !h.auth.HasAddress(ctx, escrow.Recipient) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	return msg, obj, nil
+}
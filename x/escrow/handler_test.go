@@ -0,0 +1,185 @@
+package escrow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/testhelpers"
+)
+
+// newEscrowTest runs the escrow handlers on a new store
+func newEscrowTest() *testhelpers.HandlerTest {
+	e := testhelpers.NewHandlerTest()
+	RegisterRoutes(e, e.Auth, cash.NewController(cash.NewBucket()))
+	return e
+}
+
+func TestEscrow(t *testing.T) {
+	e := newEscrowTest()
+
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	b := weave.NewCondition("sig", "ed25519", []byte{4, 5, 6})
+	c := weave.NewCondition("sig", "ed25519", []byte{7, 8, 9})
+	sender, arbiter, rcpt := a.Address(), b.Address(), c.Address()
+
+	all := x.NewCoin(100, 0, "FOO")
+	some := x.NewCoin(30, 0, "FOO")
+	rest := x.NewCoin(70, 0, "FOO")
+	testhelpers.Fund(e.DB, sender, &all)
+
+	create := &CreateEscrowMsg{
+		Src:       sender,
+		Arbiter:   arbiter,
+		Recipient: rcpt,
+		Amount:    []*x.Coin{&some},
+		Timeout:   10,
+	}
+
+	// must be signed by the sender, and not time out in the past
+	_, err := e.Run(1, create, b)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = e.Run(10, create, a)
+	assert.True(t, IsInvalidMsgErr(err), "%+v", err)
+	// can only lock up what you have
+	big := x.NewCoin(500, 0, "FOO")
+	create.Amount = []*x.Coin{&big}
+	_, err = e.Run(1, create, a)
+	assert.True(t, cash.IsInsufficientFundsErr(err), "%+v", err)
+
+	// lock up all of it
+	create.Amount = []*x.Coin{&all}
+	res, err := e.Run(1, create, a)
+	require.NoError(t, err)
+	id := res.Data
+	assert.True(t, testhelpers.Balance(e.DB, sender).IsEmpty())
+	assert.Equal(t, x.Coins{&all}, testhelpers.Balance(e.DB, EscrowCondition(id).Address()))
+
+	// we can find it by all parties
+	for idx, addr := range map[string]weave.Address{
+		SenderIndex: sender, ArbiterIndex: arbiter, RecipientIndex: rcpt} {
+		objs, err := NewBucket().GetIndexed(e.DB, idx, addr)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(objs), idx)
+		assert.Equal(t, id, objs[0].Key())
+	}
+
+	// only the arbiter can release, and not more than there is
+	release := &ReleaseEscrowMsg{EscrowId: id, Amount: []*x.Coin{&some}}
+	_, err = e.Run(2, release, a, c)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = e.Run(2, &ReleaseEscrowMsg{EscrowId: []byte("foo")}, b)
+	assert.True(t, IsNoSuchEscrowErr(err), "%+v", err)
+	_, err = e.Run(2, release, b)
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{&some}, testhelpers.Balance(e.DB, rcpt))
+	release.Amount = []*x.Coin{&all}
+	_, err = e.Run(2, release, b)
+	assert.True(t, cash.IsInsufficientFundsErr(err), "%+v", err)
+
+	// the recipient hands over to the sender,
+	// but cannot change the arbiter
+	update := &UpdateEscrowPartiesMsg{EscrowId: id, Arbiter: rcpt}
+	_, err = e.Run(3, update, c)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	update = &UpdateEscrowPartiesMsg{EscrowId: id, Recipient: sender}
+	_, err = e.Run(3, update, c)
+	require.NoError(t, err)
+	objs, err := NewBucket().GetIndexed(e.DB, RecipientIndex, rcpt)
+	require.NoError(t, err)
+	assert.Empty(t, objs)
+
+	// nobody but the arbiter can return before the timeout
+	ret := &ReturnEscrowMsg{EscrowId: id}
+	_, err = e.Run(9, ret, a)
+	assert.True(t, IsEscrowNotExpiredErr(err), "%+v", err)
+
+	// after that, nothing can be released or updated
+	release.Amount = nil
+	_, err = e.Run(10, release, b)
+	assert.True(t, IsEscrowExpiredErr(err), "%+v", err)
+	_, err = e.Run(10, update, a)
+	assert.True(t, IsEscrowExpiredErr(err), "%+v", err)
+
+	// but anyone can return the rest
+	_, err = e.Run(10, ret)
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{&rest}, testhelpers.Balance(e.DB, sender))
+	_, err = e.Run(10, ret)
+	assert.True(t, IsNoSuchEscrowErr(err), "%+v", err)
+	objs, err = NewBucket().GetIndexed(e.DB, SenderIndex, sender)
+	require.NoError(t, err)
+	assert.Empty(t, objs)
+}
+
+func TestReleaseAll(t *testing.T) {
+	e := newEscrowTest()
+
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	b := weave.NewCondition("sig", "ed25519", []byte{4, 5, 6})
+	rcpt := weave.NewCondition("sig", "ed25519", []byte{7, 8, 9}).Address()
+	foo := x.NewCoin(100, 0, "FOO")
+	bar := x.NewCoin(5, 0, "BAR")
+	testhelpers.Fund(e.DB, a.Address(), &foo, &bar)
+
+	// sender defaults to the signer
+	create := &CreateEscrowMsg{
+		Arbiter:   b.Address(),
+		Recipient: rcpt,
+		Amount:    []*x.Coin{&bar, &foo},
+		Timeout:   10,
+	}
+	res, err := e.Run(1, create, a)
+	require.NoError(t, err)
+	id := res.Data
+
+	// the arbiter can return early
+	_, err = e.Run(2, &ReleaseEscrowMsg{EscrowId: id}, b)
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{&bar, &foo}, testhelpers.Balance(e.DB, rcpt))
+	obj, err := NewBucket().Get(e.DB, id)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+}
+
+func TestValidateMsgs(t *testing.T) {
+	addr := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3}).Address()
+	foo := x.NewCoin(100, 0, "FOO")
+	neg := x.NewCoin(-100, 0, "FOO")
+	id := []byte{0, 0, 0, 1}
+
+	cases := []struct {
+		msg   interface{ Validate() error }
+		check func(error) bool
+	}{
+		0: {&CreateEscrowMsg{Arbiter: addr, Recipient: addr, Amount: []*x.Coin{&foo}, Timeout: 5}, nil},
+		1: {&CreateEscrowMsg{Src: []byte{1}, Arbiter: addr, Recipient: addr, Amount: []*x.Coin{&foo}, Timeout: 5},
+			errors.IsUnrecognizedAddressErr},
+		2: {&CreateEscrowMsg{Arbiter: addr, Amount: []*x.Coin{&foo}, Timeout: 5}, errors.IsUnrecognizedAddressErr},
+		3: {&CreateEscrowMsg{Arbiter: addr, Recipient: addr, Amount: []*x.Coin{&neg}, Timeout: 5},
+			cash.IsInvalidAmountErr},
+		4: {&CreateEscrowMsg{Arbiter: addr, Recipient: addr, Amount: []*x.Coin{&foo}}, IsInvalidMsgErr},
+		5: {&ReleaseEscrowMsg{EscrowId: id}, nil},
+		6: {&ReleaseEscrowMsg{Amount: []*x.Coin{&foo}}, IsInvalidMsgErr},
+		7: {&ReturnEscrowMsg{EscrowId: id}, nil},
+		8: {&ReturnEscrowMsg{}, IsInvalidMsgErr},
+		9: {&UpdateEscrowPartiesMsg{EscrowId: id}, IsInvalidMsgErr},
+		10: {&UpdateEscrowPartiesMsg{EscrowId: id, Arbiter: []byte{1}},
+			errors.IsUnrecognizedAddressErr},
+		11: {&UpdateEscrowPartiesMsg{EscrowId: id, Arbiter: addr}, nil},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.Validate()
+		if tc.check == nil {
+			assert.NoError(t, err, "%d", i)
+		} else {
+			assert.True(t, tc.check(err), "%d: %+v", i, err)
+		}
+	}
+}
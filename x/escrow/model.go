@@ -0,0 +1,200 @@
+package escrow
+
+import (
+	"errors"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+const (
+	// BucketName is where we store the escrows
+	BucketName = "escrow"
+	// SenderIndex is the index of escrows by sender
+	SenderIndex = "sender"
+	// ArbiterIndex is the index of escrows by arbiter
+	ArbiterIndex = "arbiter"
+	// RecipientIndex is the index of escrows by recipient
+	RecipientIndex = "recipient"
+
+	maxMemoSize int = 128
+)
+
+//---- Escrow
+
+var _ orm.CloneableData = (*Escrow)(nil)
+var _ orm.Rebaser = (*Escrow)(nil)
+
+// Validate requires valid parties, a positive amount
+// and a timeout
+func (e *Escrow) Validate() error {
+	if err := weave.Address(e.Sender).Validate(); err != nil {
+		return err
+	}
+	return validateTerms(e.Arbiter, e.Recipient, e.Amount, e.Timeout, e.Memo)
+}
+
+// Copy makes a new Escrow with the same data
+func (e *Escrow) Copy() orm.CloneableData {
+	return &Escrow{
+		Sender:    e.Sender,
+		Arbiter:   e.Arbiter,
+		Recipient: e.Recipient,
+		Amount:    x.Coins(e.Amount).Clone(),
+		Timeout:   e.Timeout,
+		Memo:      e.Memo,
+	}
+}
+
+// Rebase moves the timeout to a chain restarted from an export
+func (e *Escrow) Rebase(exportHeight int64) bool {
+	e.Timeout = orm.RebaseHeight(e.Timeout, exportHeight)
+	return true
+}
+
+// IsExpired returns true once no more coins may be released
+func (e *Escrow) IsExpired(height int64) bool {
+	return height >= e.Timeout
+}
+
+// validateTerms checks everything but the sender, which
+// may be set later
+func validateTerms(arbiter, recipient []byte, amount []*x.Coin,
+	timeout int64, memo string) error {
+
+	if err := weave.Address(arbiter).Validate(); err != nil {
+		return err
+	}
+	if err := weave.Address(recipient).Validate(); err != nil {
+		return err
+	}
+	if err := validateAmount(amount); err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		return ErrInvalidTimeout(timeout)
+	}
+	if len(memo) > maxMemoSize {
+		return cash.ErrInvalidMemo("Memo too long")
+	}
+	return nil
+}
+
+func validateAmount(amount []*x.Coin) error {
+	coins := x.Coins(amount)
+	if !coins.IsPositive() {
+		return cash.ErrInvalidAmount("Non-positive amount")
+	}
+	return coins.Validate()
+}
+
+// EscrowCondition returns the condition that holds the coins
+// of the escrow with the given id
+func EscrowCondition(id []byte) weave.Condition {
+	return weave.NewCondition("escrow", "seq", id)
+}
+
+//-------------------- Object Wrapper -------
+
+// AsEscrow will safely type-cast any value from Bucket to an Escrow
+func AsEscrow(obj orm.Object) *Escrow {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Escrow)
+}
+
+// NewEscrow wraps the escrow into an object for the bucket
+func NewEscrow(id []byte, escrow *Escrow) orm.Object {
+	return orm.NewSimpleObj(id, escrow)
+}
+
+//--- Bucket - type-safe bucket
+
+// Bucket is a type-safe wrapper around orm.Bucket
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name,
+// and all indexes
+func NewBucket() Bucket {
+	bucket := orm.NewBucket(BucketName, NewEscrow(nil, new(Escrow))).
+		WithIndex(SenderIndex, idxSender, false).
+		WithIndex(ArbiterIndex, idxArbiter, false).
+		WithIndex(RecipientIndex, idxRecipient, false)
+	return Bucket{
+		Bucket: bucket,
+	}
+}
+
+// Create saves the escrow under the next free id
+func (b Bucket) Create(db weave.KVStore, escrow *Escrow) (orm.Object, error) {
+	seq := b.Sequence(orm.SeqID)
+	obj := NewEscrow(seq.NextVal(db), escrow)
+	err := b.Save(db, obj)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// GetEscrow loads the escrow with the given id, or
+// returns an error if there is none
+func (b Bucket) GetEscrow(db weave.ReadOnlyKVStore, id []byte) (orm.Object, error) {
+	obj, err := b.Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if AsEscrow(obj) == nil {
+		return nil, ErrNoSuchEscrow(id)
+	}
+	return obj, nil
+}
+
+// Save enforces the proper type
+func (b Bucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Escrow); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+func getEscrow(obj orm.Object) (*Escrow, error) {
+	// these should use proper errors, but they never occur
+	// except in case of developer error (wrong data in wrong bucket)
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	esc, ok := obj.Value().(*Escrow)
+	if !ok {
+		return nil, errors.New("Can only take index of Escrow")
+	}
+	return esc, nil
+}
+
+func idxSender(obj orm.Object) ([]byte, error) {
+	esc, err := getEscrow(obj)
+	if err != nil {
+		return nil, err
+	}
+	return esc.Sender, nil
+}
+
+func idxArbiter(obj orm.Object) ([]byte, error) {
+	esc, err := getEscrow(obj)
+	if err != nil {
+		return nil, err
+	}
+	return esc.Arbiter, nil
+}
+
+func idxRecipient(obj orm.Object) ([]byte, error) {
+	esc, err := getEscrow(obj)
+	if err != nil {
+		return nil, err
+	}
+	return esc.Recipient, nil
+}
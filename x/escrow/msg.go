@@ -0,0 +1,94 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+)
+
+// Ensure we implement the Msg interface
+var _ weave.Msg = (*CreateEscrowMsg)(nil)
+var _ weave.Msg = (*ReleaseEscrowMsg)(nil)
+var _ weave.Msg = (*ReturnEscrowMsg)(nil)
+var _ weave.Msg = (*UpdateEscrowPartiesMsg)(nil)
+
+const (
+	pathCreateEscrowMsg        = "escrow/create"
+	pathReleaseEscrowMsg       = "escrow/release"
+	pathReturnEscrowMsg        = "escrow/return"
+	pathUpdateEscrowPartiesMsg = "escrow/update"
+
+	createEscrowCost  int64 = 300
+	releaseEscrowCost int64 = 100
+	returnEscrowCost  int64 = 100
+	updateEscrowCost  int64 = 50
+)
+
+// Path returns the routing path for this message
+func (CreateEscrowMsg) Path() string {
+	return pathCreateEscrowMsg
+}
+
+// Validate makes sure that this is sensible.
+// src is optional, as it defaults to the signer
+func (m *CreateEscrowMsg) Validate() error {
+	if m.Src != nil {
+		if err := weave.Address(m.Src).Validate(); err != nil {
+			return err
+		}
+	}
+	return validateTerms(m.Arbiter, m.Recipient, m.Amount, m.Timeout, m.Memo)
+}
+
+// Path returns the routing path for this message
+func (ReleaseEscrowMsg) Path() string {
+	return pathReleaseEscrowMsg
+}
+
+// Validate makes sure that this is sensible.
+// An empty amount releases everything
+func (m *ReleaseEscrowMsg) Validate() error {
+	if len(m.EscrowId) == 0 {
+		return ErrMissingEscrowID()
+	}
+	if len(m.Amount) == 0 {
+		return nil
+	}
+	return validateAmount(m.Amount)
+}
+
+// Path returns the routing path for this message
+func (ReturnEscrowMsg) Path() string {
+	return pathReturnEscrowMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *ReturnEscrowMsg) Validate() error {
+	if len(m.EscrowId) == 0 {
+		return ErrMissingEscrowID()
+	}
+	return nil
+}
+
+// Path returns the routing path for this message
+func (UpdateEscrowPartiesMsg) Path() string {
+	return pathUpdateEscrowPartiesMsg
+}
+
+// Validate makes sure that this is sensible.
+// All parties are optional, but at least one must be set
+func (m *UpdateEscrowPartiesMsg) Validate() error {
+	if len(m.EscrowId) == 0 {
+		return ErrMissingEscrowID()
+	}
+	if m.Sender == nil && m.Arbiter == nil && m.Recipient == nil {
+		return ErrNoUpdate()
+	}
+	for _, addr := range [][]byte{m.Sender, m.Arbiter, m.Recipient} {
+		if addr == nil {
+			continue
+		}
+		if err := weave.Address(addr).Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+/*
+Package htlc implements hash-time-locked contracts, which
+allow atomic swaps with other chains.
+
+The sender locks up coins under the sha256 hash of a secret
+preimage, and a timeout height. Anyone who knows the preimage
+can release the coins to the recipient before the timeout,
+which publishes the preimage in the tags of that tx. Once the
+timeout is reached, the coins can only be returned to the
+sender.
+
+For a swap, both parties lock coins on their chain under the
+same hash, the one on the initiator's chain with the longer
+timeout. When the initiator claims the other side, the
+counterparty learns the preimage and can claim in turn.
+*/
+package htlc
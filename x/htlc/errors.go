@@ -0,0 +1,77 @@
+package htlc
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/htlc reserves 80 ~ 89.
+const (
+	CodeNoSuchSwap      uint32 = 80
+	CodeSwapExpired            = 81
+	CodeSwapNotExpired         = 82
+	CodeInvalidPreimage        = 83
+	CodeInvalidMsg             = 84
+)
+
+var (
+	errNoSuchSwap      = fmt.Errorf("No swap with this id")
+	errSwapExpired     = fmt.Errorf("Swap expired")
+	errSwapNotExpired  = fmt.Errorf("Swap not expired")
+	errInvalidPreimage = fmt.Errorf("Preimage does not match")
+
+	errMissingSwapID       = fmt.Errorf("Missing swap id")
+	errInvalidPreimageHash = fmt.Errorf("Preimage hash must be 32 bytes")
+	errInvalidTimeout      = fmt.Errorf("Invalid timeout")
+)
+
+func ErrNoSuchSwap(id []byte) error {
+	msg := fmt.Sprintf("%X", id)
+	return errors.WithLog(msg, errNoSuchSwap, CodeNoSuchSwap)
+}
+func IsNoSuchSwapErr(err error) bool {
+	return errors.IsSameError(errNoSuchSwap, err)
+}
+
+func ErrSwapExpired(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errSwapExpired, CodeSwapExpired)
+}
+func IsSwapExpiredErr(err error) bool {
+	return errors.IsSameError(errSwapExpired, err)
+}
+
+func ErrSwapNotExpired(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errSwapNotExpired, CodeSwapNotExpired)
+}
+func IsSwapNotExpiredErr(err error) bool {
+	return errors.IsSameError(errSwapNotExpired, err)
+}
+
+func ErrInvalidPreimage() error {
+	return errors.WithCode(errInvalidPreimage, CodeInvalidPreimage)
+}
+func IsInvalidPreimageErr(err error) bool {
+	return errors.IsSameError(errInvalidPreimage, err)
+}
+
+//------ invalid messages ----
+// all will match IsInvalidMsgErr
+
+func ErrMissingSwapID() error {
+	return errors.WithCode(errMissingSwapID, CodeInvalidMsg)
+}
+func ErrInvalidPreimageHash(hash []byte) error {
+	msg := fmt.Sprintf("%X", hash)
+	return errors.WithLog(msg, errInvalidPreimageHash, CodeInvalidMsg)
+}
+func ErrInvalidTimeout(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errInvalidTimeout, CodeInvalidMsg)
+}
+func IsInvalidMsgErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidMsg)
+}
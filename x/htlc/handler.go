@@ -0,0 +1,316 @@
+package htlc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/invariant"
+)
+
+var (
+	// TagPreimageHash is set on release, so watchers can find
+	// the tx that revealed the preimage of a given hash
+	TagPreimageHash = []byte("htlc/preimage_hash")
+	// TagPreimage contains the revealed preimage in hex
+	TagPreimage = []byte("htlc/preimage")
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth x.Authenticator,
+	control cash.Controller) {
+
+	bucket := NewBucket()
+	r.Handle(pathCreateSwapMsg, CreateSwapHandler{auth, bucket, control})
+	r.Handle(pathReleaseSwapMsg, ReleaseSwapHandler{bucket, control})
+	r.Handle(pathReturnSwapMsg, ReturnSwapHandler{bucket, control})
+}
+
+// RegisterQuery will register this bucket as "/swaps",
+// and the indexes as "/swaps/preimage_hash", "/swaps/sender"
+// and "/swaps/recipient"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("swaps", qr)
+}
+
+// RegisterInvariants checks that all indexes of the swaps
+// match the data
+func RegisterInvariants(r invariant.Registry) {
+	r.Register("htlc/indexes", NewBucket().CheckIndexes)
+}
+
+//---- create
+
+// CreateSwapHandler locks up the coins of the sender
+type CreateSwapHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+	cash   cash.Controller
+}
+
+var _ weave.Handler = CreateSwapHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h CreateSwapHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += createSwapCost
+	return res, nil
+}
+
+// Deliver stores the swap and moves the coins to it.
+// The swap id is returned as Data
+func (h CreateSwapHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, sender, err := h.validate(ctx, tx)
+	if err != nil {
+		return res, err
+	}
+
+	swap := &Swap{
+		PreimageHash: msg.PreimageHash,
+		Sender:       sender,
+		Recipient:    msg.Recipient,
+		Amount:       msg.Amount,
+		Timeout:      msg.Timeout,
+		Memo:         msg.Memo,
+	}
+	obj, err := h.bucket.Create(db, swap)
+	if err != nil {
+		return res, err
+	}
+
+	dest := SwapCondition(obj.Key()).Address()
+	err = cash.MoveAll(db, h.cash, sender, dest, swap.Amount)
+	if err != nil {
+		return res, err
+	}
+
+	res.Data = obj.Key()
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the sender
+func (h CreateSwapHandler) validate(ctx weave.Context,
+	tx weave.Tx) (*CreateSwapMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*CreateSwapMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	if msg.Timeout <= height {
+		return nil, nil, ErrInvalidTimeout(msg.Timeout)
+	}
+
+	// the sender defaults to the main signer, and must have signed
+	sender := weave.Address(msg.Src)
+	if sender == nil {
+		sender = x.MainSigner(ctx, h.auth).Address()
+	}
+	if !h.auth.HasAddress(ctx, sender) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	return msg, sender, nil
+}
+
+//---- release
+
+// ReleaseSwapHandler pays the recipient, given the preimage.
+//
+// Anyone may submit the preimage, as the coins always go
+// to the recipient.
+type ReleaseSwapHandler struct {
+	bucket Bucket
+	cash   cash.Controller
+}
+
+var _ weave.Handler = ReleaseSwapHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h ReleaseSwapHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += releaseSwapCost
+	return res, nil
+}
+
+// Deliver moves all coins to the recipient, deletes the swap,
+// and publishes the preimage in the tags
+func (h ReleaseSwapHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	swap := AsSwap(obj)
+
+	src := SwapCondition(obj.Key()).Address()
+	err = cash.MoveAll(db, h.cash, src, swap.Recipient, swap.Amount)
+	if err != nil {
+		return res, err
+	}
+	err = h.bucket.Delete(db, obj.Key())
+	if err != nil {
+		return res, err
+	}
+
+	res.Tags = []common.KVPair{
+		{Key: TagPreimageHash, Value: toHex(swap.PreimageHash)},
+		{Key: TagPreimage, Value: toHex(msg.Preimage)},
+	}
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the swap
+func (h ReleaseSwapHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*ReleaseSwapMsg, orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*ReleaseSwapMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := h.bucket.GetSwap(db, msg.SwapId)
+	if err != nil {
+		return nil, nil, err
+	}
+	swap := AsSwap(obj)
+
+	height, _ := weave.GetHeight(ctx)
+	if swap.IsExpired(height) {
+		return nil, nil, ErrSwapExpired(swap.Timeout)
+	}
+	if !bytes.Equal(HashPreimage(msg.Preimage), swap.PreimageHash) {
+		return nil, nil, ErrInvalidPreimage()
+	}
+	return msg, obj, nil
+}
+
+//---- return
+
+// ReturnSwapHandler sends the coins back to the sender
+// after the timeout. Anyone may trigger it.
+type ReturnSwapHandler struct {
+	bucket Bucket
+	cash   cash.Controller
+}
+
+var _ weave.Handler = ReturnSwapHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h ReturnSwapHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += returnSwapCost
+	return res, nil
+}
+
+// Deliver moves all coins back to the sender, and deletes
+// the swap
+func (h ReturnSwapHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	swap := AsSwap(obj)
+
+	src := SwapCondition(obj.Key()).Address()
+	err = cash.MoveAll(db, h.cash, src, swap.Sender, swap.Amount)
+	if err != nil {
+		return res, err
+	}
+	err = h.bucket.Delete(db, obj.Key())
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the swap
+func (h ReturnSwapHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*ReturnSwapMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := h.bucket.GetSwap(db, msg.SwapId)
+	if err != nil {
+		return nil, err
+	}
+	swap := AsSwap(obj)
+
+	height, _ := weave.GetHeight(ctx)
+	if !swap.IsExpired(height) {
+		return nil, ErrSwapNotExpired(swap.Timeout)
+	}
+	return obj, nil
+}
+
+// toHex formats tag values like the KeyTagger does
+func toHex(bz []byte) []byte {
+	return []byte(strings.ToUpper(hex.EncodeToString(bz)))
+}
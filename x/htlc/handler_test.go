@@ -0,0 +1,163 @@
+package htlc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/testhelpers"
+)
+
+// newSwapTest runs the htlc handlers on a new store
+func newSwapTest() *testhelpers.HandlerTest {
+	s := testhelpers.NewHandlerTest()
+	RegisterRoutes(s, s.Auth, cash.NewController(cash.NewBucket()))
+	return s
+}
+
+func TestReleaseSwap(t *testing.T) {
+	s := newSwapTest()
+
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	b := weave.NewCondition("sig", "ed25519", []byte{4, 5, 6})
+	sender, rcpt := a.Address(), b.Address()
+
+	all := x.NewCoin(100, 0, "FOO")
+	testhelpers.Fund(s.DB, sender, &all)
+
+	preimage := []byte("my secret")
+	hash := HashPreimage(preimage)
+	create := &CreateSwapMsg{
+		PreimageHash: hash,
+		Recipient:    rcpt,
+		Amount:       []*x.Coin{&all},
+		Timeout:      10,
+	}
+
+	// must be signed by the sender, and not time out in the past
+	create.Src = sender
+	_, err := s.Run(1, create, b)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = s.Run(10, create, a)
+	assert.True(t, IsInvalidMsgErr(err), "%+v", err)
+
+	// sender defaults to the signer
+	create.Src = nil
+	res, err := s.Run(1, create, a)
+	require.NoError(t, err)
+	id := res.Data
+	assert.True(t, testhelpers.Balance(s.DB, sender).IsEmpty())
+	assert.Equal(t, x.Coins{&all}, testhelpers.Balance(s.DB, SwapCondition(id).Address()))
+
+	// we can find it by hash and both parties
+	for idx, key := range map[string][]byte{
+		PreimageHashIndex: hash, SenderIndex: sender, RecipientIndex: rcpt} {
+		objs, err := NewBucket().GetIndexed(s.DB, idx, key)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(objs), idx)
+		assert.Equal(t, id, objs[0].Key())
+	}
+
+	// cannot return before the timeout, or release
+	// with the wrong preimage
+	_, err = s.Run(9, &ReturnSwapMsg{SwapId: id}, a)
+	assert.True(t, IsSwapNotExpiredErr(err), "%+v", err)
+	_, err = s.Run(9, &ReleaseSwapMsg{SwapId: id, Preimage: []byte("guess")})
+	assert.True(t, IsInvalidPreimageErr(err), "%+v", err)
+	_, err = s.Run(9, &ReleaseSwapMsg{SwapId: []byte("foo"), Preimage: preimage})
+	assert.True(t, IsNoSuchSwapErr(err), "%+v", err)
+
+	// anyone with the preimage can release it to the recipient
+	release := &ReleaseSwapMsg{SwapId: id, Preimage: preimage}
+	res, err = s.Run(9, release)
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{&all}, testhelpers.Balance(s.DB, rcpt))
+	require.Equal(t, 2, len(res.Tags))
+	assert.Equal(t, TagPreimageHash, res.Tags[0].Key)
+	assert.Equal(t, toHex(hash), res.Tags[0].Value)
+	assert.Equal(t, TagPreimage, res.Tags[1].Key)
+	assert.Equal(t, []byte("6D7920736563726574"), res.Tags[1].Value)
+
+	// and it is gone
+	_, err = s.Run(9, release)
+	assert.True(t, IsNoSuchSwapErr(err), "%+v", err)
+	objs, err := NewBucket().GetIndexed(s.DB, PreimageHashIndex, hash)
+	require.NoError(t, err)
+	assert.Empty(t, objs)
+}
+
+func TestReturnSwap(t *testing.T) {
+	s := newSwapTest()
+
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	rcpt := weave.NewCondition("sig", "ed25519", []byte{4, 5, 6}).Address()
+	foo := x.NewCoin(100, 0, "FOO")
+	bar := x.NewCoin(5, 0, "BAR")
+	testhelpers.Fund(s.DB, a.Address(), &foo, &bar)
+
+	preimage := []byte("my secret")
+	create := &CreateSwapMsg{
+		PreimageHash: HashPreimage(preimage),
+		Recipient:    rcpt,
+		Amount:       []*x.Coin{&bar, &foo},
+		Timeout:      10,
+	}
+	res, err := s.Run(1, create, a)
+	require.NoError(t, err)
+	id := res.Data
+
+	// once expired, the preimage is useless
+	_, err = s.Run(10, &ReleaseSwapMsg{SwapId: id, Preimage: preimage})
+	assert.True(t, IsSwapExpiredErr(err), "%+v", err)
+
+	// but anyone can return it
+	ret := &ReturnSwapMsg{SwapId: id}
+	_, err = s.Run(10, ret)
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{&bar, &foo}, testhelpers.Balance(s.DB, a.Address()))
+	assert.Nil(t, testhelpers.Balance(s.DB, rcpt))
+	_, err = s.Run(11, ret)
+	assert.True(t, IsNoSuchSwapErr(err), "%+v", err)
+}
+
+func TestValidateMsgs(t *testing.T) {
+	addr := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3}).Address()
+	hash := HashPreimage([]byte("foo"))
+	foo := x.NewCoin(100, 0, "FOO")
+	neg := x.NewCoin(-100, 0, "FOO")
+	id := []byte{0, 0, 0, 1}
+
+	cases := []struct {
+		msg   interface{ Validate() error }
+		check func(error) bool
+	}{
+		0: {&CreateSwapMsg{PreimageHash: hash, Recipient: addr, Amount: []*x.Coin{&foo}, Timeout: 5}, nil},
+		1: {&CreateSwapMsg{Src: []byte{1}, PreimageHash: hash, Recipient: addr, Amount: []*x.Coin{&foo}, Timeout: 5},
+			errors.IsUnrecognizedAddressErr},
+		2: {&CreateSwapMsg{PreimageHash: []byte("foo"), Recipient: addr, Amount: []*x.Coin{&foo}, Timeout: 5},
+			IsInvalidMsgErr},
+		3: {&CreateSwapMsg{PreimageHash: hash, Amount: []*x.Coin{&foo}, Timeout: 5}, errors.IsUnrecognizedAddressErr},
+		4: {&CreateSwapMsg{PreimageHash: hash, Recipient: addr, Amount: []*x.Coin{&neg}, Timeout: 5},
+			cash.IsInvalidAmountErr},
+		5:  {&CreateSwapMsg{PreimageHash: hash, Recipient: addr, Amount: []*x.Coin{&foo}}, IsInvalidMsgErr},
+		6:  {&ReleaseSwapMsg{SwapId: id, Preimage: []byte("foo")}, nil},
+		7:  {&ReleaseSwapMsg{Preimage: []byte("foo")}, IsInvalidMsgErr},
+		8:  {&ReleaseSwapMsg{SwapId: id}, IsInvalidPreimageErr},
+		9:  {&ReturnSwapMsg{SwapId: id}, nil},
+		10: {&ReturnSwapMsg{}, IsInvalidMsgErr},
+	}
+
+	for i, tc := range cases {
+		err := tc.msg.Validate()
+		if tc.check == nil {
+			assert.NoError(t, err, "%d", i)
+		} else {
+			assert.True(t, tc.check(err), "%d: %+v", i, err)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package htlc
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+const optKey = "htlc"
+
+// Initializer loads all open swaps when a
+// chain is restarted from an export of its state
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+var _ weave.Exporter = Initializer{}
+
+// FromGenesis loads the state written by ToGenesis, if any
+func (Initializer) FromGenesis(opts weave.Options, kv weave.KVStore) error {
+	return orm.ImportBuckets(opts, optKey, kv, NewBucket().Bucket)
+}
+
+// ToGenesis writes all open swaps to the options
+func (Initializer) ToGenesis(opts weave.Options, kv weave.ReadOnlyKVStore) error {
+	return orm.ExportBuckets(opts, optKey, kv, NewBucket().Bucket)
+}
@@ -0,0 +1,203 @@
+package htlc
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+const (
+	// BucketName is where we store the swaps
+	BucketName = "swap"
+	// PreimageHashIndex is the index of swaps by preimage hash
+	PreimageHashIndex = "preimage_hash"
+	// SenderIndex is the index of swaps by sender
+	SenderIndex = "sender"
+	// RecipientIndex is the index of swaps by recipient
+	RecipientIndex = "recipient"
+
+	maxMemoSize int = 128
+)
+
+//---- Swap
+
+var _ orm.CloneableData = (*Swap)(nil)
+var _ orm.Rebaser = (*Swap)(nil)
+
+// Validate requires a valid hash and parties, a positive
+// amount and a timeout
+func (s *Swap) Validate() error {
+	if err := weave.Address(s.Sender).Validate(); err != nil {
+		return err
+	}
+	return validateTerms(s.PreimageHash, s.Recipient, s.Amount, s.Timeout, s.Memo)
+}
+
+// Copy makes a new Swap with the same data
+func (s *Swap) Copy() orm.CloneableData {
+	return &Swap{
+		PreimageHash: s.PreimageHash,
+		Sender:       s.Sender,
+		Recipient:    s.Recipient,
+		Amount:       x.Coins(s.Amount).Clone(),
+		Timeout:      s.Timeout,
+		Memo:         s.Memo,
+	}
+}
+
+// Rebase moves the timeout to a chain restarted from an export
+func (s *Swap) Rebase(exportHeight int64) bool {
+	s.Timeout = orm.RebaseHeight(s.Timeout, exportHeight)
+	return true
+}
+
+// IsExpired returns true once the coins can only be returned
+func (s *Swap) IsExpired(height int64) bool {
+	return height >= s.Timeout
+}
+
+// validateTerms checks everything but the sender, which
+// may be set later
+func validateTerms(hash, recipient []byte, amount []*x.Coin,
+	timeout int64, memo string) error {
+
+	if len(hash) != sha256.Size {
+		return ErrInvalidPreimageHash(hash)
+	}
+	if err := weave.Address(recipient).Validate(); err != nil {
+		return err
+	}
+	coins := x.Coins(amount)
+	if !coins.IsPositive() {
+		return cash.ErrInvalidAmount("Non-positive amount")
+	}
+	if err := coins.Validate(); err != nil {
+		return err
+	}
+	if timeout <= 0 {
+		return ErrInvalidTimeout(timeout)
+	}
+	if len(memo) > maxMemoSize {
+		return cash.ErrInvalidMemo("Memo too long")
+	}
+	return nil
+}
+
+// HashPreimage returns the hash to lock a swap with
+func HashPreimage(preimage []byte) []byte {
+	h := sha256.Sum256(preimage)
+	return h[:]
+}
+
+// SwapCondition returns the condition that holds the coins
+// of the swap with the given id
+func SwapCondition(id []byte) weave.Condition {
+	return weave.NewCondition("htlc", "seq", id)
+}
+
+//-------------------- Object Wrapper -------
+
+// AsSwap will safely type-cast any value from Bucket to a Swap
+func AsSwap(obj orm.Object) *Swap {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Swap)
+}
+
+// NewSwap wraps the swap into an object for the bucket
+func NewSwap(id []byte, swap *Swap) orm.Object {
+	return orm.NewSimpleObj(id, swap)
+}
+
+//--- Bucket - type-safe bucket
+
+// Bucket is a type-safe wrapper around orm.Bucket
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name,
+// and all indexes
+func NewBucket() Bucket {
+	bucket := orm.NewBucket(BucketName, NewSwap(nil, new(Swap))).
+		WithIndex(PreimageHashIndex, idxPreimageHash, false).
+		WithIndex(SenderIndex, idxSender, false).
+		WithIndex(RecipientIndex, idxRecipient, false)
+	return Bucket{
+		Bucket: bucket,
+	}
+}
+
+// Create saves the swap under the next free id
+func (b Bucket) Create(db weave.KVStore, swap *Swap) (orm.Object, error) {
+	seq := b.Sequence(orm.SeqID)
+	obj := NewSwap(seq.NextVal(db), swap)
+	err := b.Save(db, obj)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// GetSwap loads the swap with the given id, or
+// returns an error if there is none
+func (b Bucket) GetSwap(db weave.ReadOnlyKVStore, id []byte) (orm.Object, error) {
+	obj, err := b.Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if AsSwap(obj) == nil {
+		return nil, ErrNoSuchSwap(id)
+	}
+	return obj, nil
+}
+
+// Save enforces the proper type
+func (b Bucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Swap); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+func getSwap(obj orm.Object) (*Swap, error) {
+	// these should use proper errors, but they never occur
+	// except in case of developer error (wrong data in wrong bucket)
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	swap, ok := obj.Value().(*Swap)
+	if !ok {
+		return nil, errors.New("Can only take index of Swap")
+	}
+	return swap, nil
+}
+
+func idxPreimageHash(obj orm.Object) ([]byte, error) {
+	swap, err := getSwap(obj)
+	if err != nil {
+		return nil, err
+	}
+	return swap.PreimageHash, nil
+}
+
+func idxSender(obj orm.Object) ([]byte, error) {
+	swap, err := getSwap(obj)
+	if err != nil {
+		return nil, err
+	}
+	return swap.Sender, nil
+}
+
+func idxRecipient(obj orm.Object) ([]byte, error) {
+	swap, err := getSwap(obj)
+	if err != nil {
+		return nil, err
+	}
+	return swap.Recipient, nil
+}
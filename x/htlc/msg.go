@@ -0,0 +1,66 @@
+package htlc
+
+import (
+	"github.com/confio/weave"
+)
+
+// Ensure we implement the Msg interface
+var _ weave.Msg = (*CreateSwapMsg)(nil)
+var _ weave.Msg = (*ReleaseSwapMsg)(nil)
+var _ weave.Msg = (*ReturnSwapMsg)(nil)
+
+const (
+	pathCreateSwapMsg  = "htlc/create"
+	pathReleaseSwapMsg = "htlc/release"
+	pathReturnSwapMsg  = "htlc/return"
+
+	createSwapCost  int64 = 300
+	releaseSwapCost int64 = 100
+	returnSwapCost  int64 = 100
+)
+
+// Path returns the routing path for this message
+func (CreateSwapMsg) Path() string {
+	return pathCreateSwapMsg
+}
+
+// Validate makes sure that this is sensible.
+// src is optional, as it defaults to the signer
+func (m *CreateSwapMsg) Validate() error {
+	if m.Src != nil {
+		if err := weave.Address(m.Src).Validate(); err != nil {
+			return err
+		}
+	}
+	return validateTerms(m.PreimageHash, m.Recipient, m.Amount, m.Timeout, m.Memo)
+}
+
+// Path returns the routing path for this message
+func (ReleaseSwapMsg) Path() string {
+	return pathReleaseSwapMsg
+}
+
+// Validate makes sure that this is sensible.
+// The preimage is checked against the swap
+func (m *ReleaseSwapMsg) Validate() error {
+	if len(m.SwapId) == 0 {
+		return ErrMissingSwapID()
+	}
+	if len(m.Preimage) == 0 {
+		return ErrInvalidPreimage()
+	}
+	return nil
+}
+
+// Path returns the routing path for this message
+func (ReturnSwapMsg) Path() string {
+	return pathReturnSwapMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *ReturnSwapMsg) Validate() error {
+	if len(m.SwapId) == 0 {
+		return ErrMissingSwapID()
+	}
+	return nil
+}
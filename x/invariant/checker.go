@@ -0,0 +1,85 @@
+package invariant
+
+import (
+	"fmt"
+
+	"github.com/confio/weave"
+)
+
+// Check verifies one property of the state, and returns
+// an error naming the offending keys if it doesn't hold
+type Check func(db weave.ReadOnlyKVStore) error
+
+// Registry is an interface to register your checks,
+// the setup side of a Checker
+type Registry interface {
+	Register(name string, check Check)
+}
+
+// Violation is a check that failed, with the reason
+type Violation struct {
+	Name string
+	Err  error
+}
+
+// Error returns the name of the check and the reason
+func (v Violation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Name, v.Err)
+}
+
+// Checker holds all checks modules registered, and runs
+// them in the order they were registered
+type Checker struct {
+	names  []string
+	checks map[string]Check
+}
+
+var _ Registry = (*Checker)(nil)
+var _ weave.InvariantChecker = (*Checker)(nil)
+
+// NewChecker returns a Checker without any checks
+func NewChecker() *Checker {
+	return &Checker{
+		checks: make(map[string]Check),
+	}
+}
+
+// Register adds a new check under the given name.
+// panics if another check was already registered
+func (c *Checker) Register(name string, check Check) {
+	if _, ok := c.checks[name]; ok {
+		panic(fmt.Sprintf("Re-registering check: %s", name))
+	}
+	c.names = append(c.names, name)
+	c.checks[name] = check
+}
+
+// RegisterAll lets each module register its checks
+func (c *Checker) RegisterAll(fns ...func(Registry)) *Checker {
+	for _, fn := range fns {
+		fn(c)
+	}
+	return c
+}
+
+// Run calls all checks, and returns every one that failed
+func (c *Checker) Run(db weave.ReadOnlyKVStore) []Violation {
+	var res []Violation
+	for _, name := range c.names {
+		err := c.checks[name](db)
+		if err != nil {
+			res = append(res, Violation{Name: name, Err: err})
+		}
+	}
+	return res
+}
+
+// CheckInvariants calls all checks, and returns an error
+// with all failures if any of them fail
+func (c *Checker) CheckInvariants(db weave.ReadOnlyKVStore) error {
+	violations := c.Run(db)
+	if len(violations) > 0 {
+		return ErrViolation(violations)
+	}
+	return nil
+}
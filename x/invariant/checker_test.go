@@ -0,0 +1,76 @@
+package invariant
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+)
+
+// hasKey requires the key to be in the store
+func hasKey(key string) Check {
+	return func(db weave.ReadOnlyKVStore) error {
+		if db.Get([]byte(key)) == nil {
+			return fmt.Errorf("Missing %s", key)
+		}
+		return nil
+	}
+}
+
+func TestChecker(t *testing.T) {
+	checker := NewChecker().RegisterAll(
+		func(r Registry) { r.Register("foo", hasKey("foo")) },
+		func(r Registry) { r.Register("bar", hasKey("bar")) },
+	)
+	assert.Panics(t, func() { checker.Register("foo", hasKey("other")) })
+
+	// all violations in the order registered
+	db := store.MemStore()
+	violations := checker.Run(db)
+	require.Equal(t, 2, len(violations))
+	assert.Equal(t, "foo", violations[0].Name)
+	assert.Equal(t, "bar", violations[1].Name)
+	err := checker.CheckInvariants(db)
+	require.True(t, IsViolationErr(err), "%+v", err)
+	assert.Contains(t, err.Error(), "foo: Missing foo; bar: Missing bar")
+
+	db.Set([]byte("foo"), []byte{1})
+	violations = checker.Run(db)
+	require.Equal(t, 1, len(violations))
+	assert.Equal(t, "bar", violations[0].Name)
+
+	db.Set([]byte("bar"), []byte{1})
+	assert.Empty(t, checker.Run(db))
+	assert.NoError(t, checker.CheckInvariants(db))
+}
+
+func TestTicker(t *testing.T) {
+	checker := NewChecker()
+	checker.Register("foo", hasKey("foo"))
+	assert.Panics(t, func() { NewTicker(checker, 0) })
+
+	db := store.MemStore()
+	tick := func(ticker Ticker, height int64) error {
+		ctx := weave.WithHeight(context.Background(), height)
+		_, err := ticker.Tick(ctx, db)
+		return err
+	}
+
+	// only logs by default
+	ticker := NewTicker(checker, 5)
+	assert.NoError(t, tick(ticker, 5))
+
+	// and only fails on every n-th block with halt
+	ticker = ticker.WithHalt()
+	assert.NoError(t, tick(ticker, 4))
+	err := tick(ticker, 10)
+	assert.True(t, IsViolationErr(err), "%+v", err)
+
+	db.Set([]byte("foo"), []byte{1})
+	assert.NoError(t, tick(ticker, 10))
+}
@@ -0,0 +1,15 @@
+/*
+Package invariant verifies that the state is consistent, like
+all wallets adding up to the issued supply, or all indexes
+pointing to the right objects.
+
+Each module registers its checks on a Checker, with
+a RegisterInvariants function. The app can run them every
+n blocks with the Ticker, which logs every violation and may
+halt the node, or on demand with CheckInvariants, which the
+StoreApp calls for the check command.
+
+A check that fails names the keys that are wrong, so you can
+find them in the state.
+*/
+package invariant
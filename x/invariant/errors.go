@@ -0,0 +1,32 @@
+package invariant
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/invariant reserves 130 ~ 139.
+const (
+	CodeViolation uint32 = 130
+)
+
+var (
+	errViolation = fmt.Errorf("Invariant broken")
+)
+
+// ErrViolation is returned when any check fails,
+// listing all of them with their reasons
+func ErrViolation(violations []Violation) error {
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.Error()
+	}
+	msg := strings.Join(msgs, "; ")
+	return errors.WithLog(msg, errViolation, CodeViolation)
+}
+func IsViolationErr(err error) bool {
+	return errors.IsSameError(errViolation, err)
+}
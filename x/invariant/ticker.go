@@ -0,0 +1,59 @@
+package invariant
+
+import (
+	"fmt"
+
+	"github.com/confio/weave"
+)
+
+// Ticker runs all checks at the beginning of every n-th block,
+// and logs each violation as an error.
+//
+// The checks read the whole state, so pick n with care.
+type Ticker struct {
+	checker *Checker
+	every   int64
+	halt    bool
+}
+
+var _ weave.Ticker = Ticker{}
+
+// NewTicker runs the checks every n blocks,
+// panics if n is not positive
+func NewTicker(checker *Checker, every int64) Ticker {
+	if every <= 0 {
+		panic(fmt.Sprintf("Invalid check interval: %d", every))
+	}
+	return Ticker{
+		checker: checker,
+		every:   every,
+	}
+}
+
+// WithHalt returns a copy of the Ticker that also returns an
+// error on any violation, which makes BaseApp stop the node
+// rather than build on a broken state
+func (t Ticker) WithHalt() Ticker {
+	t.halt = true
+	return t
+}
+
+// Tick runs the checks if the height is a multiple of n
+func (t Ticker) Tick(ctx weave.Context, store weave.KVStore) (weave.TickResult, error) {
+	var res weave.TickResult
+	height, _ := weave.GetHeight(ctx)
+	if height%t.every != 0 {
+		return res, nil
+	}
+
+	violations := t.checker.Run(store)
+	logger := weave.GetLogger(ctx)
+	for _, v := range violations {
+		logger.Error("Invariant broken",
+			"check", v.Name, "height", height, "err", v.Err)
+	}
+	if len(violations) > 0 && t.halt {
+		return res, ErrViolation(violations)
+	}
+	return res, nil
+}
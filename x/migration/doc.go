@@ -0,0 +1,18 @@
+/*
+Package migration upgrades the state of a live chain when the
+code changes, like after adding an index to a bucket, changing
+a model, or renaming a bucket.
+
+Each module registers its migrations by version, starting at 1.
+The version every module is at is stored in state, so each
+migration runs exactly once. The Ticker applies all pending
+ones at a chosen height, or in the first block after the node
+starts, each in its own savepoint along with the new version.
+A crash or a failing migration never leaves half of it behind,
+and the next run starts where the last one stopped.
+
+A new chain starts with data in the current format, so the
+Registry is also an Initializer that marks every module as
+up to date in genesis.
+*/
+package migration
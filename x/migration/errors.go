@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/migration reserves 140 ~ 149.
+const (
+	CodeInvalidSchema   uint32 = 140
+	CodeMigrationFailed        = 141
+)
+
+var (
+	errInvalidSchema   = fmt.Errorf("Invalid schema")
+	errMigrationFailed = fmt.Errorf("Migration failed")
+)
+
+// ErrInvalidSchema is returned for a version that is
+// not positive
+func ErrInvalidSchema(version int64) error {
+	msg := fmt.Sprintf("version %d", version)
+	return errors.WithLog(msg, errInvalidSchema, CodeInvalidSchema)
+}
+func IsInvalidSchemaErr(err error) bool {
+	return errors.IsSameError(errInvalidSchema, err)
+}
+
+// ErrMigrationFailed wraps the error of a migration, with
+// the module and version it was for
+func ErrMigrationFailed(module string, version int64, err error) error {
+	msg := fmt.Sprintf("%s to version %d: %v", module, version, err)
+	return errors.WithLog(msg, errMigrationFailed, CodeMigrationFailed)
+}
+func IsMigrationFailedErr(err error) bool {
+	return errors.IsSameError(errMigrationFailed, err)
+}
@@ -0,0 +1,78 @@
+package migration
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+// BucketName is where we store the schema of every module
+const BucketName = "migrate"
+
+//---- Schema
+
+var _ orm.CloneableData = (*Schema)(nil)
+
+// Validate requires a positive version
+func (s *Schema) Validate() error {
+	if s.Version <= 0 {
+		return ErrInvalidSchema(s.Version)
+	}
+	return nil
+}
+
+// Copy makes a new Schema with the same data
+func (s *Schema) Copy() orm.CloneableData {
+	return &Schema{Version: s.Version}
+}
+
+//-------------------- Object Wrapper -------
+
+// AsSchema will safely type-cast any value from Bucket
+func AsSchema(obj orm.Object) *Schema {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Schema)
+}
+
+//--- Bucket - type-safe bucket
+
+// Bucket stores the schema by module name
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName, orm.NewSimpleObj(nil, new(Schema))),
+	}
+}
+
+// GetVersion returns the version of the data of the module,
+// 0 if no migration was applied yet
+func (b Bucket) GetVersion(db weave.ReadOnlyKVStore, module string) (int64, error) {
+	obj, err := b.Get(db, []byte(module))
+	if err != nil {
+		return 0, err
+	}
+	s := AsSchema(obj)
+	if s == nil {
+		return 0, nil
+	}
+	return s.Version, nil
+}
+
+// SetVersion records that the module is now at version
+func (b Bucket) SetVersion(db weave.KVStore, module string, version int64) error {
+	obj := orm.NewSimpleObj([]byte(module), &Schema{Version: version})
+	return b.Save(db, obj)
+}
+
+// Save enforces the proper type
+func (b Bucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Schema); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
@@ -0,0 +1,182 @@
+package migration
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+)
+
+// Migration upgrades the data of one module by one version,
+// for example with orm.Bucket.RebuildIndexes, Rewrite or MoveTo
+type Migration func(ctx weave.Context, db weave.KVStore) error
+
+// Registry holds the migrations of all modules, and knows
+// which of them still have to run
+type Registry struct {
+	migrations map[string][]Migration
+	schemas    Bucket
+}
+
+var _ weave.Initializer = (*Registry)(nil)
+var _ weave.Exporter = (*Registry)(nil)
+
+// NewRegistry returns a Registry without any migrations
+func NewRegistry() *Registry {
+	return &Registry{
+		migrations: make(map[string][]Migration),
+		schemas:    NewBucket(),
+	}
+}
+
+// Register adds the migration that brings the module to the
+// given version. Versions start at 1, and must be registered
+// in order. panics otherwise
+func (r *Registry) Register(module string, version int64, m Migration) {
+	next := int64(len(r.migrations[module])) + 1
+	if version != next {
+		panic(fmt.Sprintf("Registering %s version %d, expected %d",
+			module, version, next))
+	}
+	r.migrations[module] = append(r.migrations[module], m)
+}
+
+// RegisterAll lets each module register its migrations
+func (r *Registry) RegisterAll(fns ...func(*Registry)) *Registry {
+	for _, fn := range fns {
+		fn(r)
+	}
+	return r
+}
+
+// modules returns the names of all modules, sorted
+func (r *Registry) modules() []string {
+	names := make([]string, 0, len(r.migrations))
+	for name := range r.migrations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pending returns how many migrations still have to run
+func (r *Registry) Pending(db weave.ReadOnlyKVStore) (int, error) {
+	var pending int
+	for _, module := range r.modules() {
+		version, err := r.schemas.GetVersion(db, module)
+		if err != nil {
+			return 0, err
+		}
+		if left := int64(len(r.migrations[module])) - version; left > 0 {
+			pending += int(left)
+		}
+	}
+	return pending, nil
+}
+
+// Run applies all pending migrations, by module and in the
+// order of their versions.
+//
+// Each migration runs in its own savepoint along with the
+// new version, so if one fails or the node crashes, the
+// next run starts right where it stopped. It returns the
+// error of the first migration that fails.
+func (r *Registry) Run(ctx weave.Context, db weave.KVStore) error {
+	for _, module := range r.modules() {
+		version, err := r.schemas.GetVersion(db, module)
+		if err != nil {
+			return err
+		}
+		for v := version + 1; v <= int64(len(r.migrations[module])); v++ {
+			err := r.apply(ctx, db, module, v)
+			if err != nil {
+				return ErrMigrationFailed(module, v, err)
+			}
+			weave.GetLogger(ctx).Info("Migrated",
+				"module", module, "version", v)
+		}
+	}
+	return nil
+}
+
+// apply runs one migration and records the new version,
+// both only written if it succeeds
+func (r *Registry) apply(ctx weave.Context, db weave.KVStore,
+	module string, version int64) (err error) {
+
+	defer errors.Recover(&err)
+
+	m := r.migrations[module][version-1]
+	cstore, ok := db.(weave.CacheableKVStore)
+	if !ok {
+		err = m(ctx, db)
+		if err != nil {
+			return err
+		}
+		return r.schemas.SetVersion(db, module, version)
+	}
+
+	cache := cstore.CacheWrap()
+	err = m(ctx, cache)
+	if err == nil {
+		err = r.schemas.SetVersion(cache, module, version)
+	}
+	if err == nil {
+		cache.Write()
+	} else {
+		cache.Discard()
+	}
+	return err
+}
+
+// optKey holds the version of every module in genesis
+const optKey = "migrations"
+
+// FromGenesis loads the versions of a chain restarted from
+// an export of its state, so only newer migrations run.
+// Without them, it marks all modules as up to date, as a new
+// chain starts with data in the current format.
+func (r *Registry) FromGenesis(opts weave.Options, kv weave.KVStore) error {
+	if len(opts[optKey]) > 0 {
+		var versions map[string]int64
+		err := opts.ReadOptions(optKey, &versions)
+		if err != nil {
+			return err
+		}
+		for module, version := range versions {
+			err := r.schemas.SetVersion(kv, module, version)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, module := range r.modules() {
+		err := r.schemas.SetVersion(kv, module, int64(len(r.migrations[module])))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToGenesis writes the version of every module to the options
+func (r *Registry) ToGenesis(opts weave.Options, kv weave.ReadOnlyKVStore) error {
+	objs, err := r.schemas.All(kv)
+	if err != nil || len(objs) == 0 {
+		return err
+	}
+	versions := make(map[string]int64, len(objs))
+	for _, obj := range objs {
+		versions[string(obj.Key())] = AsSchema(obj).Version
+	}
+	return opts.WriteOptions(optKey, versions)
+}
+
+// RegisterQuery will register the schemas as "/migrations",
+// keyed by module
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("migrations", qr)
+}
@@ -0,0 +1,142 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+)
+
+// setKey is a migration that writes key
+func setKey(key string) Migration {
+	return func(ctx weave.Context, db weave.KVStore) error {
+		db.Set([]byte(key), []byte("done"))
+		return nil
+	}
+}
+
+// failAfter writes key, then fails
+func failAfter(key string) Migration {
+	return func(ctx weave.Context, db weave.KVStore) error {
+		db.Set([]byte(key), []byte("done"))
+		return fmt.Errorf("Broken %s", key)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	reg := NewRegistry().RegisterAll(
+		func(r *Registry) {
+			r.Register("foo", 1, setKey("foo1"))
+			r.Register("foo", 2, setKey("foo2"))
+		},
+		func(r *Registry) { r.Register("bar", 1, setKey("bar1")) },
+	)
+	assert.Panics(t, func() { reg.Register("bar", 1, setKey("again")) })
+	assert.Panics(t, func() { reg.Register("bar", 3, setKey("skip")) })
+	assert.Panics(t, func() { reg.Register("new", 0, setKey("zero")) })
+
+	ctx := context.Background()
+	db := store.MemStore()
+	schemas := NewBucket()
+
+	// foo already got the first one
+	require.NoError(t, schemas.SetVersion(db, "foo", 1))
+	pending, err := reg.Pending(db)
+	require.NoError(t, err)
+	assert.Equal(t, 2, pending)
+
+	require.NoError(t, reg.Run(ctx, db))
+	assert.Nil(t, db.Get([]byte("foo1")))
+	assert.NotNil(t, db.Get([]byte("foo2")))
+	assert.NotNil(t, db.Get([]byte("bar1")))
+	for module, expected := range map[string]int64{"foo": 2, "bar": 1} {
+		version, err := schemas.GetVersion(db, module)
+		require.NoError(t, err)
+		assert.Equal(t, expected, version, module)
+	}
+
+	// nothing left, so running again changes nothing
+	db.Delete([]byte("foo2"))
+	require.NoError(t, reg.Run(ctx, db))
+	assert.Nil(t, db.Get([]byte("foo2")))
+	pending, err = reg.Pending(db)
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+}
+
+func TestFailedMigration(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("foo", 1, setKey("foo1"))
+	reg.Register("foo", 2, failAfter("foo2"))
+	reg.Register("foo", 3, setKey("foo3"))
+
+	ctx := context.Background()
+	db := store.MemStore()
+	schemas := NewBucket()
+
+	// we keep the first one, but nothing of the broken one
+	err := reg.Run(ctx, db)
+	require.True(t, IsMigrationFailedErr(err), "%+v", err)
+	assert.Contains(t, err.Error(), "foo to version 2: Broken foo2")
+	assert.NotNil(t, db.Get([]byte("foo1")))
+	assert.Nil(t, db.Get([]byte("foo2")))
+	assert.Nil(t, db.Get([]byte("foo3")))
+	version, err := schemas.GetVersion(db, "foo")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	// panics are just as bad
+	reg = NewRegistry()
+	reg.Register("bar", 1, func(weave.Context, weave.KVStore) error {
+		panic("boom")
+	})
+	err = reg.Run(ctx, db)
+	require.True(t, IsMigrationFailedErr(err), "%+v", err)
+	version, err = schemas.GetVersion(db, "bar")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), version)
+}
+
+func TestGenesis(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("foo", 1, setKey("foo1"))
+	reg.Register("foo", 2, setKey("foo2"))
+
+	// a new chain needs none of them
+	db := store.MemStore()
+	require.NoError(t, reg.FromGenesis(weave.Options{}, db))
+	pending, err := reg.Pending(db)
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+	require.NoError(t, reg.Run(context.Background(), db))
+	assert.Nil(t, db.Get([]byte("foo1")))
+	assert.Nil(t, db.Get([]byte("foo2")))
+}
+
+func TestTicker(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("foo", 1, setKey("foo1"))
+	ticker := NewTicker(reg, 10)
+	db := store.MemStore()
+
+	ctx := weave.WithHeight(context.Background(), 9)
+	_, err := ticker.Tick(ctx, db)
+	require.NoError(t, err)
+	assert.Nil(t, db.Get([]byte("foo1")))
+
+	ctx = weave.WithHeight(context.Background(), 10)
+	_, err = ticker.Tick(ctx, db)
+	require.NoError(t, err)
+	assert.NotNil(t, db.Get([]byte("foo1")))
+
+	// a failure stops the chain
+	reg.Register("foo", 2, failAfter("foo2"))
+	ctx = weave.WithHeight(context.Background(), 11)
+	_, err = ticker.Tick(ctx, db)
+	assert.True(t, IsMigrationFailedErr(err), "%+v", err)
+}
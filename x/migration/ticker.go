@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"github.com/confio/weave"
+)
+
+// Ticker runs all pending migrations at the beginning of the
+// first block at or after a given height.
+//
+// With height 0, they run in the first block after the node
+// starts with new migrations. All nodes must upgrade before
+// that block, so they all change the state the same way.
+type Ticker struct {
+	registry *Registry
+	height   int64
+}
+
+var _ weave.Ticker = Ticker{}
+
+// NewTicker runs the migrations in registry from height on
+func NewTicker(registry *Registry, height int64) Ticker {
+	return Ticker{
+		registry: registry,
+		height:   height,
+	}
+}
+
+// Tick runs all pending migrations once we reach the height.
+//
+// A failing migration returns an error, which stops the node,
+// as the state is not what the code expects.
+func (t Ticker) Tick(ctx weave.Context, db weave.KVStore) (weave.TickResult, error) {
+	var res weave.TickResult
+	height, _ := weave.GetHeight(ctx)
+	if height < t.height {
+		return res, nil
+	}
+	return res, t.registry.Run(ctx, db)
+}
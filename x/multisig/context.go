@@ -0,0 +1,52 @@
+package multisig
+
+import (
+	"context"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+//------------------- Context --------
+// Add context information specific to this package
+
+type contextKey int // local to the multisig module
+
+const (
+	contextKeyMultisig contextKey = iota
+)
+
+// withMultisig is a private method, as only this module
+// can add a multisig contract
+func withMultisig(ctx weave.Context, id []byte) weave.Context {
+	val, _ := ctx.Value(contextKeyMultisig).([]weave.Condition)
+	// copy, so we never modify the list of a parent context
+	conds := make([]weave.Condition, len(val), len(val)+1)
+	copy(conds, val)
+	conds = append(conds, MultiSigCondition(id))
+	return context.WithValue(ctx, contextKeyMultisig, conds)
+}
+
+// Authenticate implements x.Authenticator and provides
+// authentication based on multisig contracts.
+type Authenticate struct{}
+
+var _ x.Authenticator = Authenticate{}
+
+// GetConditions returns the contracts that authorized
+// the current Context. May be empty
+func (a Authenticate) GetConditions(ctx weave.Context) []weave.Condition {
+	val, _ := ctx.Value(contextKeyMultisig).([]weave.Condition)
+	return val
+}
+
+// HasAddress returns true if the given address is
+// a contract that authorized the current Context.
+func (a Authenticate) HasAddress(ctx weave.Context, addr weave.Address) bool {
+	for _, s := range a.GetConditions(ctx) {
+		if addr.Equals(s.Address()) {
+			return true
+		}
+	}
+	return false
+}
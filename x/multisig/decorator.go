@@ -0,0 +1,80 @@
+package multisig
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+//----------------- Decorator ----------------
+//
+// This is just a binding from the functionality into the
+// Application stack, not much business logic here.
+
+// Decorator checks the multisig contracts listed in the tx
+// and adds them to the context if enough signers authorized it.
+//
+// It must run after the decorators that add the signers,
+// usually sigs.Decorator
+type Decorator struct {
+	auth   x.Authenticator
+	bucket ContractBucket
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator returns a Decorator that uses auth to find
+// the signers of the contracts. auth should include
+// multisig.Authenticate to allow contracts to sign for
+// other contracts.
+func NewDecorator(auth x.Authenticator) Decorator {
+	return Decorator{
+		auth:   auth,
+		bucket: NewContractBucket(),
+	}
+}
+
+// Check enforces the multisig contracts before calling down the stack
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	ctx, err := d.withMultisig(ctx, store, tx)
+	if err != nil {
+		return weave.CheckResult{}, err
+	}
+	return next.Check(ctx, store, tx)
+}
+
+// Deliver enforces the multisig contracts before calling down the stack
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	ctx, err := d.withMultisig(ctx, store, tx)
+	if err != nil {
+		return weave.DeliverResult{}, err
+	}
+	return next.Deliver(ctx, store, tx)
+}
+
+// withMultisig adds all contracts from the tx to the context,
+// or fails if any of them was not authorized
+func (d Decorator) withMultisig(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.Context, error) {
+
+	mtx, ok := tx.(MultiSigTx)
+	if !ok {
+		return ctx, nil
+	}
+
+	for _, id := range mtx.GetMultisig() {
+		contract, err := d.bucket.GetContract(store, id)
+		if err != nil {
+			return nil, err
+		}
+		n := int(contract.ActivationThreshold)
+		if !x.HasNConditions(ctx, d.auth, contract.Conditions(), n) {
+			return nil, ErrUnauthorizedMultiSig(id)
+		}
+		ctx = withMultisig(ctx, id)
+	}
+	return ctx, nil
+}
@@ -0,0 +1,126 @@
+package multisig
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+
+	db := store.MemStore()
+	bucket := NewContractBucket()
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	b := weave.NewCondition("sig", "ed25519", []byte{4, 5, 6})
+	c := weave.NewCondition("sig", "ed25519", []byte{7, 8, 9})
+
+	// 2 of a, b, c
+	obj, err := bucket.Create(db, &Contract{
+		Sigs:                [][]byte{a, b, c},
+		ActivationThreshold: 2,
+	})
+	require.NoError(t, err)
+	twoOfThree := obj.Key()
+	// 1 of the first contract and c
+	obj, err = bucket.Create(db, &Contract{
+		Sigs:                [][]byte{MultiSigCondition(twoOfThree), c},
+		ActivationThreshold: 1,
+	})
+	require.NoError(t, err)
+	nested := obj.Key()
+	missing := []byte("missing")
+
+	cases := []struct {
+		signers   []weave.Condition
+		contracts [][]byte
+		check     func(error) bool
+		perms     []weave.Condition
+	}{
+		// no contracts, nothing to do
+		0: {[]weave.Condition{a}, nil, nil, nil},
+		// enough signers
+		1: {[]weave.Condition{a, c}, [][]byte{twoOfThree}, nil,
+			[]weave.Condition{MultiSigCondition(twoOfThree)}},
+		// too few signers
+		2: {[]weave.Condition{b}, [][]byte{twoOfThree}, IsUnauthorizedMultiSigErr, nil},
+		// unknown contract
+		3: {[]weave.Condition{a, b}, [][]byte{missing}, IsContractNotFoundErr, nil},
+		// contracts may sign for other contracts
+		4: {[]weave.Condition{a, b}, [][]byte{twoOfThree, nested}, nil,
+			[]weave.Condition{MultiSigCondition(twoOfThree), MultiSigCondition(nested)}},
+		// but only in order
+		5: {[]weave.Condition{a, b}, [][]byte{nested, twoOfThree}, IsUnauthorizedMultiSigErr, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			auth := helpers.CtxAuth("auth")
+			ctx := auth.SetConditions(context.Background(), tc.signers...)
+			d := NewDecorator(x.ChainAuth(auth, Authenticate{}))
+			tx := &multisigTx{Tx: helpers.MockTx(helpers.MockMsg(nil)), ids: tc.contracts}
+
+			for _, kv := range []weave.KVStore{db, db.CacheWrap()} {
+				h := new(multisigHandler)
+				_, err := d.Check(ctx, kv, tx, h)
+				if tc.check != nil {
+					assert.True(t, tc.check(err), "%+v", err)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, tc.perms, h.perms)
+				}
+
+				h = new(multisigHandler)
+				_, err = d.Deliver(ctx, kv, tx, h)
+				if tc.check != nil {
+					assert.True(t, tc.check(err), "%+v", err)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, tc.perms, h.perms)
+				}
+			}
+		})
+	}
+}
+
+//---------------- helpers --------
+
+// multisigTx adds contract ids to a tx
+type multisigTx struct {
+	weave.Tx
+	ids [][]byte
+}
+
+var _ MultiSigTx = (*multisigTx)(nil)
+
+func (m *multisigTx) GetMultisig() [][]byte {
+	return m.ids
+}
+
+// multisigHandler stores the seen contracts on each call
+type multisigHandler struct {
+	perms []weave.Condition
+}
+
+var _ weave.Handler = (*multisigHandler)(nil)
+
+func (h *multisigHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	h.perms = Authenticate{}.GetConditions(ctx)
+	return weave.CheckResult{}, nil
+}
+
+func (h *multisigHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	h.perms = Authenticate{}.GetConditions(ctx)
+	return weave.DeliverResult{}, nil
+}
@@ -0,0 +1,13 @@
+/*
+Package multisig allows a group of signers to act as one
+identity.
+
+A contract stores a list of conditions and a threshold. When a
+tx lists a contract and at least threshold of those conditions
+authorized it, the Decorator adds the contract's Condition to
+the context, so it can own wallets like any public key.
+
+Conditions of other contracts are allowed as signers, as long
+as the tx lists those contracts first.
+*/
+package multisig
@@ -0,0 +1,54 @@
+package multisig
+
+import (
+	"fmt"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/multisig reserves 60 ~ 69.
+const (
+	CodeInvalidMsg           uint32 = 60
+	CodeContractNotFound            = 61
+	CodeUnauthorizedMultiSig        = 62
+)
+
+var (
+	errMissingSigs          = fmt.Errorf("Missing sigs")
+	errInvalidThreshold     = fmt.Errorf("Activation threshold must be between 1 and the number of sigs")
+	errDuplicateSig         = fmt.Errorf("Duplicate sig")
+	errContractNotFound     = fmt.Errorf("Multisig contract not found")
+	errUnauthorizedMultiSig = fmt.Errorf("Multisig authentication failed")
+)
+
+func ErrMissingSigs() error {
+	return errors.WithCode(errMissingSigs, CodeInvalidMsg)
+}
+func ErrInvalidThreshold(threshold int64) error {
+	msg := fmt.Sprintf("%d", threshold)
+	return errors.WithLog(msg, errInvalidThreshold, CodeInvalidMsg)
+}
+func ErrDuplicateSig(sig []byte) error {
+	return errors.WithLog(weave.Condition(sig).String(), errDuplicateSig, CodeInvalidMsg)
+}
+func IsInvalidMsgErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidMsg)
+}
+
+func ErrContractNotFound(id []byte) error {
+	msg := fmt.Sprintf("%X", id)
+	return errors.WithLog(msg, errContractNotFound, CodeContractNotFound)
+}
+func IsContractNotFoundErr(err error) bool {
+	return errors.IsSameError(errContractNotFound, err)
+}
+
+func ErrUnauthorizedMultiSig(id []byte) error {
+	msg := fmt.Sprintf("%X", id)
+	return errors.WithLog(msg, errUnauthorizedMultiSig, CodeUnauthorizedMultiSig)
+}
+func IsUnauthorizedMultiSigErr(err error) bool {
+	return errors.IsSameError(errUnauthorizedMultiSig, err)
+}
@@ -0,0 +1,89 @@
+package multisig
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry) {
+	r.Handle(pathCreateContractMsg, NewCreateContractHandler(NewContractBucket()))
+}
+
+// RegisterQuery will register this bucket as "/contracts"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewContractBucket().Register("contracts", qr)
+}
+
+// CreateContractHandler stores new contracts.
+//
+// Anyone can create a contract, it only gains power
+// once the signers use it.
+type CreateContractHandler struct {
+	bucket ContractBucket
+}
+
+var _ weave.Handler = CreateContractHandler{}
+
+// NewCreateContractHandler creates a handler for CreateContractMsg
+func NewCreateContractHandler(bucket ContractBucket) CreateContractHandler {
+	return CreateContractHandler{
+		bucket: bucket,
+	}
+}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h CreateContractHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, err := h.validate(tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += createContractCost
+	return res, nil
+}
+
+// Deliver stores the contract and returns its id as Data
+func (h CreateContractHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, err := h.validate(tx)
+	if err != nil {
+		return res, err
+	}
+
+	contract := &Contract{
+		Sigs:                msg.Sigs,
+		ActivationThreshold: msg.ActivationThreshold,
+	}
+	obj, err := h.bucket.Create(store, contract)
+	if err != nil {
+		return res, err
+	}
+
+	res.Data = obj.Key()
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h CreateContractHandler) validate(tx weave.Tx) (*CreateContractMsg, error) {
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*CreateContractMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
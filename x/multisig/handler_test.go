@@ -0,0 +1,68 @@
+package multisig
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestCreateContract(t *testing.T) {
+	var helpers x.TestHelpers
+
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	b := weave.NewCondition("sig", "ed25519", []byte{4, 5, 6})
+	sigs := [][]byte{a, b}
+
+	cases := []struct {
+		msg   weave.Msg
+		check func(error) bool
+	}{
+		0: {nil, errors.IsUnknownTxTypeErr},
+		1: {new(CreateContractMsg), IsInvalidMsgErr},
+		2: {&CreateContractMsg{Sigs: sigs}, IsInvalidMsgErr},
+		3: {&CreateContractMsg{Sigs: sigs, ActivationThreshold: 3}, IsInvalidMsgErr},
+		4: {&CreateContractMsg{Sigs: [][]byte{a, []byte("foo")}, ActivationThreshold: 1},
+			errors.IsUnrecognizedConditionErr},
+		// a alone must not reach the threshold
+		5: {&CreateContractMsg{Sigs: [][]byte{a, a, b}, ActivationThreshold: 2},
+			IsInvalidMsgErr},
+		6: {&CreateContractMsg{Sigs: sigs, ActivationThreshold: 2}, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			h := NewCreateContractHandler(NewContractBucket())
+			kv := store.MemStore()
+			tx := helpers.MockTx(tc.msg)
+
+			_, err := h.Check(context.Background(), kv, tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+				_, err = h.Deliver(context.Background(), kv, tx)
+				assert.True(t, tc.check(err), "%+v", err)
+				return
+			}
+			require.NoError(t, err)
+
+			// we get increasing ids
+			res, err := h.Deliver(context.Background(), kv, tx)
+			require.NoError(t, err)
+			res2, err := h.Deliver(context.Background(), kv, tx)
+			require.NoError(t, err)
+			assert.NotEqual(t, res.Data, res2.Data)
+
+			contract, err := NewContractBucket().GetContract(kv, res.Data)
+			require.NoError(t, err)
+			assert.Equal(t, sigs, contract.Sigs)
+			assert.Equal(t, int64(2), contract.ActivationThreshold)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package multisig
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+// BucketName is where we store the contracts
+const BucketName = "contract"
+
+//---- Contract
+
+var _ orm.CloneableData = (*Contract)(nil)
+
+// Validate requires valid conditions as sigs, and a
+// threshold that can be reached
+func (c *Contract) Validate() error {
+	return validateSigs(c.Sigs, c.ActivationThreshold)
+}
+
+// Copy makes a new Contract with the same signers
+func (c *Contract) Copy() orm.CloneableData {
+	sigs := make([][]byte, len(c.Sigs))
+	copy(sigs, c.Sigs)
+	return &Contract{
+		Sigs:                sigs,
+		ActivationThreshold: c.ActivationThreshold,
+	}
+}
+
+// Conditions returns the sigs as weave.Conditions
+func (c *Contract) Conditions() []weave.Condition {
+	conds := make([]weave.Condition, len(c.Sigs))
+	for i, s := range c.Sigs {
+		conds[i] = weave.Condition(s)
+	}
+	return conds
+}
+
+func validateSigs(sigs [][]byte, threshold int64) error {
+	if len(sigs) == 0 {
+		return ErrMissingSigs()
+	}
+	if threshold <= 0 || threshold > int64(len(sigs)) {
+		return ErrInvalidThreshold(threshold)
+	}
+	// every sig only counts once towards the threshold
+	seen := make(map[string]bool, len(sigs))
+	for _, s := range sigs {
+		if err := weave.Condition(s).Validate(); err != nil {
+			return err
+		}
+		if seen[string(s)] {
+			return ErrDuplicateSig(s)
+		}
+		seen[string(s)] = true
+	}
+	return nil
+}
+
+// MultiSigCondition returns the condition fulfilled once
+// the contract with the given id authorized a tx
+func MultiSigCondition(id []byte) weave.Condition {
+	return weave.NewCondition("multisig", "contract", id)
+}
+
+//-------------------- Object Wrapper -------
+
+// AsContract will safely type-cast any value from Bucket to a Contract
+func AsContract(obj orm.Object) *Contract {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Contract)
+}
+
+// NewContract wraps the contract into an object for the bucket
+func NewContract(id []byte, contract *Contract) orm.Object {
+	return orm.NewSimpleObj(id, contract)
+}
+
+//--- ContractBucket - type-safe bucket
+
+// ContractBucket is a type-safe wrapper around orm.Bucket
+type ContractBucket struct {
+	orm.Bucket
+}
+
+// NewContractBucket initializes a ContractBucket with default name
+func NewContractBucket() ContractBucket {
+	return ContractBucket{
+		Bucket: orm.NewBucket(BucketName, NewContract(nil, new(Contract))),
+	}
+}
+
+// Create saves the contract under the next free id
+func (b ContractBucket) Create(db weave.KVStore, contract *Contract) (orm.Object, error) {
+	seq := b.Sequence(orm.SeqID)
+	obj := NewContract(seq.NextVal(db), contract)
+	err := b.Save(db, obj)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// GetContract loads the contract with the given id, or
+// returns an error if there is none
+func (b ContractBucket) GetContract(db weave.ReadOnlyKVStore, id []byte) (*Contract, error) {
+	obj, err := b.Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	contract := AsContract(obj)
+	if contract == nil {
+		return nil, ErrContractNotFound(id)
+	}
+	return contract, nil
+}
+
+// Save enforces the proper type
+func (b ContractBucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Contract); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
@@ -0,0 +1,31 @@
+package multisig
+
+import (
+	"github.com/confio/weave"
+)
+
+// Ensure we implement the Msg interface
+var _ weave.Msg = (*CreateContractMsg)(nil)
+
+const (
+	pathCreateContractMsg       = "multisig/create"
+	createContractCost    int64 = 300
+)
+
+// Path returns the routing path for this message
+func (CreateContractMsg) Path() string {
+	return pathCreateContractMsg
+}
+
+// Validate makes sure the contract can be used
+func (c *CreateContractMsg) Validate() error {
+	return validateSigs(c.Sigs, c.ActivationThreshold)
+}
+
+// MultiSigTx is implemented by txs that want to act as
+// multisig contracts
+type MultiSigTx interface {
+	// GetMultisig returns the ids of the contracts to check,
+	// in order
+	GetMultisig() [][]byte
+}
@@ -0,0 +1,22 @@
+/*
+Package scheduler lets modules run delayed work at the
+beginning of a future block.
+
+A module schedules a Task with the height, the path of an
+Executor, and some data it needs later, and gets an id to
+cancel it. The Ticker, which the app passes to BaseApp, runs
+all tasks due at each height in BeginBlock. Every task runs
+in its own savepoint, so a failing task doesn't leave partial
+changes, nor does it affect the other tasks. The outcome is
+recorded as a TaskResult under the id of the task.
+
+A failed task is scheduled again a few blocks later, until
+it used up all attempts (see Ticker.WithRetries). Results
+are only kept for a limited number of blocks (see
+Ticker.WithResultHistory), so the store doesn't grow with
+every task that ever ran.
+
+This covers things like expiring offers or releasing coins
+after a timeout, without anyone sending a transaction.
+*/
+package scheduler
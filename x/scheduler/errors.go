@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/scheduler reserves 90 ~ 99.
+const (
+	CodeInvalidTask    uint32 = 90
+	CodeNoSuchExecutor        = 91
+)
+
+var (
+	errInvalidHeight  = fmt.Errorf("Task must run in the future")
+	errInvalidPath    = fmt.Errorf("Invalid task path")
+	errNoSuchExecutor = fmt.Errorf("No executor for task")
+)
+
+// ErrInvalidHeight is returned when scheduling a task at or
+// before the current block
+func ErrInvalidHeight(height int64) error {
+	msg := fmt.Sprintf("%d", height)
+	return errors.WithLog(msg, errInvalidHeight, CodeInvalidTask)
+}
+
+// ErrInvalidPath is returned for a task with a malformed path
+func ErrInvalidPath(path string) error {
+	return errors.WithLog(path, errInvalidPath, CodeInvalidTask)
+}
+
+// IsInvalidTaskErr matches any error about a malformed task
+func IsInvalidTaskErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidTask)
+}
+
+// ErrNoSuchExecutor is recorded for tasks whose path
+// has no registered Executor
+func ErrNoSuchExecutor(path string) error {
+	return errors.WithLog(path, errNoSuchExecutor, CodeNoSuchExecutor)
+}
+func IsNoSuchExecutorErr(err error) bool {
+	return errors.IsSameError(errNoSuchExecutor, err)
+}
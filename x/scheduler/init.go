@@ -0,0 +1,30 @@
+package scheduler
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+const optKey = "scheduler"
+
+// Initializer loads all pending tasks and their results when a
+// chain is restarted from an export of its state
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+var _ weave.Exporter = Initializer{}
+
+// FromGenesis loads the state written by ToGenesis, if any
+func (Initializer) FromGenesis(opts weave.Options, kv weave.KVStore) error {
+	return orm.ImportBuckets(opts, optKey, kv, genesisBuckets()...)
+}
+
+// ToGenesis writes all pending tasks and their results to the options
+func (Initializer) ToGenesis(opts weave.Options, kv weave.ReadOnlyKVStore) error {
+	return orm.ExportBuckets(opts, optKey, kv, genesisBuckets()...)
+}
+
+// genesisBuckets are all buckets with state to export
+func genesisBuckets() []orm.Bucket {
+	return []orm.Bucket{NewBucket().Bucket, NewResultBucket().Bucket}
+}
@@ -0,0 +1,224 @@
+package scheduler
+
+import (
+	"encoding/binary"
+	"errors"
+	"regexp"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+const (
+	// BucketName is where we store the pending tasks
+	BucketName = "task"
+	// ResultBucketName is where we store the outcome of executed tasks
+	ResultBucketName = "task_res"
+	// HeightIndex finds all tasks (or results) of a block
+	HeightIndex = "height"
+)
+
+// isPath matches the paths the app.Router accepts
+var isPath = regexp.MustCompile(`^[a-zA-Z0-9_/]+$`).MatchString
+
+//---- Task
+
+var _ orm.CloneableData = (*Task)(nil)
+var _ orm.Rebaser = (*Task)(nil)
+
+// Validate requires a height and a path
+func (t *Task) Validate() error {
+	if t.Height <= 0 {
+		return ErrInvalidHeight(t.Height)
+	}
+	if !isPath(t.Path) {
+		return ErrInvalidPath(t.Path)
+	}
+	return nil
+}
+
+// Copy makes a new Task with the same data
+func (t *Task) Copy() orm.CloneableData {
+	return &Task{
+		Height:   t.Height,
+		Path:     t.Path,
+		Data:     t.Data,
+		Attempts: t.Attempts,
+	}
+}
+
+// Rebase moves the task to a chain restarted from an export,
+// a task that is overdue runs in the first block
+func (t *Task) Rebase(exportHeight int64) bool {
+	t.Height = orm.RebaseHeight(t.Height, exportHeight)
+	return true
+}
+
+//---- TaskResult
+
+var _ orm.CloneableData = (*TaskResult)(nil)
+var _ orm.Rebaser = (*TaskResult)(nil)
+
+// Validate requires a height and a path
+func (r *TaskResult) Validate() error {
+	if r.Height <= 0 {
+		return ErrInvalidHeight(r.Height)
+	}
+	if r.Path == "" {
+		return ErrInvalidPath(r.Path)
+	}
+	return nil
+}
+
+// Copy makes a new TaskResult with the same data
+func (r *TaskResult) Copy() orm.CloneableData {
+	return &TaskResult{
+		Height:      r.Height,
+		Path:        r.Path,
+		Successful:  r.Successful,
+		Code:        r.Code,
+		Log:         r.Log,
+		RetryHeight: r.RetryHeight,
+	}
+}
+
+// Rebase drops the results on a chain restarted from an
+// export, as their blocks are not part of it. A task that is
+// retried is still pending.
+func (r *TaskResult) Rebase(exportHeight int64) bool {
+	return false
+}
+
+// heightKey encodes the height for the index, so they are
+// sorted in the same order as the numbers
+func heightKey(height int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+	return bz
+}
+
+//-------------------- Object Wrapper -------
+
+// AsTask will safely type-cast any value from Bucket to a Task
+func AsTask(obj orm.Object) *Task {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Task)
+}
+
+// AsTaskResult will safely type-cast any value from ResultBucket
+func AsTaskResult(obj orm.Object) *TaskResult {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*TaskResult)
+}
+
+//--- Bucket - type-safe bucket
+
+// Bucket stores the pending tasks, indexed by height
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	proto := orm.NewSimpleObj(nil, new(Task))
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName, proto).
+			WithIndex(HeightIndex, idxTaskHeight, false),
+	}
+}
+
+// Schedule saves a task to run at the beginning of block height,
+// which must be after the current one. It returns the id of
+// the task, which can be used to cancel it.
+func (b Bucket) Schedule(ctx weave.Context, db weave.KVStore,
+	height int64, path string, data []byte) ([]byte, error) {
+
+	current, _ := weave.GetHeight(ctx)
+	if height <= current {
+		return nil, ErrInvalidHeight(height)
+	}
+	seq := b.Sequence(orm.SeqID)
+	obj := orm.NewSimpleObj(seq.NextVal(db), &Task{
+		Height: height,
+		Path:   path,
+		Data:   data,
+	})
+	err := b.Save(db, obj)
+	if err != nil {
+		return nil, err
+	}
+	return obj.Key(), nil
+}
+
+// Cancel removes a task before it runs, or before it is
+// retried. It is a noop if the task is done.
+func (b Bucket) Cancel(db weave.KVStore, id []byte) error {
+	obj, err := b.Get(db, id)
+	if err != nil || obj == nil {
+		return err
+	}
+	return b.Delete(db, id)
+}
+
+// Due returns all tasks scheduled for this height, in the
+// order they were scheduled
+func (b Bucket) Due(db weave.ReadOnlyKVStore, height int64) ([]orm.Object, error) {
+	return b.GetIndexed(db, HeightIndex, heightKey(height))
+}
+
+// Save enforces the proper type
+func (b Bucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Task); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+// ResultBucket stores the outcome of executed tasks,
+// under the same id as the task
+type ResultBucket struct {
+	orm.Bucket
+}
+
+// NewResultBucket initializes a ResultBucket with default name
+func NewResultBucket() ResultBucket {
+	proto := orm.NewSimpleObj(nil, new(TaskResult))
+	return ResultBucket{
+		Bucket: orm.NewBucket(ResultBucketName, proto).
+			WithIndex(HeightIndex, idxResultHeight, false),
+	}
+}
+
+// Save enforces the proper type
+func (b ResultBucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*TaskResult); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+func idxTaskHeight(obj orm.Object) ([]byte, error) {
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	task, ok := obj.Value().(*Task)
+	if !ok {
+		return nil, errors.New("Can only take index of Task")
+	}
+	return heightKey(task.Height), nil
+}
+
+func idxResultHeight(obj orm.Object) ([]byte, error) {
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	res, ok := obj.Value().(*TaskResult)
+	if !ok {
+		return nil, errors.New("Can only take index of TaskResult")
+	}
+	return heightKey(res.Height), nil
+}
@@ -0,0 +1,229 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x/invariant"
+)
+
+// Executor runs one kind of scheduled task. It gets the data
+// that was passed to Schedule.
+//
+// Any error (or panic) marks the task as failed and rolls back
+// all changes it made, but doesn't stop the block. As nothing
+// of a failed run is kept, it is safe to run the task again.
+type Executor interface {
+	Execute(ctx weave.Context, db weave.KVStore, data []byte) (weave.TickResult, error)
+}
+
+// Ticker runs all tasks that are due in BeginBlock,
+// dispatching them to the Executor registered for their path
+type Ticker struct {
+	executors map[string]Executor
+	tasks     Bucket
+	results   ResultBucket
+
+	maxAttempts int32
+	retryDelay  int64
+	keepResults int64
+}
+
+var _ weave.Ticker = Ticker{}
+
+const (
+	// DefaultMaxAttempts is how often a task runs before we give up
+	DefaultMaxAttempts = 3
+	// DefaultRetryDelay is the number of blocks to wait before
+	// running a failed task again
+	DefaultRetryDelay = 10
+	// DefaultKeepResults is the number of blocks we keep the
+	// results of executed tasks
+	DefaultKeepResults = 1000
+)
+
+// NewTicker creates a Ticker with no executors, and the
+// default retries and result history
+func NewTicker() Ticker {
+	return Ticker{
+		executors:   make(map[string]Executor),
+		tasks:       NewBucket(),
+		results:     NewResultBucket(),
+		maxAttempts: DefaultMaxAttempts,
+		retryDelay:  DefaultRetryDelay,
+		keepResults: DefaultKeepResults,
+	}
+}
+
+// WithRetries returns a copy of the Ticker, that runs a failed
+// task up to attempts times in total, waiting delay blocks
+// between two attempts. attempts of 1 never retries.
+func (t Ticker) WithRetries(attempts int32, delay int64) Ticker {
+	if attempts < 1 || delay < 1 {
+		panic(fmt.Sprintf("Invalid retries: %d every %d blocks", attempts, delay))
+	}
+	t.maxAttempts = attempts
+	t.retryDelay = delay
+	return t
+}
+
+// WithResultHistory returns a copy of the Ticker, that
+// deletes the results of tasks once they are older than
+// blocks. 0 keeps them forever.
+func (t Ticker) WithResultHistory(blocks int64) Ticker {
+	if blocks < 0 {
+		panic(fmt.Sprintf("Invalid result history: %d", blocks))
+	}
+	t.keepResults = blocks
+	return t
+}
+
+// Register adds an Executor for all tasks with this path.
+// panics if another Executor was already registered
+func (t Ticker) Register(path string, e Executor) {
+	if !isPath(path) {
+		panic(fmt.Sprintf("Invalid path: %s", path))
+	}
+	if _, ok := t.executors[path]; ok {
+		panic(fmt.Sprintf("Re-registering executor: %s", path))
+	}
+	t.executors[path] = e
+}
+
+// Tick executes all tasks scheduled for the current height,
+// each in its own savepoint, and records the results.
+// A failed task is scheduled again, until it used up
+// all attempts. Results that are older than the history
+// of the Ticker are deleted.
+//
+// It only returns an error if the storage itself fails,
+// never for a failing task.
+func (t Ticker) Tick(ctx weave.Context, db weave.KVStore) (weave.TickResult, error) {
+	var res weave.TickResult
+	height, _ := weave.GetHeight(ctx)
+	err := t.pruneResults(db, height)
+	if err != nil {
+		return res, err
+	}
+	due, err := t.tasks.Due(db, height)
+	if err != nil {
+		return res, err
+	}
+
+	for _, obj := range due {
+		task := AsTask(obj)
+		tres, err := t.execute(ctx, db, task)
+		result := &TaskResult{
+			Height:     height,
+			Path:       task.Path,
+			Successful: err == nil,
+		}
+		if err == nil {
+			res.Diff = append(res.Diff, tres.Diff...)
+		} else {
+			tm := errors.Wrap(err)
+			result.Code = tm.ABCICode()
+			result.Log = tm.ABCILog()
+			task.Attempts++
+			if task.Attempts < t.maxAttempts {
+				result.RetryHeight = height + t.retryDelay
+			}
+			weave.GetLogger(ctx).Info("Task failed",
+				"id", fmt.Sprintf("%X", obj.Key()),
+				"path", task.Path,
+				"attempts", task.Attempts,
+				"retry", result.RetryHeight,
+				"err", result.Log)
+		}
+
+		err = t.results.Save(db, orm.NewSimpleObj(obj.Key(), result))
+		if err != nil {
+			return res, err
+		}
+		if result.RetryHeight == 0 {
+			err = t.tasks.Delete(db, obj.Key())
+		} else {
+			task.Height = result.RetryHeight
+			err = t.tasks.Save(db, obj)
+		}
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// pruneResults deletes all results of the block that
+// just left the history. As we tick every block, this
+// never keeps more than the history.
+func (t Ticker) pruneResults(db weave.KVStore, height int64) error {
+	if t.keepResults == 0 || height <= t.keepResults {
+		return nil
+	}
+	old, err := t.results.GetIndexed(db, HeightIndex, heightKey(height-t.keepResults))
+	if err != nil {
+		return err
+	}
+	for _, obj := range old {
+		err = t.results.Delete(db, obj.Key())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execute runs one task in a savepoint, which is only
+// written if it succeeds
+func (t Ticker) execute(ctx weave.Context, db weave.KVStore,
+	task *Task) (res weave.TickResult, err error) {
+
+	defer errors.Recover(&err)
+
+	e, ok := t.executors[task.Path]
+	if !ok {
+		return res, ErrNoSuchExecutor(task.Path)
+	}
+
+	cstore, ok := db.(weave.CacheableKVStore)
+	if !ok {
+		return e.Execute(ctx, db, task.Data)
+	}
+	cache := cstore.CacheWrap()
+	res, err = e.Execute(ctx, cache, task.Data)
+	if err == nil {
+		cache.Write()
+	} else {
+		cache.Discard()
+	}
+	return res, err
+}
+
+// Schedule saves a task to run at the beginning of block height,
+// and returns its id. This is the entry point for other modules.
+func Schedule(ctx weave.Context, db weave.KVStore, height int64,
+	path string, data []byte) ([]byte, error) {
+
+	return NewBucket().Schedule(ctx, db, height, path, data)
+}
+
+// Cancel removes a pending task, so it never runs
+func Cancel(db weave.KVStore, id []byte) error {
+	return NewBucket().Cancel(db, id)
+}
+
+// RegisterQuery will register the pending tasks as "/tasks",
+// and the results as "/taskresults", both indexed by height
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("tasks", qr)
+	NewResultBucket().Register("taskresults", qr)
+}
+
+// RegisterInvariants checks that the height indexes of the
+// tasks and results match the data
+func RegisterInvariants(r invariant.Registry) {
+	r.Register("scheduler/tasks", NewBucket().CheckIndexes)
+	r.Register("scheduler/results", NewResultBucket().CheckIndexes)
+}
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+)
+
+// writeExecutor writes the data under key "done",
+// and fails (after writing) if the data is "fail"
+type writeExecutor struct{}
+
+func (writeExecutor) Execute(ctx weave.Context, db weave.KVStore,
+	data []byte) (weave.TickResult, error) {
+
+	var res weave.TickResult
+	db.Set([]byte("done"), data)
+	switch string(data) {
+	case "fail":
+		return res, errors.ErrInternal("failed")
+	case "panic":
+		panic("boom")
+	}
+	res.Diff = []abci.Validator{{PubKey: abci.PubKey{Data: data}, Power: 1}}
+	return res, nil
+}
+
+func TestTicker(t *testing.T) {
+	db := store.MemStore()
+	ticker := NewTicker().WithRetries(2, 3).WithResultHistory(10)
+	assert.Panics(t, func() { NewTicker().WithRetries(0, 3) })
+	assert.Panics(t, func() { NewTicker().WithResultHistory(-1) })
+	ticker.Register("test/write", writeExecutor{})
+	assert.Panics(t, func() { ticker.Register("test/write", writeExecutor{}) })
+	assert.Panics(t, func() { ticker.Register("test write", writeExecutor{}) })
+
+	at := func(height int64) weave.Context {
+		return weave.WithHeight(context.Background(), height)
+	}
+
+	// only schedule the future, with a valid path
+	_, err := Schedule(at(5), db, 5, "test/write", []byte("now"))
+	assert.True(t, IsInvalidTaskErr(err), "%+v", err)
+	_, err = Schedule(at(5), db, 7, "test write", nil)
+	assert.True(t, IsInvalidTaskErr(err), "%+v", err)
+
+	ok, err := Schedule(at(5), db, 7, "test/write", []byte("ok"))
+	require.NoError(t, err)
+	fail, err := Schedule(at(5), db, 7, "test/write", []byte("fail"))
+	require.NoError(t, err)
+	boom, err := Schedule(at(6), db, 8, "test/write", []byte("panic"))
+	require.NoError(t, err)
+	missing, err := Schedule(at(6), db, 8, "test/missing", nil)
+	require.NoError(t, err)
+	canceled, err := Schedule(at(6), db, 8, "test/write", []byte("canceled"))
+	require.NoError(t, err)
+	require.NoError(t, Cancel(db, canceled))
+	// canceling twice is fine
+	require.NoError(t, Cancel(db, canceled))
+
+	// nothing due yet
+	res, err := ticker.Tick(at(6), db)
+	require.NoError(t, err)
+	assert.Empty(t, res.Diff)
+	assert.Nil(t, db.Get([]byte("done")))
+
+	// the failure is rolled back, but the success is kept
+	res, err = ticker.Tick(at(7), db)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Diff))
+	assert.Equal(t, []byte("ok"), res.Diff[0].PubKey.Data)
+	assert.Equal(t, []byte("ok"), db.Get([]byte("done")))
+
+	// panics and unknown paths just fail the task
+	res, err = ticker.Tick(at(8), db)
+	require.NoError(t, err)
+	assert.Empty(t, res.Diff)
+	assert.Equal(t, []byte("ok"), db.Get([]byte("done")))
+
+	// the failed tasks are still there for another attempt
+	tasks, err := NewBucket().All(db)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(tasks))
+	for _, obj := range tasks {
+		assert.Equal(t, int32(1), AsTask(obj).Attempts)
+	}
+
+	results := NewResultBucket()
+	cases := []struct {
+		id     []byte
+		height int64
+		code   uint32
+		retry  int64
+	}{
+		{ok, 7, 0, 0},
+		{fail, 7, errors.CodeInternalErr, 10},
+		{boom, 8, errors.CodeInternalErr, 11},
+		{missing, 8, CodeNoSuchExecutor, 11},
+	}
+	for i, tc := range cases {
+		obj, err := results.Get(db, tc.id)
+		require.NoError(t, err)
+		res := AsTaskResult(obj)
+		require.NotNil(t, res, "%d", i)
+		assert.Equal(t, tc.height, res.Height, "%d", i)
+		assert.Equal(t, tc.code == 0, res.Successful, "%d", i)
+		assert.Equal(t, tc.code, res.Code, "%d", i)
+		assert.Equal(t, tc.code == 0, res.Log == "", "%d", i)
+		assert.Equal(t, tc.retry, res.RetryHeight, "%d", i)
+	}
+	obj, err := results.Get(db, canceled)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+
+	done, err := results.GetIndexed(db, HeightIndex, heightKey(8))
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(done))
+
+	// the second attempt fails again, and we give up
+	for h := int64(9); h <= 11; h++ {
+		_, err = ticker.Tick(at(h), db)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []byte("ok"), db.Get([]byte("done")))
+	tasks, err = NewBucket().All(db)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+	for _, id := range [][]byte{fail, boom, missing} {
+		res := AsTaskResult(mustGet(t, results, db, id))
+		require.NotNil(t, res)
+		assert.False(t, res.Successful)
+		assert.Equal(t, int64(0), res.RetryHeight)
+	}
+
+	// results are deleted once they leave the history
+	_, err = ticker.Tick(at(17), db)
+	require.NoError(t, err)
+	assert.Nil(t, mustGet(t, results, db, ok))
+	assert.NotNil(t, mustGet(t, results, db, fail))
+	_, err = ticker.Tick(at(20), db)
+	require.NoError(t, err)
+	assert.Nil(t, mustGet(t, results, db, fail))
+	assert.NotNil(t, mustGet(t, results, db, boom))
+}
+
+func mustGet(t *testing.T, b ResultBucket, db weave.ReadOnlyKVStore, id []byte) orm.Object {
+	obj, err := b.Get(db, id)
+	require.NoError(t, err)
+	return obj
+}
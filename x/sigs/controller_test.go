@@ -49,8 +49,19 @@ func TestSignBytes(t *testing.T) {
 }
 
 func TestVerifySignature(t *testing.T) {
+	cases := map[string]*crypto.PrivateKey{
+		"ed25519":   crypto.GenPrivKeyEd25519(),
+		"secp256k1": crypto.GenPrivKeySecp256k1(),
+	}
+	for name, priv := range cases {
+		t.Run(name, func(t *testing.T) {
+			testVerifySignature(t, priv)
+		})
+	}
+}
+
+func testVerifySignature(t *testing.T, priv *crypto.PrivateKey) {
 	kv := store.MemStore()
-	priv := crypto.GenPrivKeyEd25519()
 	pub := priv.PublicKey()
 	perm := pub.Condition()
 
@@ -103,7 +114,7 @@ func TestVerifySignature(t *testing.T) {
 	_, err = VerifySignature(kv, sig2, bz, "metal")
 	assert.Error(t, err)
 	// doesn't match on bad sig
-	copy(sig2.Signature.GetEd25519(), []byte{42, 17, 99})
+	sig2.Signature = sig13.Signature
 	_, err = VerifySignature(kv, sig2, bz, chainID)
 	assert.Error(t, err)
 }
@@ -0,0 +1,25 @@
+/*
+Package staking lets coin holders bond their coins to a
+validator, which gets one unit of power per whole coin bonded.
+
+A BondMsg moves the coins of the owner to the PoolCondition,
+and adds them to the stake of the validator pubkey. An
+UnbondMsg takes them out of the stake again, but the coins
+are only paid out after the unbonding period, by a task run
+with x/scheduler, so the stake cannot leave the chain in the
+same block the validator loses its power.
+
+Every change in power is returned as the Diff of the tx, and
+must pass the validator rules enforced by x/validators.Guard.
+The stake adds to any power the validator already has in the
+x/validators.ValidatorSet, like from genesis, so bonding and
+unbonding never take away power that didn't come from staking.
+
+Only coins of the ticker from the genesis params can be bonded:
+
+	"staking": {
+	  "ticker": "ETH",
+	  "unbonding_blocks": 1000
+	}
+*/
+package staking
@@ -0,0 +1,70 @@
+package staking
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// x/staking reserves 100 ~ 109.
+const (
+	CodeInvalidMsg       uint32 = 100
+	CodeNoSuchBond              = 101
+	CodeInsufficientBond        = 102
+	CodeInvalidParams           = 103
+	CodeNoSuchUnbonding         = 104
+)
+
+var (
+	errNoSuchBond       = fmt.Errorf("No bond on this validator")
+	errInsufficientBond = fmt.Errorf("Bond too small")
+	errInvalidParams    = fmt.Errorf("Invalid staking params")
+	errNoSuchUnbonding  = fmt.Errorf("No unbonding with this id")
+
+	errMissingValidator = fmt.Errorf("Missing validator pubkey")
+	errWrongTicker      = fmt.Errorf("Cannot bond this ticker")
+)
+
+func ErrNoSuchBond(validator []byte) error {
+	msg := fmt.Sprintf("%X", validator)
+	return errors.WithLog(msg, errNoSuchBond, CodeNoSuchBond)
+}
+func IsNoSuchBondErr(err error) bool {
+	return errors.IsSameError(errNoSuchBond, err)
+}
+
+func ErrInsufficientBond() error {
+	return errors.WithCode(errInsufficientBond, CodeInsufficientBond)
+}
+func IsInsufficientBondErr(err error) bool {
+	return errors.IsSameError(errInsufficientBond, err)
+}
+
+func ErrInvalidParams(reason string) error {
+	return errors.WithLog(reason, errInvalidParams, CodeInvalidParams)
+}
+func IsInvalidParamsErr(err error) bool {
+	return errors.IsSameError(errInvalidParams, err)
+}
+
+func ErrNoSuchUnbonding(id []byte) error {
+	msg := fmt.Sprintf("%X", id)
+	return errors.WithLog(msg, errNoSuchUnbonding, CodeNoSuchUnbonding)
+}
+func IsNoSuchUnbondingErr(err error) bool {
+	return errors.IsSameError(errNoSuchUnbonding, err)
+}
+
+//------ invalid messages ----
+// all will match IsInvalidMsgErr
+
+func ErrMissingValidator() error {
+	return errors.WithCode(errMissingValidator, CodeInvalidMsg)
+}
+func ErrWrongTicker(ticker string) error {
+	return errors.WithLog(ticker, errWrongTicker, CodeInvalidMsg)
+}
+func IsInvalidMsgErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidMsg)
+}
@@ -0,0 +1,385 @@
+package staking
+
+import (
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/invariant"
+	"github.com/confio/weave/x/scheduler"
+	"github.com/confio/weave/x/validators"
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth x.Authenticator,
+	control cash.Controller) {
+
+	b := newBuckets()
+	r.Handle(pathBondMsg, BondHandler{auth, control, b})
+	r.Handle(pathUnbondMsg, UnbondHandler{auth, b})
+}
+
+// RegisterTasks adds the executor that pays out the
+// unbonded coins to the scheduler
+func RegisterTasks(t scheduler.Ticker, control cash.Controller) {
+	t.Register(pathUnbondTask, PayoutExecutor{NewUnbondingBucket(), control})
+}
+
+// RegisterQuery will register the bonds as "/bonds", the total
+// stake per validator as "/stakes", and the pending payouts as
+// "/unbondings", along with all their indexes
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBondBucket().Register("bonds", qr)
+	NewStakeBucket().Register("stakes", qr)
+	NewUnbondingBucket().Register("unbondings", qr)
+}
+
+// RegisterInvariants checks that all indexes of the bonds
+// and unbondings match the data
+func RegisterInvariants(r invariant.Registry) {
+	r.Register("staking/bonds", NewBondBucket().CheckIndexes)
+	r.Register("staking/unbondings", NewUnbondingBucket().CheckIndexes)
+}
+
+// buckets bundles everything the handlers need to
+// update the stake of a validator
+type buckets struct {
+	params     ParamsBucket
+	bonds      BondBucket
+	stakes     StakeBucket
+	unbondings UnbondingBucket
+	set        validators.ValidatorSet
+	guard      validators.Guard
+}
+
+func newBuckets() buckets {
+	return buckets{
+		params:     NewParamsBucket(),
+		bonds:      NewBondBucket(),
+		stakes:     NewStakeBucket(),
+		unbondings: NewUnbondingBucket(),
+		set:        validators.NewValidatorSet(),
+		guard:      validators.NewGuard(),
+	}
+}
+
+// addStake adds amount (which may be negative) to the stake of
+// the validator. If that changes the power, it returns the diff
+// for tendermint, once it passed the validator rules.
+//
+// The stake only adds to the power the validator already has in
+// the ValidatorSet, like from genesis, so bonding to a validator
+// can never take away power it didn't get from staking.
+func (b buckets) addStake(ctx weave.Context, db weave.KVStore,
+	validator *validators.PubKey, amount x.Coin) ([]abci.Validator, error) {
+
+	stake, err := b.stakes.GetStake(db, validator)
+	if err != nil {
+		return nil, err
+	}
+	if stake == nil {
+		stake = &Stake{
+			Validator: validator,
+			Amount:    &x.Coin{Ticker: amount.Ticker, Issuer: amount.Issuer},
+		}
+	}
+
+	before := Power(stake.Amount)
+	total, err := stake.Amount.Add(amount)
+	if err != nil {
+		return nil, err
+	}
+	stake.Amount = &total
+	err = b.stakes.SetStake(db, stake)
+	if err != nil {
+		return nil, err
+	}
+
+	change := Power(stake.Amount) - before
+	if change == 0 {
+		return nil, nil
+	}
+	current, err := b.set.GetPower(db, validator.Data)
+	if err != nil {
+		return nil, err
+	}
+	power := current + change
+	// someone else already removed the validator
+	if power < 0 {
+		power = 0
+	}
+	diff := []abci.Validator{{
+		PubKey: abci.PubKey{Type: validator.Type, Data: validator.Data},
+		Power:  power,
+	}}
+	err = b.guard.Check(ctx, db, diff)
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// getOwner returns the owner from the message, or the main signer,
+// and makes sure it signed the tx
+func getOwner(ctx weave.Context, auth x.Authenticator,
+	addr []byte) (weave.Address, error) {
+
+	owner := weave.Address(addr)
+	if owner == nil {
+		owner = x.MainSigner(ctx, auth).Address()
+	}
+	if !auth.HasAddress(ctx, owner) {
+		return nil, errors.ErrUnauthorized()
+	}
+	return owner, nil
+}
+
+//---- bond
+
+// BondHandler moves coins to the pool, and adds them
+// to the stake of the validator
+type BondHandler struct {
+	auth x.Authenticator
+	cash cash.Controller
+	b    buckets
+}
+
+var _ weave.Handler = BondHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h BondHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += bondCost
+	return res, nil
+}
+
+// Deliver moves the coins and updates the bond. It returns
+// the new power of the validator in the Diff
+func (h BondHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, owner, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.cash.MoveCoins(db, owner, PoolCondition.Address(), *msg.Amount)
+	if err != nil {
+		return res, err
+	}
+
+	bond, err := h.b.bonds.GetBond(db, owner, msg.Validator)
+	if err != nil {
+		return res, err
+	}
+	if bond == nil {
+		bond = &Bond{
+			Owner:     owner,
+			Validator: msg.Validator,
+			Amount:    &x.Coin{Ticker: msg.Amount.Ticker, Issuer: msg.Amount.Issuer},
+		}
+	}
+	total, err := bond.Amount.Add(*msg.Amount)
+	if err != nil {
+		return res, err
+	}
+	bond.Amount = &total
+	err = h.b.bonds.SetBond(db, bond)
+	if err != nil {
+		return res, err
+	}
+
+	res.Diff, err = h.b.addStake(ctx, db, msg.Validator, *msg.Amount)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the owner
+func (h BondHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*BondMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*BondMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	params, err := h.b.params.GetParams(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if msg.Amount.ID() != params.Ticker {
+		return nil, nil, ErrWrongTicker(msg.Amount.ID())
+	}
+
+	owner, err := getOwner(ctx, h.auth, msg.Owner)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, owner, nil
+}
+
+//---- unbond
+
+// UnbondHandler takes coins from the stake of the validator,
+// and schedules paying them out after the unbonding period
+type UnbondHandler struct {
+	auth x.Authenticator
+	b    buckets
+}
+
+var _ weave.Handler = UnbondHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h UnbondHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	_, _, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += unbondCost
+	return res, nil
+}
+
+// Deliver updates the bond and schedules the payout. It returns
+// the new power of the validator in the Diff, and the id of the
+// unbonding as Data
+func (h UnbondHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	msg, bond, params, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	left, err := bond.Amount.Add(msg.Amount.Negative())
+	if err != nil {
+		return res, err
+	}
+	bond.Amount = &left
+	err = h.b.bonds.SetBond(db, bond)
+	if err != nil {
+		return res, err
+	}
+
+	res.Diff, err = h.b.addStake(ctx, db, msg.Validator, msg.Amount.Negative())
+	if err != nil {
+		return res, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	obj, err := h.b.unbondings.Create(db, &Unbonding{
+		Owner:         bond.Owner,
+		Validator:     msg.Validator,
+		Amount:        msg.Amount,
+		ReleaseHeight: height + params.UnbondingBlocks,
+	})
+	if err != nil {
+		return res, err
+	}
+	_, err = scheduler.Schedule(ctx, db, height+params.UnbondingBlocks,
+		pathUnbondTask, obj.Key())
+	if err != nil {
+		return res, err
+	}
+
+	res.Data = obj.Key()
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// and returns the bond to take the coins from
+func (h UnbondHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*UnbondMsg, *Bond, *Params, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	msg, ok := rmsg.(*UnbondMsg)
+	if !ok {
+		return nil, nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	params, err := h.b.params.GetParams(db)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	owner, err := getOwner(ctx, h.auth, msg.Owner)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	bond, err := h.b.bonds.GetBond(db, owner, msg.Validator)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if bond == nil {
+		return nil, nil, nil, ErrNoSuchBond(msg.Validator.Data)
+	}
+	if !bond.Amount.IsGTE(*msg.Amount) {
+		return nil, nil, nil, ErrInsufficientBond()
+	}
+	return msg, bond, params, nil
+}
+
+//---- payout
+
+// PayoutExecutor is run by the scheduler once the unbonding
+// period is over, and sends the coins back to the owner
+type PayoutExecutor struct {
+	bucket UnbondingBucket
+	cash   cash.Controller
+}
+
+var _ scheduler.Executor = PayoutExecutor{}
+
+// Execute pays out the unbonding with the id in data,
+// and deletes it
+func (e PayoutExecutor) Execute(ctx weave.Context, db weave.KVStore,
+	data []byte) (weave.TickResult, error) {
+
+	var res weave.TickResult
+	obj, err := e.bucket.Get(db, data)
+	if err != nil {
+		return res, err
+	}
+	u := AsUnbonding(obj)
+	if u == nil {
+		return res, ErrNoSuchUnbonding(data)
+	}
+
+	err = e.cash.MoveCoins(db, PoolCondition.Address(), u.Owner, *u.Amount)
+	if err != nil {
+		return res, err
+	}
+	return res, e.bucket.Delete(db, data)
+}
@@ -0,0 +1,203 @@
+package staking
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/scheduler"
+	"github.com/confio/weave/x/testhelpers"
+	"github.com/confio/weave/x/validators"
+)
+
+// stakeTest runs the staking handlers and tasks on a new store
+type stakeTest struct {
+	*testhelpers.HandlerTest
+	t      *testing.T
+	ticker scheduler.Ticker
+}
+
+func newStakeTest(t *testing.T, params *Params) *stakeTest {
+	s := &stakeTest{
+		HandlerTest: testhelpers.NewHandlerTest(),
+		t:           t,
+		ticker:      scheduler.NewTicker(),
+	}
+	// store the diff like the app does
+	s.OnDeliver = func(db weave.KVStore, res weave.DeliverResult) error {
+		return validators.NewValidatorSet().UpdateValidators(db, res.Diff)
+	}
+	control := cash.NewController(cash.NewBucket())
+	RegisterRoutes(s, s.Auth, control)
+	RegisterTasks(s.ticker, control)
+	if params != nil {
+		require.NoError(t, NewParamsBucket().SetParams(s.DB, params))
+	}
+	return s
+}
+
+// tick runs the scheduled tasks at height
+func (s *stakeTest) tick(height int64) {
+	ctx := weave.WithHeight(context.Background(), height)
+	_, err := s.ticker.Tick(ctx, s.DB)
+	require.NoError(s.t, err)
+}
+
+func TestBondAndUnbond(t *testing.T) {
+	s := newStakeTest(t, &Params{Ticker: "ETH", UnbondingBlocks: 10})
+
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	b := weave.NewCondition("sig", "ed25519", []byte{4, 5, 6})
+	owner := a.Address()
+	pool := PoolCondition.Address()
+	eth, foo := x.NewCoin(100, 0, "ETH"), x.NewCoin(5, 0, "FOO")
+	testhelpers.Fund(s.DB, owner, &eth, &foo)
+
+	val := testValidator()
+	power := func(p int64) []abci.Validator {
+		return []abci.Validator{{
+			PubKey: abci.PubKey{Type: val.Type, Data: val.Data},
+			Power:  p,
+		}}
+	}
+
+	// only keys tendermint accepts
+	bad := &validators.PubKey{Type: "foo", Data: val.Data}
+	_, err := s.Run(1, &BondMsg{Validator: bad, Amount: &eth}, a)
+	assert.True(t, validators.IsInvalidValidatorErr(err), "%+v", err)
+	bad = &validators.PubKey{Type: val.Type, Data: []byte{1, 2, 3}}
+	_, err = s.Run(1, &BondMsg{Validator: bad, Amount: &eth}, a)
+	assert.True(t, validators.IsInvalidValidatorErr(err), "%+v", err)
+
+	// only the ticker from the params, and only signed by the owner
+	_, err = s.Run(1, &BondMsg{Validator: val, Amount: &foo}, a)
+	assert.True(t, IsInvalidMsgErr(err), "%+v", err)
+	coin := x.NewCoin(10, 500000000, "ETH")
+	_, err = s.Run(1, &BondMsg{Owner: owner, Validator: val, Amount: &coin}, b)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+
+	// power is the whole coins bonded
+	res, err := s.Run(1, &BondMsg{Validator: val, Amount: &coin}, a)
+	require.NoError(t, err)
+	assert.Equal(t, power(10), res.Diff)
+	assert.Equal(t, x.Coins{&coin}, testhelpers.Balance(s.DB, pool))
+
+	// no diff if the power is the same
+	coin = x.NewCoin(0, 200000000, "ETH")
+	res, err = s.Run(2, &BondMsg{Validator: val, Amount: &coin}, a)
+	require.NoError(t, err)
+	assert.Empty(t, res.Diff)
+
+	bond, err := NewBondBucket().GetBond(s.DB, owner, val)
+	require.NoError(t, err)
+	total := x.NewCoin(10, 700000000, "ETH")
+	assert.Equal(t, &total, bond.Amount)
+	objs, err := NewBondBucket().GetIndexed(s.DB, ValidatorIndex, val.Data)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(objs))
+
+	// only the owner can unbond, and only what they bonded
+	_, err = s.Run(3, &UnbondMsg{Validator: val, Amount: &total}, b)
+	assert.True(t, IsNoSuchBondErr(err), "%+v", err)
+	coin = x.NewCoin(20, 0, "ETH")
+	_, err = s.Run(3, &UnbondMsg{Validator: val, Amount: &coin}, a)
+	assert.True(t, IsInsufficientBondErr(err), "%+v", err)
+
+	// unbond all, which removes the validator
+	res, err = s.Run(5, &UnbondMsg{Validator: val, Amount: &total}, a)
+	require.NoError(t, err)
+	assert.Equal(t, power(0), res.Diff)
+	id := res.Data
+	bond, err = NewBondBucket().GetBond(s.DB, owner, val)
+	require.NoError(t, err)
+	assert.Nil(t, bond)
+	stake, err := NewStakeBucket().GetStake(s.DB, val)
+	require.NoError(t, err)
+	assert.Nil(t, stake)
+
+	// the coins are paid out after the unbonding period
+	s.tick(14)
+	assert.Equal(t, x.Coins{&total}, testhelpers.Balance(s.DB, pool))
+	s.tick(15)
+	assert.True(t, testhelpers.Balance(s.DB, pool).IsEmpty())
+	assert.Equal(t, x.Coins{&eth, &foo}, testhelpers.Balance(s.DB, owner))
+	obj, err := NewUnbondingBucket().Get(s.DB, id)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+}
+
+func TestBondRules(t *testing.T) {
+	s := newStakeTest(t, &Params{Ticker: "ETH", UnbondingBlocks: 10})
+
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	eth := x.NewCoin(100, 0, "ETH")
+	testhelpers.Fund(s.DB, a.Address(), &eth)
+	val := testValidator()
+
+	_, err := s.Run(1, &BondMsg{Validator: val, Amount: &eth}, a)
+	require.NoError(t, err)
+
+	guard := validators.NewGuard()
+	require.NoError(t, guard.SetRules(s.DB, &validators.Rules{MinValidators: 1}))
+
+	// so the last validator cannot leave
+	_, err = s.Run(2, &UnbondMsg{Validator: val, Amount: &eth}, a)
+	assert.True(t, validators.IsTooFewValidatorsErr(err), "%+v", err)
+}
+
+func TestBondToGenesisValidator(t *testing.T) {
+	s := newStakeTest(t, &Params{Ticker: "ETH", UnbondingBlocks: 10})
+
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	eth := x.NewCoin(100, 0, "ETH")
+	testhelpers.Fund(s.DB, a.Address(), &eth)
+	val := testValidator()
+	power := func(p int64) []abci.Validator {
+		return []abci.Validator{{
+			PubKey: abci.PubKey{Type: val.Type, Data: val.Data},
+			Power:  p,
+		}}
+	}
+	set := validators.NewValidatorSet()
+	require.NoError(t, set.UpdateValidators(s.DB, power(10)))
+
+	// the stake adds to the power from genesis
+	coin := x.NewCoin(1, 0, "ETH")
+	res, err := s.Run(1, &BondMsg{Validator: val, Amount: &coin}, a)
+	require.NoError(t, err)
+	assert.Equal(t, power(11), res.Diff)
+
+	// and unbonding only takes it away again
+	res, err = s.Run(2, &UnbondMsg{Validator: val, Amount: &coin}, a)
+	require.NoError(t, err)
+	assert.Equal(t, power(10), res.Diff)
+	current, err := set.GetPower(s.DB, val.Data)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), current)
+}
+
+func TestBondWithoutParams(t *testing.T) {
+	s := newStakeTest(t, nil)
+
+	a := weave.NewCondition("sig", "ed25519", []byte{1, 2, 3})
+	eth := x.NewCoin(100, 0, "ETH")
+	testhelpers.Fund(s.DB, a.Address(), &eth)
+	val := testValidator()
+
+	_, err := s.Run(1, &BondMsg{Validator: val, Amount: &eth}, a)
+	assert.True(t, IsInvalidParamsErr(err), "%+v", err)
+}
+
+// testValidator returns a valid ed25519 validator pubkey
+func testValidator() *validators.PubKey {
+	data := make([]byte, 32)
+	copy(data, "validator")
+	return &validators.PubKey{Type: validators.KeyTypeEd25519, Data: data}
+}
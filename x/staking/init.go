@@ -0,0 +1,60 @@
+package staking
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+const (
+	optKey = "staking"
+	// stateKey holds the bonds, stakes and unbondings when
+	// the chain is restarted from an export of its state
+	stateKey = "staking_state"
+)
+
+// Initializer fulfils the InitStater interface to load the
+// staking params from the genesis file, and can export them
+// in the same format, along with all bonds
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+var _ weave.Exporter = Initializer{}
+
+// FromGenesis will parse the params from genesis
+// and save them to the database, along with any
+// exported bonds
+func (Initializer) FromGenesis(opts weave.Options, kv weave.KVStore) error {
+	err := orm.ImportBuckets(opts, stateKey, kv, stateBuckets()...)
+	if err != nil || len(opts[optKey]) == 0 {
+		return err
+	}
+	var params Params
+	err = opts.ReadOptions(optKey, &params)
+	if err != nil {
+		return err
+	}
+	return NewParamsBucket().SetParams(kv, &params)
+}
+
+// ToGenesis writes the params to the options, if they were set,
+// and all bonds, stakes and unbondings
+func (Initializer) ToGenesis(opts weave.Options, kv weave.ReadOnlyKVStore) error {
+	err := orm.ExportBuckets(opts, stateKey, kv, stateBuckets()...)
+	if err != nil {
+		return err
+	}
+	obj, err := NewParamsBucket().Get(kv, []byte(ParamsKey))
+	if err != nil || obj == nil {
+		return err
+	}
+	return opts.WriteOptions(optKey, obj.Value())
+}
+
+// stateBuckets are all buckets changed by the handlers
+func stateBuckets() []orm.Bucket {
+	return []orm.Bucket{
+		NewBondBucket().Bucket,
+		NewStakeBucket().Bucket,
+		NewUnbondingBucket().Bucket,
+	}
+}
@@ -0,0 +1,36 @@
+package staking
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+)
+
+func TestGenesis(t *testing.T) {
+	var init Initializer
+	db := store.MemStore()
+
+	// nothing to load or export
+	require.NoError(t, init.FromGenesis(weave.Options{}, db))
+	opts := weave.Options{}
+	require.NoError(t, init.ToGenesis(opts, db))
+	assert.Empty(t, opts)
+
+	// bad params are rejected
+	err := init.FromGenesis(weave.Options{optKey: []byte(`{"ticker":"ETH"}`)}, db)
+	assert.True(t, IsInvalidParamsErr(err), "%+v", err)
+
+	// export what we loaded
+	params := []byte(`{"ticker":"ETH","unbonding_blocks":100}`)
+	require.NoError(t, init.FromGenesis(weave.Options{optKey: params}, db))
+	loaded, err := NewParamsBucket().GetParams(db)
+	require.NoError(t, err)
+	assert.Equal(t, &Params{Ticker: "ETH", UnbondingBlocks: 100}, loaded)
+
+	require.NoError(t, init.ToGenesis(opts, db))
+	assert.Equal(t, string(params), string(opts[optKey]))
+}
@@ -0,0 +1,402 @@
+package staking
+
+import (
+	"errors"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/validators"
+)
+
+const (
+	// BondBucketName is where we store the bonds
+	BondBucketName = "bond"
+	// StakeBucketName is where we store the total stake
+	// of each validator
+	StakeBucketName = "stake"
+	// UnbondingBucketName is where we store the coins waiting
+	// to be paid out
+	UnbondingBucketName = "unbond"
+	// ParamsBucketName is where we store the params from genesis
+	ParamsBucketName = "stakecfg"
+	// ParamsKey is used to store the params
+	ParamsKey = "params"
+
+	// OwnerIndex is the index of bonds (or unbondings) by owner
+	OwnerIndex = "owner"
+	// ValidatorIndex is the index of bonds by validator pubkey
+	ValidatorIndex = "validator"
+)
+
+// PoolCondition holds all bonded and unbonding coins
+var PoolCondition = weave.NewCondition("staking", "pool", []byte("bonded"))
+
+// Power returns the validator power of the stake,
+// one per whole coin
+func Power(amount *x.Coin) int64 {
+	if amount == nil {
+		return 0
+	}
+	return amount.Whole
+}
+
+//---- Params
+
+var _ orm.CloneableData = (*Params)(nil)
+
+// Validate requires a ticker and a positive period
+func (p *Params) Validate() error {
+	if !x.IsCC(p.Ticker) {
+		return ErrInvalidParams("Invalid ticker")
+	}
+	if p.UnbondingBlocks <= 0 {
+		return ErrInvalidParams("Unbonding blocks must be positive")
+	}
+	return nil
+}
+
+// Copy makes new params with the same data
+func (p *Params) Copy() orm.CloneableData {
+	return &Params{
+		Ticker:          p.Ticker,
+		UnbondingBlocks: p.UnbondingBlocks,
+	}
+}
+
+//---- Bond
+
+var _ orm.CloneableData = (*Bond)(nil)
+
+// Validate requires an owner, a validator and a positive amount
+func (b *Bond) Validate() error {
+	if err := weave.Address(b.Owner).Validate(); err != nil {
+		return err
+	}
+	return validateTerms(b.Validator, b.Amount)
+}
+
+// Copy makes a new Bond with the same data
+func (b *Bond) Copy() orm.CloneableData {
+	return &Bond{
+		Owner:     b.Owner,
+		Validator: copyPubKey(b.Validator),
+		Amount:    cloneCoin(b.Amount),
+	}
+}
+
+//---- Stake
+
+var _ orm.CloneableData = (*Stake)(nil)
+
+// Validate requires a validator and a positive amount
+func (s *Stake) Validate() error {
+	return validateTerms(s.Validator, s.Amount)
+}
+
+// Copy makes a new Stake with the same data
+func (s *Stake) Copy() orm.CloneableData {
+	return &Stake{
+		Validator: copyPubKey(s.Validator),
+		Amount:    cloneCoin(s.Amount),
+	}
+}
+
+//---- Unbonding
+
+var _ orm.CloneableData = (*Unbonding)(nil)
+var _ orm.Rebaser = (*Unbonding)(nil)
+
+// Validate requires an owner, a validator, a positive amount
+// and a release height
+func (u *Unbonding) Validate() error {
+	if err := weave.Address(u.Owner).Validate(); err != nil {
+		return err
+	}
+	if u.ReleaseHeight <= 0 {
+		return ErrInvalidParams("Missing release height")
+	}
+	return validateTerms(u.Validator, u.Amount)
+}
+
+// Copy makes a new Unbonding with the same data
+func (u *Unbonding) Copy() orm.CloneableData {
+	return &Unbonding{
+		Owner:         u.Owner,
+		Validator:     copyPubKey(u.Validator),
+		Amount:        cloneCoin(u.Amount),
+		ReleaseHeight: u.ReleaseHeight,
+	}
+}
+
+// Rebase moves the release to a chain restarted from an export.
+// The task that pays it out is moved along by the scheduler.
+func (u *Unbonding) Rebase(exportHeight int64) bool {
+	u.ReleaseHeight = orm.RebaseHeight(u.ReleaseHeight, exportHeight)
+	return true
+}
+
+// validateTerms checks the validator and amount
+// shared by all types
+func validateTerms(validator *validators.PubKey, amount *x.Coin) error {
+	if validator == nil {
+		return ErrMissingValidator()
+	}
+	if err := validator.Validate(); err != nil {
+		return err
+	}
+	if amount == nil || !amount.IsPositive() {
+		return cash.ErrInvalidAmount("Non-positive amount")
+	}
+	return amount.Validate()
+}
+
+func cloneCoin(c *x.Coin) *x.Coin {
+	if c == nil {
+		return nil
+	}
+	return c.Clone()
+}
+
+func copyPubKey(pk *validators.PubKey) *validators.PubKey {
+	if pk == nil {
+		return nil
+	}
+	return &validators.PubKey{
+		Type: pk.Type,
+		Data: pk.Data,
+	}
+}
+
+// bondKey is unique for each owner and validator
+func bondKey(owner weave.Address, validator *validators.PubKey) []byte {
+	return append(append([]byte(nil), owner...), validator.Data...)
+}
+
+//-------------------- Object Wrapper -------
+
+// AsBond will safely type-cast any value from BondBucket
+func AsBond(obj orm.Object) *Bond {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Bond)
+}
+
+// AsStake will safely type-cast any value from StakeBucket
+func AsStake(obj orm.Object) *Stake {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Stake)
+}
+
+// AsUnbonding will safely type-cast any value from UnbondingBucket
+func AsUnbonding(obj orm.Object) *Unbonding {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Unbonding)
+}
+
+//--- Buckets - type-safe buckets
+
+// ParamsBucket stores the staking params under ParamsKey
+type ParamsBucket struct {
+	orm.Bucket
+}
+
+// NewParamsBucket initializes a ParamsBucket with default name
+func NewParamsBucket() ParamsBucket {
+	proto := orm.NewSimpleObj(nil, new(Params))
+	return ParamsBucket{
+		Bucket: orm.NewBucket(ParamsBucketName, proto),
+	}
+}
+
+// GetParams loads the params, or returns an error
+// if they were never set
+func (b ParamsBucket) GetParams(db weave.ReadOnlyKVStore) (*Params, error) {
+	obj, err := b.Get(db, []byte(ParamsKey))
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return nil, ErrInvalidParams("Staking not configured")
+	}
+	return obj.Value().(*Params), nil
+}
+
+// SetParams validates and stores the params
+func (b ParamsBucket) SetParams(db weave.KVStore, params *Params) error {
+	return b.Save(db, orm.NewSimpleObj([]byte(ParamsKey), params))
+}
+
+// Save enforces the proper type
+func (b ParamsBucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Params); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+// BondBucket stores the bonds by owner and validator
+type BondBucket struct {
+	orm.Bucket
+}
+
+// NewBondBucket initializes a BondBucket with default name,
+// and all indexes
+func NewBondBucket() BondBucket {
+	proto := orm.NewSimpleObj(nil, new(Bond))
+	return BondBucket{
+		Bucket: orm.NewBucket(BondBucketName, proto).
+			WithIndex(OwnerIndex, idxBondOwner, false).
+			WithIndex(ValidatorIndex, idxBondValidator, false),
+	}
+}
+
+// GetBond loads the bond of owner on the validator,
+// or nil if there is none
+func (b BondBucket) GetBond(db weave.ReadOnlyKVStore, owner weave.Address,
+	validator *validators.PubKey) (*Bond, error) {
+
+	obj, err := b.Get(db, bondKey(owner, validator))
+	if err != nil {
+		return nil, err
+	}
+	return AsBond(obj), nil
+}
+
+// SetBond saves the bond, or deletes it if the amount is zero
+func (b BondBucket) SetBond(db weave.KVStore, bond *Bond) error {
+	key := bondKey(bond.Owner, bond.Validator)
+	if x.IsEmpty(bond.Amount) {
+		return b.Delete(db, key)
+	}
+	return b.Save(db, orm.NewSimpleObj(key, bond))
+}
+
+// Save enforces the proper type
+func (b BondBucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Bond); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+// StakeBucket stores the total stake by validator pubkey
+type StakeBucket struct {
+	orm.Bucket
+}
+
+// NewStakeBucket initializes a StakeBucket with default name
+func NewStakeBucket() StakeBucket {
+	proto := orm.NewSimpleObj(nil, new(Stake))
+	return StakeBucket{
+		Bucket: orm.NewBucket(StakeBucketName, proto),
+	}
+}
+
+// GetStake loads the stake of the validator,
+// or nil if there is none
+func (b StakeBucket) GetStake(db weave.ReadOnlyKVStore,
+	validator *validators.PubKey) (*Stake, error) {
+
+	obj, err := b.Get(db, validator.Data)
+	if err != nil {
+		return nil, err
+	}
+	return AsStake(obj), nil
+}
+
+// SetStake saves the stake, or deletes it if the amount is zero
+func (b StakeBucket) SetStake(db weave.KVStore, stake *Stake) error {
+	key := stake.Validator.Data
+	if x.IsEmpty(stake.Amount) {
+		return b.Delete(db, key)
+	}
+	return b.Save(db, orm.NewSimpleObj(key, stake))
+}
+
+// Save enforces the proper type
+func (b StakeBucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Stake); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+// UnbondingBucket stores the coins waiting to be paid out,
+// by id
+type UnbondingBucket struct {
+	orm.Bucket
+}
+
+// NewUnbondingBucket initializes an UnbondingBucket with
+// default name, and all indexes
+func NewUnbondingBucket() UnbondingBucket {
+	proto := orm.NewSimpleObj(nil, new(Unbonding))
+	return UnbondingBucket{
+		Bucket: orm.NewBucket(UnbondingBucketName, proto).
+			WithIndex(OwnerIndex, idxUnbondingOwner, false),
+	}
+}
+
+// Create saves the unbonding under the next free id
+func (b UnbondingBucket) Create(db weave.KVStore, u *Unbonding) (orm.Object, error) {
+	seq := b.Sequence(orm.SeqID)
+	obj := orm.NewSimpleObj(seq.NextVal(db), u)
+	err := b.Save(db, obj)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Save enforces the proper type
+func (b UnbondingBucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Unbonding); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+func getBond(obj orm.Object) (*Bond, error) {
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	bond, ok := obj.Value().(*Bond)
+	if !ok {
+		return nil, errors.New("Can only take index of Bond")
+	}
+	return bond, nil
+}
+
+func idxBondOwner(obj orm.Object) ([]byte, error) {
+	bond, err := getBond(obj)
+	if err != nil {
+		return nil, err
+	}
+	return bond.Owner, nil
+}
+
+func idxBondValidator(obj orm.Object) ([]byte, error) {
+	bond, err := getBond(obj)
+	if err != nil {
+		return nil, err
+	}
+	return bond.Validator.Data, nil
+}
+
+func idxUnbondingOwner(obj orm.Object) ([]byte, error) {
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	u, ok := obj.Value().(*Unbonding)
+	if !ok {
+		return nil, errors.New("Can only take index of Unbonding")
+	}
+	return u.Owner, nil
+}
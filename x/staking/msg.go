@@ -0,0 +1,55 @@
+package staking
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/validators"
+)
+
+// Ensure we implement the Msg interface
+var _ weave.Msg = (*BondMsg)(nil)
+var _ weave.Msg = (*UnbondMsg)(nil)
+
+const (
+	pathBondMsg   = "staking/bond"
+	pathUnbondMsg = "staking/unbond"
+
+	// pathUnbondTask is where the scheduler finds the
+	// executor paying out unbonded coins
+	pathUnbondTask = "staking/payout"
+
+	bondCost   int64 = 200
+	unbondCost int64 = 200
+)
+
+// Path returns the routing path for this message
+func (BondMsg) Path() string {
+	return pathBondMsg
+}
+
+// Validate makes sure that this is sensible.
+// owner is optional, as it defaults to the signer
+func (m *BondMsg) Validate() error {
+	return validateMsg(m.Owner, m.Validator, m.Amount)
+}
+
+// Path returns the routing path for this message
+func (UnbondMsg) Path() string {
+	return pathUnbondMsg
+}
+
+// Validate makes sure that this is sensible.
+// owner is optional, as it defaults to the signer
+func (m *UnbondMsg) Validate() error {
+	return validateMsg(m.Owner, m.Validator, m.Amount)
+}
+
+// validateMsg checks the optional owner and the terms
+func validateMsg(owner []byte, validator *validators.PubKey, amount *x.Coin) error {
+	if owner != nil {
+		if err := weave.Address(owner).Validate(); err != nil {
+			return err
+		}
+	}
+	return validateTerms(validator, amount)
+}
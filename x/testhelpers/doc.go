@@ -0,0 +1,8 @@
+/*
+Package testhelpers runs the handlers of an extension in tests,
+like the app would do it, and sets up the wallets they need.
+
+It is only meant to be imported from _test.go files, so none of
+it ends up in an app.
+*/
+package testhelpers
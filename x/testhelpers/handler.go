@@ -0,0 +1,71 @@
+package testhelpers
+
+import (
+	"context"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+// HandlerTest runs messages through the handlers of an
+// extension, like the app would do it. Pass it to
+// RegisterRoutes as the Registry, along with Auth.
+type HandlerTest struct {
+	DB   weave.KVStore
+	Auth x.CtxAuther
+	// OnDeliver is called after every successful Deliver,
+	// in the same savepoint (optional)
+	OnDeliver func(db weave.KVStore, res weave.DeliverResult) error
+
+	handlers map[string]weave.Handler
+}
+
+var _ weave.Registry = (*HandlerTest)(nil)
+
+// NewHandlerTest returns an empty HandlerTest on a new in-memory
+// store, authenticating the signers passed to Run
+func NewHandlerTest() *HandlerTest {
+	var helpers x.TestHelpers
+	return &HandlerTest{
+		DB:       store.MemStore(),
+		Auth:     helpers.CtxAuth("auth"),
+		handlers: make(map[string]weave.Handler),
+	}
+}
+
+// Handle registers the handler for all messages with this path
+func (h *HandlerTest) Handle(path string, handler weave.Handler) {
+	h.handlers[path] = handler
+}
+
+// Run checks and delivers the msg at the given height,
+// signed by the signers. Deliver only writes to DB
+// if it succeeds.
+func (h *HandlerTest) Run(height int64, msg weave.Msg,
+	signers ...weave.Condition) (weave.DeliverResult, error) {
+
+	var helpers x.TestHelpers
+	ctx := weave.WithHeight(context.Background(), height)
+	ctx = h.Auth.SetConditions(ctx, signers...)
+	tx := helpers.MockTx(msg)
+	handler := h.handlers[msg.Path()]
+
+	cache := h.DB.(weave.CacheableKVStore).CacheWrap()
+	_, err := handler.Check(ctx, cache, tx)
+	cache.Discard()
+	if err != nil {
+		return weave.DeliverResult{}, err
+	}
+
+	// like a savepoint, only keep successful changes
+	cache = h.DB.(weave.CacheableKVStore).CacheWrap()
+	res, err := handler.Deliver(ctx, cache, tx)
+	if err == nil && h.OnDeliver != nil {
+		err = h.OnDeliver(cache, res)
+	}
+	if err == nil {
+		cache.Write()
+	}
+	return res, err
+}
@@ -0,0 +1,71 @@
+package testhelpers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+func TestHandlerTest(t *testing.T) {
+	var helper x.TestHelpers
+	h := NewHandlerTest()
+
+	a, b := []byte("a"), []byte("b")
+	h.Handle("mock", helper.WriteHandler(a, b, nil))
+	_, err := h.Run(1, helper.MockMsg(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, b, h.DB.Get(a))
+
+	// failures are rolled back
+	h = NewHandlerTest()
+	h.Handle("mock", helper.WriteHandler(a, b, fmt.Errorf("fail")))
+	_, err = h.Run(1, helper.MockMsg(nil))
+	assert.Error(t, err)
+	assert.Nil(t, h.DB.Get(a))
+
+	// so is the delivery, if OnDeliver fails
+	h = NewHandlerTest()
+	h.Handle("mock", helper.WriteHandler(a, b, nil))
+	h.OnDeliver = func(db weave.KVStore, res weave.DeliverResult) error {
+		assert.Equal(t, b, db.Get(a))
+		return fmt.Errorf("rejected")
+	}
+	_, err = h.Run(1, helper.MockMsg(nil))
+	assert.Error(t, err)
+	assert.Nil(t, h.DB.Get(a))
+
+	// signers are authenticated
+	_, addr := helper.MakeKey()
+	h = NewHandlerTest()
+	h.Handle("mock", authHandler{h.Auth, addr.Address()})
+	_, err = h.Run(1, helper.MockMsg(nil))
+	assert.Error(t, err)
+	_, err = h.Run(1, helper.MockMsg(nil), addr)
+	assert.NoError(t, err)
+}
+
+// authHandler only accepts messages signed by addr
+type authHandler struct {
+	auth x.Authenticator
+	addr weave.Address
+}
+
+func (h authHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	if !h.auth.HasAddress(ctx, h.addr) {
+		return weave.CheckResult{}, fmt.Errorf("unauthorized")
+	}
+	return weave.CheckResult{}, nil
+}
+
+func (h authHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	_, err := h.Check(ctx, store, tx)
+	return weave.DeliverResult{}, err
+}
@@ -0,0 +1,30 @@
+package testhelpers
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+// Fund sets the wallet of addr in the default cash bucket
+// to hold exactly these coins. It panics if the store is broken.
+func Fund(db weave.KVStore, addr weave.Address, coins ...*x.Coin) {
+	obj, err := cash.WalletWith(addr, coins...)
+	if err != nil {
+		panic(err)
+	}
+	err = cash.NewBucket().Save(db, obj)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Balance returns all coins in the wallet of addr,
+// which is empty if there is no wallet
+func Balance(db weave.ReadOnlyKVStore, addr weave.Address) x.Coins {
+	obj, err := cash.NewBucket().Get(db, addr)
+	if err != nil {
+		panic(err)
+	}
+	return cash.AsCoins(obj)
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+)
+
+// GasLimit is a decorator that meters all store access of
+// its children, and aborts the tx once it used more than
+// the limit. The meter is also placed in the context,
+// so handlers may charge for other work.
+type GasLimit struct {
+	limit  int64
+	config store.GasConfig
+}
+
+var _ weave.Decorator = GasLimit{}
+
+// NewGasLimit creates a GasLimit decorator allowing limit
+// gas per tx, with the default store costs
+func NewGasLimit(limit int64) GasLimit {
+	return GasLimit{
+		limit:  limit,
+		config: store.DefaultGasConfig(),
+	}
+}
+
+// WithConfig returns a GasLimit that charges store access
+// with the given costs
+func (g GasLimit) WithConfig(config store.GasConfig) GasLimit {
+	return GasLimit{
+		limit:  g.limit,
+		config: config,
+	}
+}
+
+// Check meters the tx and reports the limit as GasAllocated
+func (g GasLimit) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Checker) (res weave.CheckResult, err error) {
+
+	meter := weave.NewGasMeter(g.limit)
+	ctx = weave.WithGasMeter(ctx, meter)
+	defer recoverOutOfGas(&err)
+
+	res, err = next.Check(ctx, store.NewGasStore(db, meter, g.config), tx)
+	res.GasAllocated = g.limit
+	return res, err
+}
+
+// Deliver meters the tx and reports the gas consumed as GasUsed,
+// also if the tx failed
+func (g GasLimit) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (res weave.DeliverResult, err error) {
+
+	meter := weave.NewGasMeter(g.limit)
+	ctx = weave.WithGasMeter(ctx, meter)
+	defer func() {
+		res.GasUsed = meter.GasConsumed()
+	}()
+	defer recoverOutOfGas(&err)
+
+	return next.Deliver(ctx, store.NewGasStore(db, meter, g.config), tx)
+}
+
+// recoverOutOfGas turns an out of gas panic into an error,
+// all other panics are passed along
+func recoverOutOfGas(err *error) {
+	if r := recover(); r != nil {
+		if perr, ok := r.(error); ok && errors.IsOutOfGasErr(perr) {
+			*err = perr
+			return
+		}
+		panic(r)
+	}
+}
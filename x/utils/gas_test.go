@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestGasLimit(t *testing.T) {
+	var help x.TestHelpers
+
+	key, value := []byte("demo"), []byte("data")
+	// cost of writing key, value with the default config
+	writeCost := int64(10 + 10*(len(key)+len(value)))
+	derr := fmt.Errorf("something went wrong")
+
+	cases := [...]struct {
+		limit    int64
+		handler  weave.Handler
+		used     int64
+		isError  bool
+		outOfGas bool
+	}{
+		// enough gas
+		0: {writeCost, help.WriteHandler(key, value, nil), writeCost, false, false},
+		// handler errors are reported with the gas
+		1: {1000, help.WriteHandler(key, value, derr), writeCost, true, false},
+		// too little gas
+		2: {writeCost - 1, help.WriteHandler(key, value, nil), writeCost, true, true},
+		// no store access, no gas
+		3: {10, help.CountingHandler(), 0, false, false},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			ctx := context.Background()
+			gas := NewGasLimit(tc.limit)
+
+			cres, err := gas.Check(ctx, store.MemStore(), nil, tc.handler)
+			if tc.isError {
+				require.Error(t, err)
+				assert.Equal(t, tc.outOfGas, errors.IsOutOfGasErr(err))
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.limit, cres.GasAllocated)
+			}
+
+			kv := store.MemStore()
+			dres, err := gas.Deliver(ctx, kv, nil, tc.handler)
+			if tc.isError {
+				require.Error(t, err)
+				assert.Equal(t, tc.outOfGas, errors.IsOutOfGasErr(err))
+			} else {
+				require.NoError(t, err)
+				assert.True(t, kv.Has(key) || tc.used == 0)
+			}
+			assert.Equal(t, tc.used, dres.GasUsed)
+		})
+	}
+}
+
+func TestGasLimitPanics(t *testing.T) {
+	var help x.TestHelpers
+	perr := fmt.Errorf("boom")
+	gas := NewGasLimit(1000)
+
+	// other panics are not swallowed
+	assert.Panics(t, func() {
+		gas.Deliver(context.Background(), store.MemStore(), nil, help.PanicHandler(perr))
+	})
+
+	// handlers can charge gas via the context
+	h := help.Wrap(gas, gasHandler{amount: 1200})
+	_, err := h.Deliver(context.Background(), store.MemStore(), nil)
+	assert.True(t, errors.IsOutOfGasErr(err))
+}
+
+// gasHandler consumes the given amount from the meter in the context
+type gasHandler struct {
+	amount int64
+}
+
+var _ weave.Handler = gasHandler{}
+
+func (g gasHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	meter, _ := weave.GetGasMeter(ctx)
+	meter.ConsumeGas(g.amount, "test")
+	return weave.CheckResult{}, nil
+}
+
+func (g gasHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	meter, _ := weave.GetGasMeter(ctx)
+	meter.ConsumeGas(g.amount, "test")
+	return weave.DeliverResult{}, nil
+}
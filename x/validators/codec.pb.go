@@ -12,6 +12,8 @@
 		PubKey
 		SetValidators
 		Accounts
+		Rules
+		BlockChange
 */
 package validators
 
@@ -124,11 +126,87 @@ func (m *Accounts) GetAddresses() [][]byte {
 	return nil
 }
 
+// Rules limit how much the validators may change, so a single
+// bad update cannot take over or halt the chain.
+// A zero value disables the rule.
+type Rules struct {
+	// max sum of all power changes in one block,
+	// in percent of the total power before the block
+	MaxChangePercent int64 `protobuf:"varint,1,opt,name=max_change_percent,json=maxChangePercent,proto3" json:"max_change_percent,omitempty"`
+	// min number of validators left after a change
+	MinValidators int64 `protobuf:"varint,2,opt,name=min_validators,json=minValidators,proto3" json:"min_validators,omitempty"`
+	// max power of a single validator, in percent of the total power
+	MaxSharePercent int64 `protobuf:"varint,3,opt,name=max_share_percent,json=maxSharePercent,proto3" json:"max_share_percent,omitempty"`
+}
+
+func (m *Rules) Reset()                    { *m = Rules{} }
+func (m *Rules) String() string            { return proto.CompactTextString(m) }
+func (*Rules) ProtoMessage()               {}
+func (*Rules) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{4} }
+
+func (m *Rules) GetMaxChangePercent() int64 {
+	if m != nil {
+		return m.MaxChangePercent
+	}
+	return 0
+}
+
+func (m *Rules) GetMinValidators() int64 {
+	if m != nil {
+		return m.MinValidators
+	}
+	return 0
+}
+
+func (m *Rules) GetMaxSharePercent() int64 {
+	if m != nil {
+		return m.MaxSharePercent
+	}
+	return 0
+}
+
+// BlockChange tracks how much the power changed in one block
+type BlockChange struct {
+	Height int64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	// total power before the first change in this block
+	BasePower int64 `protobuf:"varint,2,opt,name=base_power,json=basePower,proto3" json:"base_power,omitempty"`
+	// sum of all power changes in this block
+	ChangedPower int64 `protobuf:"varint,3,opt,name=changed_power,json=changedPower,proto3" json:"changed_power,omitempty"`
+}
+
+func (m *BlockChange) Reset()                    { *m = BlockChange{} }
+func (m *BlockChange) String() string            { return proto.CompactTextString(m) }
+func (*BlockChange) ProtoMessage()               {}
+func (*BlockChange) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{5} }
+
+func (m *BlockChange) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *BlockChange) GetBasePower() int64 {
+	if m != nil {
+		return m.BasePower
+	}
+	return 0
+}
+
+func (m *BlockChange) GetChangedPower() int64 {
+	if m != nil {
+		return m.ChangedPower
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Validator)(nil), "validators.Validator")
 	proto.RegisterType((*PubKey)(nil), "validators.PubKey")
 	proto.RegisterType((*SetValidators)(nil), "validators.SetValidators")
 	proto.RegisterType((*Accounts)(nil), "validators.Accounts")
+	proto.RegisterType((*Rules)(nil), "validators.Rules")
+	proto.RegisterType((*BlockChange)(nil), "validators.BlockChange")
 }
 func (m *Validator) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -253,6 +331,72 @@ func (m *Accounts) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *Rules) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Rules) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.MaxChangePercent != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MaxChangePercent))
+	}
+	if m.MinValidators != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MinValidators))
+	}
+	if m.MaxSharePercent != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MaxSharePercent))
+	}
+	return i, nil
+}
+
+func (m *BlockChange) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlockChange) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Height != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Height))
+	}
+	if m.BasePower != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.BasePower))
+	}
+	if m.ChangedPower != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ChangedPower))
+	}
+	return i, nil
+}
+
 func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -315,6 +459,36 @@ func (m *Accounts) Size() (n int) {
 	return n
 }
 
+func (m *Rules) Size() (n int) {
+	var l int
+	_ = l
+	if m.MaxChangePercent != 0 {
+		n += 1 + sovCodec(uint64(m.MaxChangePercent))
+	}
+	if m.MinValidators != 0 {
+		n += 1 + sovCodec(uint64(m.MinValidators))
+	}
+	if m.MaxSharePercent != 0 {
+		n += 1 + sovCodec(uint64(m.MaxSharePercent))
+	}
+	return n
+}
+
+func (m *BlockChange) Size() (n int) {
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovCodec(uint64(m.Height))
+	}
+	if m.BasePower != 0 {
+		n += 1 + sovCodec(uint64(m.BasePower))
+	}
+	if m.ChangedPower != 0 {
+		n += 1 + sovCodec(uint64(m.ChangedPower))
+	}
+	return n
+}
+
 func sovCodec(x uint64) (n int) {
 	for {
 		n++
@@ -728,6 +902,220 @@ func (m *Accounts) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *Rules) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Rules: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Rules: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxChangePercent", wireType)
+			}
+			m.MaxChangePercent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxChangePercent |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinValidators", wireType)
+			}
+			m.MinValidators = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinValidators |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSharePercent", wireType)
+			}
+			m.MaxSharePercent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxSharePercent |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BlockChange) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BlockChange: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BlockChange: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BasePower", wireType)
+			}
+			m.BasePower = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BasePower |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChangedPower", wireType)
+			}
+			m.ChangedPower = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ChangedPower |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipCodec(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -836,23 +1224,30 @@ var (
 func init() { proto.RegisterFile("x/validators/codec.proto", fileDescriptorCodec) }
 
 var fileDescriptorCodec = []byte{
-	// 279 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xcd, 0x4a, 0xc4, 0x30,
-	0x14, 0x85, 0x27, 0x76, 0xec, 0xd8, 0x3b, 0x15, 0x24, 0x28, 0x04, 0x91, 0x5a, 0xba, 0xea, 0xc6,
-	0x56, 0x47, 0x7c, 0x00, 0x67, 0xe1, 0xc6, 0x8d, 0x44, 0x70, 0x2b, 0xfd, 0x89, 0x75, 0x50, 0x27,
-	0xa1, 0x49, 0xd4, 0xbe, 0x85, 0x8f, 0x35, 0x4b, 0x9f, 0x40, 0xa4, 0xbe, 0x88, 0x78, 0xeb, 0x4c,
-	0xbb, 0x3b, 0xdf, 0xe1, 0xdc, 0x9c, 0x7b, 0x03, 0xec, 0x3d, 0x7d, 0xcd, 0x9e, 0x17, 0x65, 0x66,
-	0x64, 0xad, 0xd3, 0x42, 0x96, 0xa2, 0x48, 0x54, 0x2d, 0x8d, 0xa4, 0xd0, 0xfb, 0x87, 0x27, 0xd5,
-	0xc2, 0x3c, 0xda, 0x3c, 0x29, 0xe4, 0x4b, 0x5a, 0xc9, 0x4a, 0xa6, 0x18, 0xc9, 0xed, 0x03, 0x12,
-	0x02, 0xaa, 0x6e, 0x34, 0x5a, 0x82, 0x77, 0xb7, 0x1e, 0xa6, 0x0c, 0x26, 0x59, 0x59, 0xd6, 0x42,
-	0x6b, 0x46, 0x42, 0x12, 0xfb, 0x7c, 0x8d, 0xf4, 0x0c, 0x26, 0xca, 0xe6, 0xf7, 0x4f, 0xa2, 0x61,
-	0x5b, 0x21, 0x89, 0xa7, 0x33, 0x9a, 0xf4, 0x9d, 0xc9, 0x8d, 0xcd, 0xaf, 0x45, 0x33, 0x1f, 0xaf,
-	0xbe, 0x8e, 0x47, 0xdc, 0x55, 0x48, 0x74, 0x1f, 0xb6, 0x95, 0x7c, 0x13, 0x35, 0x73, 0x42, 0x12,
-	0x3b, 0xbc, 0x83, 0xe8, 0x14, 0xdc, 0x2e, 0x4d, 0x29, 0x8c, 0x4d, 0xa3, 0x04, 0x36, 0x79, 0x1c,
-	0xf5, 0x9f, 0x57, 0x66, 0x26, 0xc3, 0x0e, 0x9f, 0xa3, 0x8e, 0xae, 0x60, 0xf7, 0x56, 0x98, 0xcd,
-	0x92, 0x9a, 0x5e, 0xc0, 0xe0, 0x5e, 0x46, 0x42, 0x27, 0x9e, 0xce, 0x0e, 0x86, 0xeb, 0x6c, 0xb2,
-	0x7c, 0x10, 0x8c, 0x62, 0xd8, 0xb9, 0x2c, 0x0a, 0x69, 0x97, 0x46, 0xd3, 0x23, 0xf0, 0xfe, 0x2f,
-	0x13, 0xdd, 0x0b, 0x3e, 0xef, 0x8d, 0xf9, 0xde, 0xaa, 0x0d, 0xc8, 0x67, 0x1b, 0x90, 0xef, 0x36,
-	0x20, 0x1f, 0x3f, 0xc1, 0x28, 0x77, 0xf1, 0xb3, 0xce, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0xf0,
-	0x9c, 0x78, 0xfb, 0x83, 0x01, 0x00, 0x00,
+	// 400 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x52, 0xd1, 0x8e, 0x94, 0x30,
+	0x14, 0xdd, 0xee, 0xec, 0xb2, 0x72, 0x87, 0xd1, 0xb5, 0x51, 0x43, 0x8c, 0x22, 0xc1, 0x98, 0x10,
+	0xa3, 0xa0, 0x6b, 0xfc, 0x00, 0xc7, 0xc4, 0x17, 0x5f, 0x26, 0xdd, 0xc4, 0x57, 0x52, 0x4a, 0x05,
+	0xb2, 0x03, 0x25, 0xb4, 0x28, 0xf3, 0x07, 0x3e, 0xfa, 0x59, 0xfb, 0xe8, 0x17, 0x18, 0x33, 0xfe,
+	0xc8, 0x86, 0x96, 0x19, 0x78, 0xbb, 0xe7, 0x70, 0x7a, 0xce, 0xb9, 0xa5, 0xe0, 0xf6, 0xf1, 0x0f,
+	0xba, 0x2d, 0x33, 0xaa, 0x44, 0x2b, 0x63, 0x26, 0x32, 0xce, 0xa2, 0xa6, 0x15, 0x4a, 0x60, 0x98,
+	0xf8, 0xa7, 0x6f, 0xf3, 0x52, 0x15, 0x5d, 0x1a, 0x31, 0x51, 0xc5, 0xb9, 0xc8, 0x45, 0xac, 0x25,
+	0x69, 0xf7, 0x5d, 0x23, 0x0d, 0xf4, 0x64, 0x8e, 0x06, 0x35, 0xd8, 0xdf, 0x0e, 0x87, 0xb1, 0x0b,
+	0x17, 0x34, 0xcb, 0x5a, 0x2e, 0xa5, 0x8b, 0x7c, 0x14, 0x3a, 0xe4, 0x00, 0xf1, 0x7b, 0xb8, 0x68,
+	0xba, 0x34, 0xb9, 0xe1, 0x3b, 0xf7, 0xd4, 0x47, 0xe1, 0xf2, 0x0a, 0x47, 0x53, 0x66, 0xb4, 0xe9,
+	0xd2, 0xaf, 0x7c, 0xb7, 0x3e, 0xbb, 0xfd, 0xfb, 0xe2, 0x84, 0x58, 0x8d, 0x46, 0xf8, 0x11, 0x9c,
+	0x37, 0xe2, 0x27, 0x6f, 0xdd, 0x85, 0x8f, 0xc2, 0x05, 0x31, 0x20, 0x78, 0x07, 0x96, 0x51, 0x63,
+	0x0c, 0x67, 0x6a, 0xd7, 0x70, 0x9d, 0x64, 0x13, 0x3d, 0x0f, 0x5c, 0x46, 0x15, 0xd5, 0x19, 0x0e,
+	0xd1, 0x73, 0xf0, 0x05, 0x56, 0xd7, 0x5c, 0x1d, 0x4b, 0x4a, 0xfc, 0x11, 0x66, 0xfb, 0xba, 0xc8,
+	0x5f, 0x84, 0xcb, 0xab, 0xc7, 0xf3, 0x3a, 0x47, 0x2d, 0x99, 0x09, 0x83, 0x10, 0xee, 0x7d, 0x62,
+	0x4c, 0x74, 0xb5, 0x92, 0xf8, 0x19, 0xd8, 0xe3, 0x66, 0xdc, 0x38, 0x38, 0x64, 0x22, 0x82, 0x5f,
+	0x08, 0xce, 0x49, 0xb7, 0xe5, 0x12, 0xbf, 0x01, 0x5c, 0xd1, 0x3e, 0x61, 0x05, 0xad, 0x73, 0x9e,
+	0x34, 0xbc, 0x65, 0xbc, 0x56, 0xba, 0xf1, 0x82, 0x5c, 0x56, 0xb4, 0xff, 0xac, 0x3f, 0x6c, 0x0c,
+	0x8f, 0x5f, 0xc1, 0xfd, 0xaa, 0xac, 0x93, 0x59, 0xb9, 0x53, 0xad, 0x5c, 0x55, 0x65, 0x3d, 0xeb,
+	0xff, 0x1a, 0x1e, 0x0e, 0xa6, 0xb2, 0xa0, 0xed, 0xe4, 0x69, 0x2e, 0xe9, 0x41, 0x45, 0xfb, 0xeb,
+	0x81, 0x1f, 0x2d, 0x83, 0x12, 0x96, 0xeb, 0xad, 0x60, 0x37, 0x26, 0x08, 0x3f, 0x01, 0xab, 0xe0,
+	0x65, 0x5e, 0x1c, 0x3a, 0x8c, 0x08, 0x3f, 0x07, 0x48, 0xa9, 0xe4, 0x89, 0xb9, 0x70, 0x93, 0x6a,
+	0x0f, 0xcc, 0x66, 0x20, 0xf0, 0x4b, 0x58, 0x99, 0x15, 0xb2, 0x64, 0xfe, 0x4b, 0x9c, 0x91, 0xd4,
+	0xa2, 0xf5, 0xe5, 0xed, 0xde, 0x43, 0x7f, 0xf6, 0x1e, 0xfa, 0xb7, 0xf7, 0xd0, 0xef, 0xff, 0xde,
+	0x49, 0x6a, 0xe9, 0x27, 0xf2, 0xe1, 0x6e, 0x00, 0x9b, 0x5a, 0x1f, 0xb0, 0x79, 0x02, 0x00, 0x00,
 }
@@ -14,21 +14,23 @@ type CheckAddress func(address weave.Address) bool
 // should work plenty fine, but you can add other logic
 // if so desired
 type Controller interface {
-	CanUpdateValidators(store weave.KVStore, checkAddress CheckAddress, diff []abci.Validator) ([]abci.Validator, error)
+	CanUpdateValidators(ctx weave.Context, store weave.KVStore, checkAddress CheckAddress, diff []abci.Validator) ([]abci.Validator, error)
 }
 
 // BaseController is a simple implementation of controller
-// wallet must return something that supports AsSet
+// wallet must return something that supports AsSet.
+// It also enforces the Rules from genesis with a Guard.
 type BaseController struct {
 	bucket orm.Bucket
+	guard  Guard
 }
 
 // NewController returns a basic controller implementation
 func NewController(bucket orm.Bucket) BaseController {
-	return BaseController{bucket: bucket}
+	return BaseController{bucket: bucket, guard: NewGuard()}
 }
 
-func (c BaseController) CanUpdateValidators(store weave.KVStore, checkAddress CheckAddress, diff []abci.Validator) ([]abci.Validator, error) {
+func (c BaseController) CanUpdateValidators(ctx weave.Context, store weave.KVStore, checkAddress CheckAddress, diff []abci.Validator) ([]abci.Validator, error) {
 	if len(diff) == 0 {
 		return nil, ErrEmptyDiff()
 	}
@@ -43,5 +45,10 @@ func (c BaseController) CanUpdateValidators(store weave.KVStore, checkAddress Ch
 		return nil, errors.ErrUnauthorized()
 	}
 
+	err = c.guard.Check(ctx, store, diff)
+	if err != nil {
+		return nil, err
+	}
+
 	return diff, nil
 }
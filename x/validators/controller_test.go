@@ -1,6 +1,7 @@
 package validators
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
@@ -29,9 +30,10 @@ func TestController(t *testing.T) {
 		accountsJson, err := json.Marshal(accts)
 		So(err, ShouldBeNil)
 
-		diff := []abci.Validator{{}}
+		diff := []abci.Validator{{PubKey: testPubKey("a"), Power: 5}}
 		emptyDiff := make([]abci.Validator, 0)
 
+		ctx := context.Background()
 		kv := store.MemStore()
 		bucket := NewBucket()
 		ctrl := NewController(bucket)
@@ -42,7 +44,7 @@ func TestController(t *testing.T) {
 			So(err, ShouldBeNil)
 
 			Convey("Everything is in order", func() {
-				d, err := ctrl.CanUpdateValidators(kv, checkAddress, diff)
+				d, err := ctrl.CanUpdateValidators(ctx, kv, checkAddress, diff)
 				So(err, ShouldBeNil)
 				So(d, ShouldResemble, diff)
 			})
@@ -50,17 +52,17 @@ func TestController(t *testing.T) {
 			Convey("Accounts type is nil", func() {
 				bucket.Delete(kv, []byte(Key))
 				//bucket.Save(kv, orm.NewSimpleObj([]byte(Key), set))
-				_, err = ctrl.CanUpdateValidators(kv, checkAddress, diff)
+				_, err = ctrl.CanUpdateValidators(ctx, kv, checkAddress, diff)
 				So(err.Error(), ShouldResemble, ErrWrongType(nil).Error())
 			})
 
 			Convey("No permission", func() {
-				_, err = ctrl.CanUpdateValidators(kv, checkAddress2, diff)
+				_, err = ctrl.CanUpdateValidators(ctx, kv, checkAddress2, diff)
 				So(err.Error(), ShouldResemble, errors.ErrUnauthorized().Error())
 			})
 
 			Convey("Empty diff", func() {
-				_, err := ctrl.CanUpdateValidators(kv, checkAddress, emptyDiff)
+				_, err := ctrl.CanUpdateValidators(ctx, kv, checkAddress, emptyDiff)
 				So(err.Error(), ShouldResemble, ErrEmptyDiff().Error())
 			})
 
@@ -69,14 +71,14 @@ func TestController(t *testing.T) {
 				So(err, ShouldBeNil)
 				bucket.Delete(kv, []byte(Key))
 				kv.Set([]byte(Key), []byte(set.String()))
-				_, err = ctrl.CanUpdateValidators(kv, checkAddress, diff)
+				_, err = ctrl.CanUpdateValidators(ctx, kv, checkAddress, diff)
 				So(err.Error(), ShouldResemble, ErrWrongType(set).Error())
 			})
 		})
 
 		Convey("When init didn't happen", func() {
 			Convey("Error on GetAccounts", func() {
-				_, err = ctrl.CanUpdateValidators(kv, checkAddress, diff)
+				_, err = ctrl.CanUpdateValidators(ctx, kv, checkAddress, diff)
 				So(err.Error(), ShouldResemble, ErrWrongType(nil).Error())
 			})
 		})
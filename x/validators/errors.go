@@ -10,13 +10,25 @@ import (
 // ABCI Response Codes
 // x/update_validators reserves 40 ~ 49.
 const (
-	CodeEmptyDiff uint32 = 40
-	CodeWrongType        = 41
+	CodeEmptyDiff            uint32 = 40
+	CodeWrongType                   = 41
+	CodeInvalidValidator            = 42
+	CodePowerChangeTooLarge         = 43
+	CodeTooFewValidators            = 44
+	CodeValidatorTooPowerful        = 45
+	CodeInvalidRules                = 46
 )
 
 var (
 	errEmptyDiff = fmt.Errorf("Empty validator diff")
 	errWrongType = fmt.Errorf("Wrong type for accounts storage")
+
+	errInvalidValidator = fmt.Errorf("Invalid validator")
+
+	errPowerChangeTooLarge  = fmt.Errorf("Power change too large")
+	errTooFewValidators     = fmt.Errorf("Too few validators")
+	errValidatorTooPowerful = fmt.Errorf("Validator too powerful")
+	errInvalidRules         = fmt.Errorf("Invalid validator rules")
 )
 
 func ErrEmptyDiff() error {
@@ -30,3 +42,41 @@ func ErrWrongType(t interface{}) error {
 	}
 	return errors.WithLog(typeName, errWrongType, CodeWrongType)
 }
+
+func ErrInvalidValidator(reason string) error {
+	return errors.WithLog(reason, errInvalidValidator, CodeInvalidValidator)
+}
+func IsInvalidValidatorErr(err error) bool {
+	return errors.IsSameError(errInvalidValidator, err)
+}
+
+func ErrPowerChangeTooLarge(changed, base, max int64) error {
+	msg := fmt.Sprintf("%d of %d power changed in this block, max %d%%", changed, base, max)
+	return errors.WithLog(msg, errPowerChangeTooLarge, CodePowerChangeTooLarge)
+}
+func IsPowerChangeTooLargeErr(err error) bool {
+	return errors.IsSameError(errPowerChangeTooLarge, err)
+}
+
+func ErrTooFewValidators(count int, min int64) error {
+	msg := fmt.Sprintf("%d left, min %d", count, min)
+	return errors.WithLog(msg, errTooFewValidators, CodeTooFewValidators)
+}
+func IsTooFewValidatorsErr(err error) bool {
+	return errors.IsSameError(errTooFewValidators, err)
+}
+
+func ErrValidatorTooPowerful(power, total, max int64) error {
+	msg := fmt.Sprintf("%d of %d power, max %d%%", power, total, max)
+	return errors.WithLog(msg, errValidatorTooPowerful, CodeValidatorTooPowerful)
+}
+func IsValidatorTooPowerfulErr(err error) bool {
+	return errors.IsSameError(errValidatorTooPowerful, err)
+}
+
+func ErrInvalidRules(reason string) error {
+	return errors.WithLog(reason, errInvalidRules, CodeInvalidRules)
+}
+func IsInvalidRulesErr(err error) bool {
+	return errors.IsSameError(errInvalidRules, err)
+}
@@ -22,9 +22,11 @@ func RegisterRoutes(r weave.Registry, auth x.Authenticator,
 	r.Handle(pathUpdate, NewUpdateHandler(auth, control, authCheckAddress))
 }
 
-// RegisterQuery will register this bucket as "/validators"
+// RegisterQuery will register this bucket as "/validators",
+// and the current validator set as "/validators/set"
 func RegisterQuery(qr weave.QueryRouter) {
 	NewBucket().Register("validators", qr)
+	NewValidatorSet().Register("validators/set", qr)
 }
 
 // UpdateHandler will handle sending coins
@@ -58,8 +60,12 @@ func (h UpdateHandler) Check(ctx weave.Context, store weave.KVStore,
 	if !ok {
 		return res, errors.ErrUnknownTxType(rmsg)
 	}
+	err = msg.Validate()
+	if err != nil {
+		return res, err
+	}
 
-	_, err = h.control.CanUpdateValidators(store, h.authCheckAddress(h.auth, ctx), msg.AsABCI())
+	_, err = h.control.CanUpdateValidators(ctx, store, h.authCheckAddress(h.auth, ctx), msg.AsABCI())
 	if err != nil {
 		return res, err
 	}
@@ -82,8 +88,12 @@ func (h UpdateHandler) Deliver(ctx weave.Context, store weave.KVStore,
 	if !ok {
 		return res, errors.ErrUnknownTxType(rmsg)
 	}
+	err = msg.Validate()
+	if err != nil {
+		return res, err
+	}
 
-	diff, err := h.control.CanUpdateValidators(store, h.authCheckAddress(h.auth, ctx), msg.AsABCI())
+	diff, err := h.control.CanUpdateValidators(ctx, store, h.authCheckAddress(h.auth, ctx), msg.AsABCI())
 	if err != nil {
 		return res, err
 	}
@@ -35,10 +35,11 @@ func TestHandler(t *testing.T) {
 		err = init.FromGenesis(weave.Options{optKey: accountsJson}, kv)
 		So(err, ShouldBeNil)
 		ctrl := NewController(bucket)
+		valid := &Validator{PubKey: PubKey{Type: KeyTypeEd25519, Data: make([]byte, 32)}, Power: 5}
 
 		Convey("Check Deliver and Check", func() {
 			Convey("With a right address", func() {
-				tx := helpers.MockTx(&SetValidators{Validators: []*Validator{{}}})
+				tx := helpers.MockTx(&SetValidators{Validators: []*Validator{valid}})
 				handler := NewUpdateHandler(auth, ctrl, authCheckAddress)
 
 				res, err := handler.Deliver(nil, kv, tx)
@@ -50,7 +51,7 @@ func TestHandler(t *testing.T) {
 			})
 
 			Convey("With a wrong address", func() {
-				tx := helpers.MockTx(&SetValidators{Validators: []*Validator{{}}})
+				tx := helpers.MockTx(&SetValidators{Validators: []*Validator{valid}})
 				handler := NewUpdateHandler(auth2, ctrl, authCheckAddress)
 
 				_, err := handler.Deliver(nil, kv, tx)
@@ -60,6 +61,23 @@ func TestHandler(t *testing.T) {
 				So(err.Error(), ShouldResemble, errors.ErrUnauthorized().Error())
 			})
 
+			Convey("With an invalid validator", func() {
+				for _, msg := range []*SetValidators{
+					{},
+					{Validators: []*Validator{{}}},
+					{Validators: []*Validator{{PubKey: valid.PubKey, Power: -1}}},
+				} {
+					tx := helpers.MockTx(msg)
+					handler := NewUpdateHandler(auth, ctrl, authCheckAddress)
+
+					_, err := handler.Deliver(nil, kv, tx)
+					So(IsInvalidValidatorErr(err), ShouldBeTrue)
+
+					_, err = handler.Check(nil, kv, tx)
+					So(IsInvalidValidatorErr(err), ShouldBeTrue)
+				}
+			})
+
 			Convey("With an invalid message", func() {
 				msg := &cash.SendMsg{}
 				tx := helpers.MockTx(msg)
@@ -4,13 +4,17 @@ import (
 	"github.com/confio/weave"
 )
 
-const optKey = "update_validators"
+const (
+	optKey   = "update_validators"
+	rulesKey = "validator_rules"
+)
 
 // Initializer fulfils the InitStater interface to load data from
-// the genesis file
+// the genesis file, and can export the state in the same format
 type Initializer struct{}
 
 var _ weave.Initializer = Initializer{}
+var _ weave.Exporter = Initializer{}
 
 // FromGenesis will parse initial account info from genesis
 // and save it to the database
@@ -34,5 +38,46 @@ func (Initializer) FromGenesis(opts weave.Options, kv weave.KVStore) error {
 		return err
 	}
 
-	return nil
+	return loadRules(opts, kv)
+}
+
+// loadRules stores the validator rules, if any are in genesis
+func loadRules(opts weave.Options, kv weave.KVStore) error {
+	if len(opts[rulesKey]) == 0 {
+		return nil
+	}
+	var rules Rules
+	err := opts.ReadOptions(rulesKey, &rules)
+	if err != nil {
+		return err
+	}
+	return NewGuard().SetRules(kv, &rules)
+}
+
+// ToGenesis writes the accounts allowed to update validators
+// and the validator rules to the options, if any were set
+func (Initializer) ToGenesis(opts weave.Options, kv weave.ReadOnlyKVStore) error {
+	rules, err := NewGuard().GetRules(kv)
+	if err != nil {
+		return err
+	}
+	if !rules.IsEmpty() {
+		err = opts.WriteOptions(rulesKey, rules)
+		if err != nil {
+			return err
+		}
+	}
+
+	obj, err := NewBucket().Get(kv, []byte(Key))
+	if err != nil || obj == nil {
+		return err
+	}
+	accts, ok := obj.Value().(*Accounts)
+	if !ok {
+		return ErrWrongType(obj.Value())
+	}
+	if len(accts.Addresses) == 0 {
+		return nil
+	}
+	return opts.WriteOptions(optKey, AsWeaveAccounts(accts))
 }
@@ -59,5 +59,42 @@ func TestInitState(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(accounts, ShouldResemble, AsAccounts(accts2))
 		})
+
+		Convey("Export writes nothing without accounts", func() {
+			opts := weave.Options{}
+			err := init.ToGenesis(opts, kv)
+			So(err, ShouldBeNil)
+			So(opts, ShouldBeEmpty)
+		})
+
+		Convey("Export can be read back", func() {
+			err := init.FromGenesis(weave.Options{optKey: accountsJson2}, kv)
+			So(err, ShouldBeNil)
+
+			opts := weave.Options{}
+			err = init.ToGenesis(opts, kv)
+			So(err, ShouldBeNil)
+			So(string(opts[optKey]), ShouldEqual, string(accountsJson2))
+		})
+
+		Convey("Init stores validator rules", func() {
+			rulesJson := []byte(`{"max_change_percent":10,"min_validators":4,"max_share_percent":33}`)
+			err := init.FromGenesis(weave.Options{rulesKey: rulesJson}, kv)
+			So(err, ShouldBeNil)
+
+			rules, err := NewGuard().GetRules(kv)
+			So(err, ShouldBeNil)
+			So(rules, ShouldResemble, &Rules{MaxChangePercent: 10, MinValidators: 4, MaxSharePercent: 33})
+
+			opts := weave.Options{}
+			err = init.ToGenesis(opts, kv)
+			So(err, ShouldBeNil)
+			So(string(opts[rulesKey]), ShouldEqual, string(rulesJson))
+		})
+
+		Convey("Init fails with bad validator rules", func() {
+			err := init.FromGenesis(weave.Options{rulesKey: []byte(`{"max_share_percent":120}`)}, kv)
+			So(IsInvalidRulesErr(err), ShouldBeTrue)
+		})
 	})
 }
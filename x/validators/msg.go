@@ -1,6 +1,8 @@
 package validators
 
 import (
+	"fmt"
+
 	"github.com/confio/weave"
 	abci "github.com/tendermint/abci/types"
 )
@@ -8,7 +10,14 @@ import (
 // Ensure we implement the Msg interface
 var _ weave.Msg = (*SetValidators)(nil)
 
-const pathUpdate = "validators/update"
+const (
+	pathUpdate = "validators/update"
+
+	// KeyTypeEd25519 is the only validator key type
+	// tendermint accepts
+	KeyTypeEd25519 = "ed25519"
+	ed25519KeySize = 32
+)
 
 // Path returns the routing path for this message
 func (*SetValidators) Path() string {
@@ -23,6 +32,21 @@ func (m Validator) AsABCI() abci.Validator {
 	}
 }
 
+// Validate only accepts keys tendermint can use for a
+// validator, as it halts on anything else
+func (m PubKey) Validate() error {
+	if len(m.Data) == 0 {
+		return ErrInvalidValidator("Missing pubkey")
+	}
+	if m.Type != KeyTypeEd25519 {
+		return ErrInvalidValidator("Unsupported key type: " + m.Type)
+	}
+	if len(m.Data) != ed25519KeySize {
+		return ErrInvalidValidator(fmt.Sprintf("Invalid key size: %d", len(m.Data)))
+	}
+	return nil
+}
+
 func (m PubKey) AsABCI() abci.PubKey {
 	return abci.PubKey{
 		Data: m.Data,
@@ -30,6 +54,31 @@ func (m PubKey) AsABCI() abci.PubKey {
 	}
 }
 
+// Validate requires at least one validator, and a valid
+// diff, see ValidateDiff
+func (m *SetValidators) Validate() error {
+	if len(m.Validators) == 0 {
+		return ErrInvalidValidator("No validators")
+	}
+	return ValidateDiff(m.AsABCI())
+}
+
+// ValidateDiff makes sure every entry of a validator diff has a
+// valid pubkey and no negative power, a power of 0 removes it
+func ValidateDiff(diff []abci.Validator) error {
+	for _, d := range diff {
+		pubkey := PubKey{Type: d.PubKey.Type, Data: d.PubKey.Data}
+		err := pubkey.Validate()
+		if err != nil {
+			return err
+		}
+		if d.Power < 0 {
+			return ErrInvalidValidator("Negative power")
+		}
+	}
+	return nil
+}
+
 func (m *SetValidators) AsABCI() []abci.Validator {
 	validators := make([]abci.Validator, len(m.Validators))
 	for k, v := range m.Validators {
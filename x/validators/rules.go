@@ -0,0 +1,227 @@
+package validators
+
+import (
+	"fmt"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	abci "github.com/tendermint/abci/types"
+)
+
+const (
+	// RulesBucketName contains the safety rules from genesis
+	RulesBucketName = "valrules"
+	// RulesKey is used to store the rules
+	RulesKey = "rules"
+
+	// changeBucketName tracks the power change in the current block
+	changeBucketName = "valchng"
+	changeKey        = "block"
+)
+
+var _ orm.CloneableData = (*Rules)(nil)
+var _ orm.CloneableData = (*BlockChange)(nil)
+
+// Validate makes sure all limits are in range
+func (m *Rules) Validate() error {
+	if m.MaxChangePercent < 0 || m.MaxChangePercent > 100 {
+		return ErrInvalidRules(fmt.Sprintf("max_change_percent %d", m.MaxChangePercent))
+	}
+	if m.MinValidators < 0 {
+		return ErrInvalidRules(fmt.Sprintf("min_validators %d", m.MinValidators))
+	}
+	if m.MaxSharePercent < 0 || m.MaxSharePercent > 100 {
+		return ErrInvalidRules(fmt.Sprintf("max_share_percent %d", m.MaxSharePercent))
+	}
+	return nil
+}
+
+// Copy makes new rules with the same limits
+func (m *Rules) Copy() orm.CloneableData {
+	return &Rules{
+		MaxChangePercent: m.MaxChangePercent,
+		MinValidators:    m.MinValidators,
+		MaxSharePercent:  m.MaxSharePercent,
+	}
+}
+
+// IsEmpty is true if no rule is enabled
+func (m *Rules) IsEmpty() bool {
+	return m.MaxChangePercent == 0 && m.MinValidators == 0 &&
+		m.MaxSharePercent == 0
+}
+
+// Validate only requires positive numbers
+func (m *BlockChange) Validate() error {
+	if m.Height < 0 || m.BasePower < 0 || m.ChangedPower < 0 {
+		return ErrInvalidRules("negative block change")
+	}
+	return nil
+}
+
+// Copy makes a new BlockChange with the same data
+func (m *BlockChange) Copy() orm.CloneableData {
+	return &BlockChange{
+		Height:       m.Height,
+		BasePower:    m.BasePower,
+		ChangedPower: m.ChangedPower,
+	}
+}
+
+// Guard enforces the Rules stored in genesis on every
+// validator diff, so a single bad (or malicious) update cannot
+// hand the chain to one validator or halt it.
+//
+// It compares the diff against the ValidatorSet, so the app
+// must store it with StoreApp.WithValidators for the rules to
+// have any meaning.
+type Guard struct {
+	rules   orm.Bucket
+	changes orm.Bucket
+	set     ValidatorSet
+}
+
+// NewGuard uses the default buckets
+func NewGuard() Guard {
+	return Guard{
+		rules:   orm.NewBucket(RulesBucketName, orm.NewSimpleObj(nil, new(Rules))),
+		changes: orm.NewBucket(changeBucketName, orm.NewSimpleObj(nil, new(BlockChange))),
+		set:     NewValidatorSet(),
+	}
+}
+
+// GetRules returns the stored rules, or empty rules
+// if none were set
+func (g Guard) GetRules(db weave.ReadOnlyKVStore) (*Rules, error) {
+	obj, err := g.rules.Get(db, []byte(RulesKey))
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return new(Rules), nil
+	}
+	rules, ok := obj.Value().(*Rules)
+	if !ok {
+		return nil, ErrWrongType(obj.Value())
+	}
+	return rules, nil
+}
+
+// SetRules validates and stores the rules
+func (g Guard) SetRules(db weave.KVStore, rules *Rules) error {
+	return g.rules.Save(db, orm.NewSimpleObj([]byte(RulesKey), rules))
+}
+
+// Check returns an error if the diff is invalid, or applying it
+// to the current validators would break any of the rules.
+// Otherwise, it adds the diff to the power changed in this block.
+//
+// A set that already breaks the share or count rules (eg. a
+// single genesis validator) could never change if they were
+// applied as they are, so a diff only has to make the set no
+// worse: no higher maximum share, no fewer validators.
+func (g Guard) Check(ctx weave.Context, db weave.KVStore, diff []abci.Validator) error {
+	err := ValidateDiff(diff)
+	if err != nil {
+		return err
+	}
+	rules, err := g.GetRules(db)
+	if err != nil || rules.IsEmpty() {
+		return err
+	}
+
+	current, err := g.set.GetValidators(db)
+	if err != nil {
+		return err
+	}
+	powers := make(map[string]int64, len(current))
+	var total int64
+	for _, v := range current {
+		powers[string(v.PubKey.Data)] = v.Power
+		total += v.Power
+	}
+	count, top := len(powers), maxPower(powers)
+
+	var changed int64
+	for _, d := range diff {
+		key := string(d.PubKey.Data)
+		changed += abs(d.Power - powers[key])
+		if d.Power == 0 {
+			delete(powers, key)
+		} else {
+			powers[key] = d.Power
+		}
+	}
+
+	newCount := len(powers)
+	if rules.MinValidators > 0 && int64(newCount) < rules.MinValidators &&
+		newCount < count {
+		return ErrTooFewValidators(newCount, rules.MinValidators)
+	}
+
+	if rules.MaxSharePercent > 0 {
+		var newTotal int64
+		for _, p := range powers {
+			newTotal += p
+		}
+		newTop := maxPower(powers)
+		// newTop/newTotal > top/total, anything beats no validators
+		worse := total == 0 || newTop*total > top*newTotal
+		if newTop*100 > rules.MaxSharePercent*newTotal && worse {
+			return ErrValidatorTooPowerful(newTop, newTotal, rules.MaxSharePercent)
+		}
+	}
+
+	if rules.MaxChangePercent > 0 {
+		return g.addChange(ctx, db, total, changed, rules.MaxChangePercent)
+	}
+	return nil
+}
+
+// addChange adds changed to the power changed in this block,
+// and fails if it goes over max percent of the power at the
+// start of the block. It allows anything if there was no power,
+// so the first validators can be set, and a single unit of
+// power, so a set too small for any change can still grow.
+func (g Guard) addChange(ctx weave.Context, db weave.KVStore,
+	total, changed, max int64) error {
+
+	height, _ := weave.GetHeight(ctx)
+	obj, err := g.changes.Get(db, []byte(changeKey))
+	if err != nil {
+		return err
+	}
+	var change *BlockChange
+	if obj != nil {
+		change, _ = obj.Value().(*BlockChange)
+	}
+	// first change in this block
+	if change == nil || change.Height != height {
+		change = &BlockChange{Height: height, BasePower: total}
+	}
+
+	change.ChangedPower += changed
+	if change.BasePower > 0 && change.ChangedPower > 1 &&
+		change.ChangedPower*100 > max*change.BasePower {
+		return ErrPowerChangeTooLarge(change.ChangedPower, change.BasePower, max)
+	}
+	return g.changes.Save(db, orm.NewSimpleObj([]byte(changeKey), change))
+}
+
+// maxPower returns the highest power in the set
+func maxPower(powers map[string]int64) int64 {
+	var top int64
+	for _, p := range powers {
+		if p > top {
+			top = p
+		}
+	}
+	return top
+}
+
+func abs(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,182 @@
+package validators
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+)
+
+func TestGuard(t *testing.T) {
+	val := func(key string, power int64) abci.Validator {
+		return abci.Validator{
+			PubKey: testPubKey(key),
+			Power:  power,
+		}
+	}
+	start := []abci.Validator{val("a", 10), val("b", 10), val("c", 10), val("d", 10)}
+	rules := &Rules{MaxChangePercent: 25, MinValidators: 3, MaxSharePercent: 40}
+
+	cases := map[string]struct {
+		rules *Rules
+		// diffs to check, one per tx in the same block
+		diffs [][]abci.Validator
+		// check the error of the last diff, all others must pass
+		isErr func(error) bool
+	}{
+		"no rules allow anything": {
+			diffs: [][]abci.Validator{{val("a", 1000), val("b", 0), val("c", 0)}},
+		},
+		"remove one": {
+			rules: rules,
+			diffs: [][]abci.Validator{{val("d", 0)}},
+		},
+		"too few validators": {
+			rules: rules,
+			diffs: [][]abci.Validator{{val("c", 0), val("d", 0)}},
+			isErr: IsTooFewValidatorsErr,
+		},
+		"one validator too powerful": {
+			rules: rules,
+			diffs: [][]abci.Validator{{val("a", 30)}},
+			isErr: IsValidatorTooPowerfulErr,
+		},
+		"removal makes another too powerful": {
+			rules: &Rules{MaxSharePercent: 30},
+			diffs: [][]abci.Validator{{val("d", 0)}},
+			isErr: IsValidatorTooPowerfulErr,
+		},
+		"changes add up within a block": {
+			rules: rules,
+			diffs: [][]abci.Validator{
+				{val("a", 13)},
+				{val("b", 3)},
+				{val("e", 1)},
+			},
+			isErr: IsPowerChangeTooLargeErr,
+		},
+		"negative power": {
+			rules: rules,
+			diffs: [][]abci.Validator{{val("a", -5)}},
+			isErr: IsInvalidValidatorErr,
+		},
+		"negative power without rules": {
+			diffs: [][]abci.Validator{{val("a", -5)}},
+			isErr: IsInvalidValidatorErr,
+		},
+		"missing pubkey without rules": {
+			diffs: [][]abci.Validator{{{Power: 5}}},
+			isErr: IsInvalidValidatorErr,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			db := store.MemStore()
+			ctx := weave.WithHeight(context.Background(), 5)
+			guard := NewGuard()
+			require.NoError(t, guard.set.UpdateValidators(db, start))
+			if tc.rules != nil {
+				require.NoError(t, guard.SetRules(db, tc.rules))
+			}
+
+			last := len(tc.diffs) - 1
+			for _, diff := range tc.diffs[:last] {
+				require.NoError(t, guard.Check(ctx, db, diff))
+			}
+			err := guard.Check(ctx, db, tc.diffs[last])
+			if tc.isErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, tc.isErr(err), "%+v", err)
+			}
+		})
+	}
+}
+
+func TestGuardGrowIntoRules(t *testing.T) {
+	val := func(key string, power int64) []abci.Validator {
+		return []abci.Validator{{PubKey: testPubKey(key), Power: power}}
+	}
+	db := store.MemStore()
+	guard := NewGuard()
+	rules := &Rules{MaxChangePercent: 50, MinValidators: 4, MaxSharePercent: 33}
+	require.NoError(t, guard.SetRules(db, rules))
+	// a single genesis validator breaks all rules
+	require.NoError(t, guard.set.UpdateValidators(db, val("a", 1)))
+
+	steps := []struct {
+		diff  []abci.Validator
+		isErr func(error) bool
+	}{
+		// removing the last one makes it worse
+		{val("a", 0), IsTooFewValidatorsErr},
+		// a single unit of power can always change
+		{val("b", 1), nil},
+		// shares are 1/2, so 3/5 is worse
+		{val("c", 3), IsValidatorTooPowerfulErr},
+		{val("c", 1), nil},
+		{val("d", 1), nil},
+		// now the rules apply as they are
+		{val("a", 3), IsValidatorTooPowerfulErr},
+		{append(val("a", 2), val("b", 2)[0], val("c", 2)[0]), IsPowerChangeTooLargeErr},
+		{val("a", 0), IsTooFewValidatorsErr},
+		{val("e", 1), nil},
+		{val("a", 0), nil},
+	}
+	for i, step := range steps {
+		// each step in a new block
+		ctx := weave.WithHeight(context.Background(), int64(10+i))
+		err := guard.Check(ctx, db, step.diff)
+		if step.isErr != nil {
+			assert.True(t, step.isErr(err), "%d: %+v", i, err)
+			continue
+		}
+		require.NoError(t, err, "%d", i)
+		require.NoError(t, guard.set.UpdateValidators(db, step.diff), "%d", i)
+	}
+}
+
+func TestGuardNextBlock(t *testing.T) {
+	db := store.MemStore()
+	guard := NewGuard()
+	require.NoError(t, guard.SetRules(db, &Rules{MaxChangePercent: 10}))
+	require.NoError(t, guard.set.UpdateValidators(db, []abci.Validator{{
+		PubKey: testPubKey("a"),
+		Power:  100,
+	}}))
+	diff := []abci.Validator{{
+		PubKey: testPubKey("b"),
+		Power:  6,
+	}}
+
+	ctx := weave.WithHeight(context.Background(), 5)
+	require.NoError(t, guard.Check(ctx, db, diff))
+	err := guard.Check(ctx, db, diff)
+	assert.True(t, IsPowerChangeTooLargeErr(err), "%+v", err)
+
+	// the limit resets with a new block
+	ctx = weave.WithHeight(context.Background(), 6)
+	assert.NoError(t, guard.Check(ctx, db, diff))
+}
+
+// testPubKey returns a valid ed25519 pubkey, which
+// is different for every name
+func testPubKey(name string) abci.PubKey {
+	data := make([]byte, ed25519KeySize)
+	copy(data, name)
+	return abci.PubKey{Type: KeyTypeEd25519, Data: data}
+}
+
+func TestRulesValidate(t *testing.T) {
+	assert.NoError(t, (&Rules{}).Validate())
+	assert.NoError(t, (&Rules{MaxChangePercent: 100, MinValidators: 7, MaxSharePercent: 1}).Validate())
+	assert.True(t, IsInvalidRulesErr((&Rules{MaxChangePercent: 101}).Validate()))
+	assert.True(t, IsInvalidRulesErr((&Rules{MinValidators: -1}).Validate()))
+	assert.True(t, IsInvalidRulesErr((&Rules{MaxSharePercent: -2}).Validate()))
+}
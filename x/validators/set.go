@@ -0,0 +1,133 @@
+package validators
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	abci "github.com/tendermint/abci/types"
+)
+
+// SetBucketName contains the current validator set,
+// stored by pubkey
+const SetBucketName = "valset"
+
+var _ orm.CloneableData = (*Validator)(nil)
+
+// Validate requires a pubkey and a positive power,
+// as removed validators are not stored
+func (m *Validator) Validate() error {
+	if len(m.PubKey.Data) == 0 {
+		return ErrInvalidValidator("Missing pubkey")
+	}
+	if m.Power <= 0 {
+		return ErrInvalidValidator("Power must be positive")
+	}
+	return nil
+}
+
+// Copy makes a new validator with the same data
+func (m *Validator) Copy() orm.CloneableData {
+	return &Validator{
+		Address: m.Address,
+		PubKey:  m.PubKey,
+		Power:   m.Power,
+	}
+}
+
+// ValidatorFromABCI converts a tendermint validator,
+// the inverse of AsABCI
+func ValidatorFromABCI(v abci.Validator) Validator {
+	return Validator{
+		Address: v.Address,
+		PubKey: PubKey{
+			Type: v.PubKey.Type,
+			Data: v.PubKey.Data,
+		},
+		Power: v.Power,
+	}
+}
+
+// AsValidator will safely type-cast any value from the
+// validator set
+func AsValidator(obj orm.Object) *Validator {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Validator)
+}
+
+// ValidatorSet keeps the current validators, with their power,
+// in a bucket. It is passed to the StoreApp, which calls it
+// with the genesis validators and every change after that.
+type ValidatorSet struct {
+	orm.Bucket
+}
+
+var _ weave.ValidatorUpdater = ValidatorSet{}
+
+// NewValidatorSet initializes a ValidatorSet with default name
+func NewValidatorSet() ValidatorSet {
+	proto := orm.NewSimpleObj(nil, new(Validator))
+	return ValidatorSet{
+		Bucket: orm.NewBucket(SetBucketName, proto),
+	}
+}
+
+// UpdateValidators applies the diff, a power of 0
+// removes the validator
+func (s ValidatorSet) UpdateValidators(db weave.KVStore, diff []abci.Validator) error {
+	for _, d := range diff {
+		key := d.PubKey.Data
+		if len(key) == 0 {
+			return ErrInvalidValidator("Missing pubkey")
+		}
+		if d.Power == 0 {
+			err := s.Delete(db, key)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		val := ValidatorFromABCI(d)
+		err := s.Save(db, orm.NewSimpleObj(key, &val))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetValidators returns the current validator set,
+// sorted by pubkey
+func (s ValidatorSet) GetValidators(db weave.ReadOnlyKVStore) ([]abci.Validator, error) {
+	objs, err := s.All(db)
+	if err != nil {
+		return nil, err
+	}
+	vals := make([]abci.Validator, len(objs))
+	for i, obj := range objs {
+		vals[i] = AsValidator(obj).AsABCI()
+	}
+	return vals, nil
+}
+
+// GetPower returns the current power of the validator with
+// this pubkey, 0 if it is not in the set
+func (s ValidatorSet) GetPower(db weave.ReadOnlyKVStore, pubkey []byte) (int64, error) {
+	obj, err := s.Get(db, pubkey)
+	if err != nil {
+		return 0, err
+	}
+	val := AsValidator(obj)
+	if val == nil {
+		return 0, nil
+	}
+	return val.Power, nil
+}
+
+// Save enforces the proper type
+func (s ValidatorSet) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*Validator); !ok {
+		return orm.ErrInvalidObject(obj.Value())
+	}
+	return s.Bucket.Save(db, obj)
+}
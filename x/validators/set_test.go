@@ -0,0 +1,52 @@
+package validators
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave/store"
+)
+
+func TestValidatorSet(t *testing.T) {
+	db := store.MemStore()
+	set := NewValidatorSet()
+
+	val := func(key string, power int64) abci.Validator {
+		return abci.Validator{
+			PubKey: abci.PubKey{Type: "ed25519", Data: []byte(key)},
+			Power:  power,
+		}
+	}
+
+	vals, err := set.GetValidators(db)
+	require.NoError(t, err)
+	assert.Empty(t, vals)
+
+	// add some, sorted by pubkey
+	err = set.UpdateValidators(db, []abci.Validator{val("foo", 5), val("bar", 10)})
+	require.NoError(t, err)
+	vals, err = set.GetValidators(db)
+	require.NoError(t, err)
+	assert.Equal(t, []abci.Validator{val("bar", 10), val("foo", 5)}, vals)
+
+	// update, remove and add
+	err = set.UpdateValidators(db, []abci.Validator{
+		val("foo", 0), val("bar", 7), val("baz", 1), val("missing", 0)})
+	require.NoError(t, err)
+	vals, err = set.GetValidators(db)
+	require.NoError(t, err)
+	assert.Equal(t, []abci.Validator{val("bar", 7), val("baz", 1)}, vals)
+
+	obj, err := set.Get(db, []byte("bar"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), AsValidator(obj).Power)
+
+	// bad diffs are rejected
+	err = set.UpdateValidators(db, []abci.Validator{val("foo", -3)})
+	assert.True(t, IsInvalidValidatorErr(err), "%+v", err)
+	err = set.UpdateValidators(db, []abci.Validator{val("", 3)})
+	assert.True(t, IsInvalidValidatorErr(err), "%+v", err)
+}